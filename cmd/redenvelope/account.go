@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"rpcsol/redenvelope"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func runAccount(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: redenvelope account <import|list> [flags]")
+	}
+
+	switch args[0] {
+	case "import":
+		return runAccountImport(args[1:])
+	case "list":
+		return runAccountList(args[1:])
+	default:
+		return fmt.Errorf("unknown account command %q", args[0])
+	}
+}
+
+// runAccountImport import private key hex ke keystore. Key dibaca dari file
+// atau stdin supaya tidak muncul di shell history / process list.
+func runAccountImport(args []string) error {
+	fs := flag.NewFlagSet("account import", flag.ContinueOnError)
+	keystoreDir := fs.String("keystore", "keystore", "Keystore directory")
+	keyFile := fs.String("key-file", "", "File containing the hex private key (default: read from stdin)")
+	passwordEnv := fs.String("password-env", redenvelope.PassphraseEnv, "Environment variable holding the passphrase")
+	passwordFile := fs.String("password-file", "", "File containing the passphrase")
+	addOutputFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	passphrase, err := redenvelope.ResolvePassphrase(*passwordEnv, *passwordFile)
+	if err != nil {
+		return err
+	}
+
+	var keyHex string
+	if *keyFile != "" {
+		data, err := os.ReadFile(*keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to read key file: %v", err)
+		}
+		keyHex = string(data)
+	} else {
+		fmt.Fprint(os.Stderr, "Private key (hex): ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("failed to read private key: %v", err)
+		}
+		keyHex = line
+	}
+
+	account, err := redenvelope.ImportKeystoreKey(*keystoreDir, strings.TrimSpace(keyHex), passphrase)
+	if err != nil {
+		return err
+	}
+
+	result := accountResult{Address: account.Address, KeystoreFile: account.URL.Path}
+	return printResult(result, func() {
+		fmt.Printf("Imported %s\n", result.Address.Hex())
+		fmt.Printf("Keystore file: %s\n", result.KeystoreFile)
+	})
+}
+
+func runAccountList(args []string) error {
+	fs := flag.NewFlagSet("account list", flag.ContinueOnError)
+	keystoreDir := fs.String("keystore", "keystore", "Keystore directory")
+	addOutputFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	accounts, err := redenvelope.ListKeystoreAccounts(*keystoreDir)
+	if err != nil {
+		return err
+	}
+
+	result := accountListResult{Accounts: []accountResult{}}
+	for _, account := range accounts {
+		result.Accounts = append(result.Accounts, accountResult{Address: account.Address, KeystoreFile: account.URL.Path})
+	}
+	return printResult(result, func() {
+		for i, account := range result.Accounts {
+			fmt.Printf("#%d %s %s\n", i, account.Address.Hex(), account.KeystoreFile)
+		}
+	})
+}
+
+// accountResult satu account keystore di output account import / list
+type accountResult struct {
+	Address      common.Address `json:"address"`
+	KeystoreFile string         `json:"keystoreFile"`
+}
+
+// accountListResult output account list
+type accountListResult struct {
+	Accounts []accountResult `json:"accounts"`
+}
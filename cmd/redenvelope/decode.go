@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"rpcsol/redenvelope"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// runDecode decode tx hash (butuh node) atau raw input (offline)
+func runDecode(args []string) error {
+	fs := flag.NewFlagSet("decode", flag.ContinueOnError)
+	rpcURL := fs.String("rpc", defaultRPCURL, "RPC URL")
+	contract := fs.String("contract", "", "RedEnvelope contract address (with --tx)")
+	txHash := fs.String("tx", "", "Transaction hash to fetch and decode")
+	input := fs.String("input", "", "Raw calldata hex to decode")
+	addOutputFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	switch {
+	case *input != "":
+		data, err := hexutil.Decode(strings.TrimSpace(*input))
+		if err != nil {
+			return fmt.Errorf("invalid --input: %v", err)
+		}
+		call, err := redenvelope.DecodeCalldata(data)
+		if err != nil {
+			return err
+		}
+		return printResult(call, func() {
+			printCall(call)
+		})
+
+	case *txHash != "":
+		if *contract == "" {
+			return fmt.Errorf("--contract is required with --tx")
+		}
+		service, err := redenvelope.NewRedEnvelopeServiceWithSigner(*rpcURL, *contract, redenvelope.NewWatchOnlySigner(common.Address{}), redenvelope.WithLogger(logger))
+		if err != nil {
+			return err
+		}
+		defer service.Client.Close()
+
+		decoded, err := service.DecodeTransaction(common.HexToHash(*txHash))
+		if err != nil {
+			return err
+		}
+		// Value di-override supaya wei tetap string seperti schema lain
+		result := struct {
+			*redenvelope.DecodedTx
+			Value string `json:"value"`
+		}{decoded, decoded.Value.String()}
+		return printResult(result, func() {
+			printDecodedTx(decoded)
+		})
+
+	default:
+		return usageError{fmt.Errorf("either --tx or --input is required")}
+	}
+}
+
+func printCall(call *redenvelope.DecodedCall) {
+	fmt.Printf("Method: %s (%s)\n", call.Method, call.Selector)
+	for _, arg := range call.Args {
+		fmt.Printf("  %-20s %-8s %s\n", arg.Name, arg.Type, arg.Display)
+	}
+}
+
+func printDecodedTx(tx *redenvelope.DecodedTx) {
+	fmt.Printf("Tx:     %s\n", tx.Hash.Hex())
+	fmt.Printf("From:   %s\n", tx.From.Hex())
+	if tx.To != nil {
+		fmt.Printf("To:     %s\n", tx.To.Hex())
+	}
+	fmt.Printf("Value:  %s wei\n", tx.Value)
+	fmt.Printf("Nonce:  %d\n", tx.Nonce)
+	fmt.Printf("Status: %s\n", tx.Status)
+	if tx.BlockNumber > 0 {
+		fmt.Printf("Block:  %d (gas used %d)\n", tx.BlockNumber, tx.GasUsed)
+	}
+	if tx.Call != nil {
+		printCall(tx.Call)
+	}
+	for _, event := range tx.Events {
+		fmt.Printf("Event #%d %s\n", event.LogIndex, event.Name)
+		for _, field := range event.Fields {
+			fmt.Printf("  %-20s %s\n", field.Name, field.Display)
+		}
+	}
+	if tx.Failure != "" {
+		fmt.Printf("Failure: %s\n", tx.Failure)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+
+	"rpcsol/redenvelope"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// runDeploy deploy RedEnvelope lalu menulis address-nya ke network di config
+func runDeploy(args []string) error {
+	fs := flag.NewFlagSet("deploy", flag.ContinueOnError)
+	configPath := fs.String("config", "", "Config file (default $"+redenvelope.EnvConfig+" or "+redenvelope.DefaultConfigPath+")")
+	networkName := fs.String("network", "", "Network in the config (default $"+redenvelope.EnvNetwork+" or defaultNetwork)")
+	treasury := fs.String("treasury", "", "Treasury address receiving fees (default: deployer)")
+	feeBps := fs.String("fee-bps", "", "Fee in basis points, e.g. 100 for 1%")
+	artifactPath := fs.String("artifact", "", "Hardhat/Foundry artifact JSON (default: embedded artifact)")
+	keystore := addKeystoreFlags(fs)
+	addOutputFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	fee, err := strconv.ParseUint(*feeBps, 10, 16)
+	if err != nil {
+		return fmt.Errorf("invalid --fee-bps %q", *feeBps)
+	}
+
+	path := configFilePath(*configPath)
+	config, err := redenvelope.LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	network, err := config.Network(*networkName)
+	if err != nil {
+		return err
+	}
+
+	signer, err := keystore.signer()
+	if err != nil {
+		return err
+	}
+
+	treasuryAddr, err := parseAddress("treasury", *treasury)
+	if err != nil {
+		return err
+	}
+	if treasuryAddr == (common.Address{}) {
+		treasuryAddr = signer.Address()
+	}
+
+	artifact, err := redenvelope.EmbeddedArtifact()
+	if *artifactPath != "" {
+		artifact, err = redenvelope.LoadArtifact(*artifactPath)
+	}
+	if err != nil {
+		return err
+	}
+
+	service, receipt, err := redenvelope.DeployRedEnvelopeToNetwork(artifact, network, signer, treasuryAddr, uint16(fee), redenvelope.WithLogger(logger))
+	if err != nil {
+		return err
+	}
+	defer service.Client.Close()
+
+	// Hanya address dan block yang ditulis, override dari env tidak ikut tersimpan
+	stored := config.Networks[network.Name]
+	stored.ContractAddress = network.ContractAddress
+	stored.DeploymentBlock = network.DeploymentBlock
+	if err := redenvelope.SaveConfig(path, config); err != nil {
+		return fmt.Errorf("deployed at %s but failed to write config: %v", service.ContractAddress.Hex(), err)
+	}
+
+	result := deployResult{
+		ContractAddress: service.ContractAddress,
+		Network:         network.Name,
+		ChainID:         network.ChainID,
+		DeploymentBlock: network.DeploymentBlock,
+		Treasury:        treasuryAddr,
+		FeeBps:          uint16(fee),
+		Config:          path,
+		Receipt:         redenvelope.NewReceiptJSON(receipt, nil),
+	}
+	return printResult(result, func() {
+		fmt.Printf("RedEnvelope deployed to: %s\n", result.ContractAddress.Hex())
+		fmt.Printf("Network:  %s (chain %d)\n", result.Network, result.ChainID)
+		fmt.Printf("Tx:       %s\n", receipt.TxHash.Hex())
+		fmt.Printf("Block:    %d\n", result.DeploymentBlock)
+		fmt.Printf("Treasury: %s (fee %d bps)\n", result.Treasury.Hex(), result.FeeBps)
+		fmt.Printf("Config:   %s\n", result.Config)
+	})
+}
+
+// deployResult output command deploy
+type deployResult struct {
+	ContractAddress common.Address           `json:"contractAddress"`
+	Network         string                   `json:"network"`
+	ChainID         uint64                   `json:"chainId"`
+	DeploymentBlock uint64                   `json:"deploymentBlock"`
+	Treasury        common.Address           `json:"treasury"`
+	FeeBps          uint16                   `json:"feeBps"`
+	Config          string                   `json:"config"`
+	Receipt         *redenvelope.ReceiptJSON `json:"receipt"`
+}
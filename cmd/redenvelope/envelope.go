@@ -0,0 +1,518 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/big"
+	"time"
+
+	"rpcsol/redenvelope"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// runCreate membuat envelope, menunggu receipt, lalu mencetak envelope ID
+func runCreate(args []string) error {
+	fs := flag.NewFlagSet("create", flag.ContinueOnError)
+	network := addNetworkFlags(fs)
+	addOutputFlag(fs)
+	keystore := addKeystoreFlags(fs)
+	kind := fs.String("kind", "group_random", "Envelope kind: direct_fixed, group_fixed or group_random")
+	token := fs.String("token", "", "ERC-20 token address (default: native token)")
+	claims := fs.String("claims", "1", "Total claims (must be 1 for direct_fixed)")
+	amount := fs.String("amount", "", "Amount in token units, e.g. 0.5 (per claim for group_fixed, total pot otherwise); suffix wei for raw units")
+	expiry := fs.String("expiry", "24h", "Time until the envelope expires, e.g. 90m, 24h, 7d")
+	room := fs.String("room", "", "Room ID restricting who can claim (default: no restriction)")
+	recipient := fs.String("recipient", "", "Recipient address (direct_fixed only)")
+	approve := fs.Bool("approve", false, "Approve the contract for the pot first if the ERC-20 allowance is too low")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireFlag("amount", *amount); err != nil {
+		return err
+	}
+
+	kindValue, err := parseKind(*kind)
+	if err != nil {
+		return usageError{err}
+	}
+	tokenAddr, err := parseAddress("token", *token)
+	if err != nil {
+		return usageError{err}
+	}
+	totalClaims, err := parseUint32("claims", *claims)
+	if err != nil {
+		return usageError{err}
+	}
+	expiryDuration, err := parseDuration("expiry", *expiry)
+	if err != nil {
+		return usageError{err}
+	}
+	recipientAddr, err := parseAddress("recipient", *recipient)
+	if err != nil {
+		return usageError{err}
+	}
+
+	signer, err := keystore.signer()
+	if err != nil {
+		return err
+	}
+	service, err := network.service(signer, redenvelope.WithSimulation())
+	if err != nil {
+		return err
+	}
+	defer service.Client.Close()
+
+	asset, err := assetOf(service, tokenAddr)
+	if err != nil {
+		return err
+	}
+	amountWei, err := redenvelope.ParseAmount(*amount, asset.decimals)
+	if err != nil {
+		return usageError{err}
+	}
+	quote, err := service.QuoteFee(kindValue, totalClaims, amountWei)
+	if err != nil {
+		return err
+	}
+	progressf("Creating %s envelope: pot %s, fee %s (%d bps), claimable %s\n",
+		redenvelope.KindName(kindValue), asset.format(quote.GrossPot), asset.format(quote.Fee), quote.FeeBps, asset.format(quote.NetPot))
+
+	if tokenAddr != (common.Address{}) {
+		if err := ensureAllowance(service, asset, quote.GrossPot, *approve); err != nil {
+			return err
+		}
+	}
+
+	tx, err := service.CreateEnvelope(kindValue, tokenAddr, totalClaims, amountWei, expiryDuration, parseRoom(*room), recipientAddr)
+	if err != nil {
+		return err
+	}
+	result, err := waitReceipt(service, tx)
+	if err != nil {
+		return err
+	}
+
+	id, err := service.EnvelopeIDFromReceipt(result.receipt)
+	if err != nil {
+		return err
+	}
+	result.EnvelopeID = id.String()
+	result.Quote = redenvelope.NewFeeQuoteJSON(quote)
+	return printResult(result, func() {
+		fmt.Printf("Envelope ID: %s\n", id)
+	})
+}
+
+// runClaim klaim envelope dan mencetak payout
+func runClaim(args []string) error {
+	fs := flag.NewFlagSet("claim", flag.ContinueOnError)
+	network := addNetworkFlags(fs)
+	addOutputFlag(fs)
+	keystore := addKeystoreFlags(fs)
+	envelope := fs.String("envelope", "", "Envelope ID")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	id, err := envelopeIDFlag(*envelope)
+	if err != nil {
+		return err
+	}
+
+	signer, err := keystore.signer()
+	if err != nil {
+		return err
+	}
+	service, err := network.service(signer, redenvelope.WithSimulation())
+	if err != nil {
+		return err
+	}
+	defer service.Client.Close()
+
+	tx, err := service.ClaimEnvelope(id)
+	if err != nil {
+		return err
+	}
+	result, err := waitReceipt(service, tx)
+	if err != nil {
+		return err
+	}
+	return printResult(result, func() {
+		printEvents(service, result.events)
+	})
+}
+
+// runRefund refund envelope yang sudah expired
+func runRefund(args []string) error {
+	fs := flag.NewFlagSet("refund", flag.ContinueOnError)
+	network := addNetworkFlags(fs)
+	addOutputFlag(fs)
+	keystore := addKeystoreFlags(fs)
+	envelope := fs.String("envelope", "", "Envelope ID")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	id, err := envelopeIDFlag(*envelope)
+	if err != nil {
+		return err
+	}
+
+	signer, err := keystore.signer()
+	if err != nil {
+		return err
+	}
+	service, err := network.service(signer, redenvelope.WithSimulation())
+	if err != nil {
+		return err
+	}
+	defer service.Client.Close()
+
+	tx, err := service.RefundEnvelope(id)
+	if err != nil {
+		return err
+	}
+	result, err := waitReceipt(service, tx)
+	if err != nil {
+		return err
+	}
+	return printResult(result, func() {
+		printEvents(service, result.events)
+	})
+}
+
+// runGet menampilkan isi envelope
+func runGet(args []string) error {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	network := addNetworkFlags(fs)
+	addOutputFlag(fs)
+	envelope := fs.String("envelope", "", "Envelope ID")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	id, err := envelopeIDFlag(*envelope)
+	if err != nil {
+		return err
+	}
+
+	service, err := network.readService()
+	if err != nil {
+		return err
+	}
+	defer service.Client.Close()
+
+	env, err := service.GetEnvelope(id)
+	if err != nil {
+		return err
+	}
+	if env.Creator == (common.Address{}) {
+		return fmt.Errorf("envelope %s: %w", id, redenvelope.ErrEnvelopeNotFound)
+	}
+	asset, err := assetOf(service, env.Token)
+	if err != nil {
+		return err
+	}
+
+	return printResult(redenvelope.NewEnvelopeJSON(id, env), func() {
+		printEnvelope(id, env, asset)
+	})
+}
+
+// printEnvelope output text command get
+func printEnvelope(id *big.Int, env *redenvelope.Envelope, asset *asset) {
+	expiry := time.Unix(int64(env.Expiry), 0)
+	status := "active"
+	if time.Now().After(expiry) {
+		status = "expired"
+	}
+
+	fmt.Printf("Envelope %s (%s)\n", id, status)
+	fmt.Printf("  Kind:      %s\n", redenvelope.KindName(env.Kind))
+	fmt.Printf("  Creator:   %s\n", env.Creator.Hex())
+	fmt.Printf("  Asset:     %s\n", asset.name())
+	fmt.Printf("  Per claim: %s\n", asset.format(env.AmountPerClaim))
+	fmt.Printf("  Remaining: %s\n", asset.format(env.RemainingAmount))
+	fmt.Printf("  Claims:    %d of %d left\n", env.RemainingClaims, env.TotalClaims)
+	fmt.Printf("  Expiry:    %s\n", expiry.Format(time.RFC3339))
+	if env.RoomIdHash != redenvelope.EmptyRoomIdHash {
+		fmt.Printf("  Room hash: %s\n", common.Hash(env.RoomIdHash).Hex())
+	}
+	if env.Recipient != (common.Address{}) {
+		fmt.Printf("  Recipient: %s\n", env.Recipient.Hex())
+	}
+}
+
+// runHasClaimed cek status klaim sebuah address
+func runHasClaimed(args []string) error {
+	fs := flag.NewFlagSet("has-claimed", flag.ContinueOnError)
+	network := addNetworkFlags(fs)
+	addOutputFlag(fs)
+	envelope := fs.String("envelope", "", "Envelope ID")
+	address := fs.String("address", "", "Address to check")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	id, err := envelopeIDFlag(*envelope)
+	if err != nil {
+		return err
+	}
+	if err := requireFlag("address", *address); err != nil {
+		return err
+	}
+	user, err := parseAddress("address", *address)
+	if err != nil {
+		return usageError{err}
+	}
+
+	service, err := network.readService()
+	if err != nil {
+		return err
+	}
+	defer service.Client.Close()
+
+	claimed, err := service.HasClaimed(id, user)
+	if err != nil {
+		return err
+	}
+	result := struct {
+		EnvelopeID string         `json:"envelopeId"`
+		Address    common.Address `json:"address"`
+		Claimed    bool           `json:"claimed"`
+	}{id.String(), user, claimed}
+	return printResult(result, func() {
+		fmt.Println(claimed)
+	})
+}
+
+// runNextID mencetak envelope ID berikutnya
+func runNextID(args []string) error {
+	fs := flag.NewFlagSet("next-id", flag.ContinueOnError)
+	network := addNetworkFlags(fs)
+	addOutputFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	service, err := network.readService()
+	if err != nil {
+		return err
+	}
+	defer service.Client.Close()
+
+	id, err := service.GetNextEnvelopeId()
+	if err != nil {
+		return err
+	}
+	result := struct {
+		NextEnvelopeID string `json:"nextEnvelopeId"`
+	}{id.String()}
+	return printResult(result, func() {
+		fmt.Println(id)
+	})
+}
+
+// runFee menampilkan fee, treasury, dan quote kalau --amount diisi
+func runFee(args []string) error {
+	fs := flag.NewFlagSet("fee", flag.ContinueOnError)
+	network := addNetworkFlags(fs)
+	addOutputFlag(fs)
+	kind := fs.String("kind", "group_random", "Envelope kind for the quote")
+	token := fs.String("token", "", "ERC-20 token address for the quote (default: native token)")
+	claims := fs.String("claims", "1", "Total claims for the quote")
+	amount := fs.String("amount", "", "Amount to quote, same meaning as in create")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	service, err := network.readService()
+	if err != nil {
+		return err
+	}
+	defer service.Client.Close()
+
+	feeBps, err := service.GetFeeBps()
+	if err != nil {
+		return err
+	}
+	treasury, err := service.GetTreasury()
+	if err != nil {
+		return err
+	}
+	result := feeResult{FeeBps: feeBps, Treasury: treasury}
+	if *amount == "" {
+		return printResult(result, result.print)
+	}
+
+	kindValue, err := parseKind(*kind)
+	if err != nil {
+		return usageError{err}
+	}
+	tokenAddr, err := parseAddress("token", *token)
+	if err != nil {
+		return usageError{err}
+	}
+	totalClaims, err := parseUint32("claims", *claims)
+	if err != nil {
+		return usageError{err}
+	}
+	asset, err := assetOf(service, tokenAddr)
+	if err != nil {
+		return err
+	}
+	amountWei, err := redenvelope.ParseAmount(*amount, asset.decimals)
+	if err != nil {
+		return usageError{err}
+	}
+
+	quote := redenvelope.QuoteFee(kindValue, totalClaims, amountWei, feeBps)
+	result.Quote = redenvelope.NewFeeQuoteJSON(quote)
+	return printResult(result, func() {
+		result.print()
+		fmt.Printf("Pot:      %s\n", asset.format(quote.GrossPot))
+		fmt.Printf("Fee:      %s\n", asset.format(quote.Fee))
+		fmt.Printf("Net pot:  %s\n", asset.format(quote.NetPot))
+	})
+}
+
+// feeResult output command fee
+type feeResult struct {
+	FeeBps   uint16                    `json:"feeBps"`
+	Treasury common.Address            `json:"treasury"`
+	Quote    *redenvelope.FeeQuoteJSON `json:"quote,omitempty"`
+}
+
+func (r feeResult) print() {
+	fmt.Printf("Fee:      %d bps (%s%%)\n", r.FeeBps, redenvelope.FormatAmount(big.NewInt(int64(r.FeeBps)), 2))
+	fmt.Printf("Treasury: %s\n", r.Treasury.Hex())
+}
+
+// envelopeIDFlag parse --envelope yang wajib diisi
+func envelopeIDFlag(value string) (*big.Int, error) {
+	if err := requireFlag("envelope", value); err != nil {
+		return nil, err
+	}
+	id, err := parseBigInt("envelope", value)
+	if err != nil {
+		return nil, usageError{err}
+	}
+	return id, nil
+}
+
+// txResult output command yang mengirim transaksi
+type txResult struct {
+	Transaction *redenvelope.TxJSON       `json:"transaction"`
+	Receipt     *redenvelope.ReceiptJSON  `json:"receipt"`
+	EnvelopeID  string                    `json:"envelopeId,omitempty"` // create
+	Quote       *redenvelope.FeeQuoteJSON `json:"quote,omitempty"`      // create
+
+	receipt *types.Receipt
+	events  []redenvelope.EnvelopeEvent
+}
+
+// waitReceipt menunggu tx sesuai confirmations network, revert dikembalikan
+// sebagai error contract yang sudah di-decode
+func waitReceipt(service *redenvelope.RedEnvelopeService, tx *types.Transaction) (*txResult, error) {
+	progressf("Tx %s sent, waiting for %d confirmation(s)...\n", tx.Hash().Hex(), service.Confirmations)
+	receipt, err := service.WaitMined(context.Background(), tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := service.ReceiptError(tx, receipt); err != nil {
+		return nil, err
+	}
+	progressf("Mined in block %d (gas used %d)\n", receipt.BlockNumber, receipt.GasUsed)
+
+	events, err := service.EventsFromReceipt(receipt)
+	if err != nil {
+		return nil, err
+	}
+	return &txResult{
+		Transaction: redenvelope.NewTxJSON(tx, service.Address),
+		Receipt:     redenvelope.NewReceiptJSON(receipt, events),
+		receipt:     receipt,
+		events:      events,
+	}, nil
+}
+
+// ensureAllowance memastikan contract boleh menarik required dari token
+// sender. Tanpa --approve gagal lebih awal, sebelum simulasi create revert
+// di transferFrom.
+func ensureAllowance(service *redenvelope.RedEnvelopeService, asset *asset, required *big.Int, approve bool) error {
+	allowance, err := service.TokenAllowance(asset.token, service.Address, service.ContractAddress)
+	if err != nil {
+		return err
+	}
+	if allowance.Cmp(required) >= 0 {
+		return nil
+	}
+	if !approve {
+		return fmt.Errorf("%w; rerun with --approve or approve the contract first", &redenvelope.InsufficientFundsError{
+			Asset:     "erc20",
+			Token:     asset.token,
+			Allowance: true,
+			Decimals:  asset.decimals,
+			Required:  required,
+			Available: allowance,
+			Missing:   new(big.Int).Sub(required, allowance),
+		})
+	}
+
+	progressf("Approving the contract to spend %s of %s\n", asset.format(required), asset.name())
+	tx, err := service.ApproveToken(asset.token, required)
+	if err != nil {
+		return err
+	}
+	progressf("Tx %s sent, waiting for %d confirmation(s)...\n", tx.Hash().Hex(), service.Confirmations)
+	receipt, err := service.WaitMined(context.Background(), tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("approve tx %s reverted", tx.Hash().Hex())
+	}
+	return nil
+}
+
+// printEvents mencetak event RedEnvelope dari receipt
+func printEvents(service *redenvelope.RedEnvelopeService, events []redenvelope.EnvelopeEvent) {
+	assets := assetCache{service: service}
+	for _, event := range events {
+		if err := printEvent(&assets, event); err != nil {
+			logger.Warn("failed to format event", "event", event.Name, "envelopeId", event.EnvelopeID, "error", err)
+		}
+	}
+}
+
+// asset native token atau ERC-20 beserta decimals untuk format amount
+type asset struct {
+	token    common.Address
+	symbol   string
+	decimals uint8
+}
+
+// assetOf info asset; decimals ERC-20 dibaca dari token contract
+func assetOf(service *redenvelope.RedEnvelopeService, token common.Address) (*asset, error) {
+	if token == (common.Address{}) {
+		return &asset{symbol: service.Network.Symbol(), decimals: redenvelope.NativeDecimals}, nil
+	}
+	decimals, err := service.TokenDecimals(token)
+	if err != nil {
+		return nil, err
+	}
+	return &asset{token: token, decimals: decimals}, nil
+}
+
+func (a *asset) name() string {
+	if a.token == (common.Address{}) {
+		return a.symbol
+	}
+	return "ERC-20 " + a.token.Hex()
+}
+
+func (a *asset) format(amount *big.Int) string {
+	if a.token == (common.Address{}) {
+		return redenvelope.FormatAmount(amount, a.decimals) + " " + a.symbol
+	}
+	return redenvelope.FormatAmount(amount, a.decimals) + " tokens"
+}
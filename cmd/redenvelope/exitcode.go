@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"flag"
+
+	"rpcsol/redenvelope"
+)
+
+// Exit code CLI. Revert yang dikenali mendapat kode sendiri supaya script
+// bisa membedakan, misalnya, AlreadyClaimed dari kegagalan jaringan.
+const (
+	exitOK      = 0
+	exitFailure = 1 // Error lain (RPC, keystore, config, ...)
+	exitUsage   = 2 // Flag salah atau kurang
+
+	exitAlreadyClaimed    = 10
+	exitEnvelopeExpired   = 11
+	exitEnvelopeNotFound  = 12
+	exitInvalidParameters = 13
+	exitNotEligible       = 14
+	exitTransferFailed    = 15
+	exitUnauthorized      = 16
+	exitReverted          = 19 // Revert lain (require string, panic, error tak dikenal)
+
+	exitInsufficientFunds = 20
+	exitChainMismatch     = 21
+	exitGasPriceTooHigh   = 22
+)
+
+var contractExitCodes = map[*redenvelope.ContractError]int{
+	redenvelope.ErrAlreadyClaimed:    exitAlreadyClaimed,
+	redenvelope.ErrEnvelopeExpired:   exitEnvelopeExpired,
+	redenvelope.ErrEnvelopeNotFound:  exitEnvelopeNotFound,
+	redenvelope.ErrInvalidParameters: exitInvalidParameters,
+	redenvelope.ErrNotEligible:       exitNotEligible,
+	redenvelope.ErrTransferFailed:    exitTransferFailed,
+	redenvelope.ErrUnauthorized:      exitUnauthorized,
+}
+
+// usageError error karena pemakaian CLI yang salah
+type usageError struct{ err error }
+
+func (e usageError) Error() string { return e.err.Error() }
+func (e usageError) Unwrap() error { return e.err }
+
+// exitCode kode exit untuk error hasil subcommand
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	var usage usageError
+	if errors.Is(err, flag.ErrHelp) || errors.As(err, &usage) {
+		return exitUsage
+	}
+
+	var contractErr *redenvelope.ContractError
+	if errors.As(err, &contractErr) {
+		if code, ok := contractExitCodes[contractErr]; ok {
+			return code
+		}
+		return exitReverted
+	}
+
+	var fundsErr *redenvelope.InsufficientFundsError
+	var chainErr *redenvelope.ChainIDMismatchError
+	switch {
+	case errors.As(err, &fundsErr):
+		return exitInsufficientFunds
+	case errors.As(err, &chainErr):
+		return exitChainMismatch
+	case errors.Is(err, redenvelope.ErrGasPriceTooHigh):
+		return exitGasPriceTooHigh
+	}
+	return exitFailure
+}
+
+// parseFlags fs.Parse dengan error parse dianggap usage error
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	logs := addLogFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{err}
+	}
+	if err := logs.apply(); err != nil {
+		return usageError{err}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"rpcsol/redenvelope"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// defaultRPCURL Hardhat local node
+const defaultRPCURL = "http://127.0.0.1:8545"
+
+// configFilePath path config dari flag, $REDENVELOPE_CONFIG, atau default
+func configFilePath(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	if path := os.Getenv(redenvelope.EnvConfig); path != "" {
+		return path
+	}
+	return redenvelope.DefaultConfigPath
+}
+
+// keystoreFlags flag untuk load signer dari keystore V3
+type keystoreFlags struct {
+	keyfile      *string
+	passwordEnv  *string
+	passwordFile *string
+}
+
+func addKeystoreFlags(fs *flag.FlagSet) *keystoreFlags {
+	return &keystoreFlags{
+		keyfile:      fs.String("keyfile", "", "Keystore V3 file used for signing"),
+		passwordEnv:  fs.String("password-env", redenvelope.PassphraseEnv, "Environment variable holding the keystore passphrase"),
+		passwordFile: fs.String("password-file", "", "File containing the keystore passphrase"),
+	}
+}
+
+func (k *keystoreFlags) signer() (*redenvelope.KeySigner, error) {
+	if *k.keyfile == "" {
+		return nil, fmt.Errorf("--keyfile is required")
+	}
+	passphrase, err := redenvelope.ResolvePassphrase(*k.passwordEnv, *k.passwordFile)
+	if err != nil {
+		return nil, err
+	}
+	return redenvelope.NewKeystoreSigner(*k.keyfile, passphrase)
+}
+
+// parseKind menerima nama kind (direct_fixed, group_fixed, group_random) atau angka 0-2
+func parseKind(value string) (uint8, error) {
+	switch strings.ToLower(strings.ReplaceAll(value, "-", "_")) {
+	case "direct_fixed", "direct", "0":
+		return redenvelope.DIRECT_FIXED, nil
+	case "group_fixed", "fixed", "1":
+		return redenvelope.GROUP_FIXED, nil
+	case "group_random", "random", "2":
+		return redenvelope.GROUP_RANDOM, nil
+	default:
+		return 0, fmt.Errorf("invalid envelope kind %q", value)
+	}
+}
+
+// parseBigInt parse bilangan bulat desimal (atau 0x hex)
+func parseBigInt(name, value string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(value, 0)
+	if !ok || n.Sign() < 0 {
+		return nil, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return n, nil
+}
+
+// parseAddress parse address hex, string kosong menjadi zero address
+func parseAddress(name, value string) (common.Address, error) {
+	if value == "" {
+		return common.Address{}, nil
+	}
+	if !common.IsHexAddress(value) {
+		return common.Address{}, fmt.Errorf("invalid %s address %q", name, value)
+	}
+	return common.HexToAddress(value), nil
+}
+
+// parseRoom room ID string -> roomIdHash, kosong berarti tanpa room restriction
+func parseRoom(room string) [32]byte {
+	if room == "" {
+		return redenvelope.EmptyRoomIdHash
+	}
+	return redenvelope.GenerateRoomIdHash(room)
+}
+
+// parseUint32 parse angka uint32
+func parseUint32(name, value string) (uint32, error) {
+	n, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return uint32(n), nil
+}
+
+// networkFlags flag untuk memilih network dari config
+type networkFlags struct {
+	config  *string
+	network *string
+}
+
+func addNetworkFlags(fs *flag.FlagSet) *networkFlags {
+	return &networkFlags{
+		config:  fs.String("config", "", "Config file (default $"+redenvelope.EnvConfig+" or "+redenvelope.DefaultConfigPath+")"),
+		network: fs.String("network", "", "Network in the config (default $"+redenvelope.EnvNetwork+" or defaultNetwork)"),
+	}
+}
+
+// resolve NetworkConfig yang dipilih, sudah termasuk override dari env
+func (n *networkFlags) resolve() (*redenvelope.NetworkConfig, error) {
+	config, err := redenvelope.LoadConfig(configFilePath(*n.config))
+	if err != nil {
+		return nil, err
+	}
+	return config.Network(*n.network)
+}
+
+// service membuat service untuk network terpilih
+func (n *networkFlags) service(signer redenvelope.Signer, opts ...redenvelope.Option) (*redenvelope.RedEnvelopeService, error) {
+	network, err := n.resolve()
+	if err != nil {
+		return nil, err
+	}
+	return redenvelope.NewRedEnvelopeServiceForNetwork(network, signer, append(opts, redenvelope.WithLogger(logger))...)
+}
+
+// readService service read-only, tanpa keystore
+func (n *networkFlags) readService() (*redenvelope.RedEnvelopeService, error) {
+	return n.service(redenvelope.NewWatchOnlySigner(common.Address{}))
+}
+
+// parseDuration seperti time.ParseDuration, ditambah suffix "d" untuk hari
+func parseDuration(name, value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.ParseUint(days, 10, 16)
+		if err != nil || n == 0 {
+			return 0, fmt.Errorf("invalid %s %q", name, value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return d, nil
+}
+
+// requireFlag error usage kalau flag wajib kosong
+func requireFlag(name, value string) error {
+	if value == "" {
+		return usageError{fmt.Errorf("--%s is required", name)}
+	}
+	return nil
+}
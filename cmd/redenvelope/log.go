@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"rpcsol/redenvelope"
+)
+
+// logger log proses CLI ke stderr, diatur parseFlags dari --log-level dan
+// --log-format. stdout tetap hanya berisi output command.
+var logger = newLogger(os.Stderr, slog.LevelWarn, "text")
+
+// defaultLogLevel level kalau --log-level tidak diisi; main menaikkannya ke
+// info untuk command yang berjalan lama (serve, watch)
+var defaultLogLevel = slog.LevelWarn
+
+// newLogger logger slog dengan key material disensor
+func newLogger(w io.Writer, level slog.Leveler, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: redenvelope.RedactAttr}
+	if format == "json" {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// logFlags --log-level dan --log-format, didaftarkan di setiap subcommand
+type logFlags struct {
+	level  *string
+	format *string
+}
+
+func addLogFlags(fs *flag.FlagSet) *logFlags {
+	return &logFlags{
+		level:  fs.String("log-level", "", "Log level: debug, info, warn or error (default warn, info for serve and watch)"),
+		format: fs.String("log-format", "text", "Log format on stderr: text or json"),
+	}
+}
+
+// apply memasang logger sesuai flag, juga sebagai slog default
+func (f *logFlags) apply() error {
+	level := defaultLogLevel
+	if *f.level != "" {
+		if err := level.UnmarshalText([]byte(*f.level)); err != nil {
+			return fmt.Errorf("invalid log-level %q", *f.level)
+		}
+	}
+	format := strings.ToLower(*f.format)
+	if format != "text" && format != "json" {
+		return fmt.Errorf("invalid log-format %q: must be text or json", *f.format)
+	}
+	logger = newLogger(os.Stderr, level, format)
+	slog.SetDefault(logger)
+	return nil
+}
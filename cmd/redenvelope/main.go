@@ -0,0 +1,77 @@
+// Command redenvelope adalah CLI untuk tooling RedEnvelope contract.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// command satu subcommand CLI
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+
+	// longRunning command yang log level default-nya info
+	longRunning bool
+}
+
+var commands = []command{
+	{name: "create", usage: "Create an envelope (native or ERC-20)", run: runCreate},
+	{name: "claim", usage: "Claim an envelope", run: runClaim},
+	{name: "refund", usage: "Refund an expired envelope", run: runRefund},
+	{name: "get", usage: "Show an envelope", run: runGet},
+	{name: "has-claimed", usage: "Check whether an address has claimed an envelope", run: runHasClaimed},
+	{name: "next-id", usage: "Show the next envelope ID", run: runNextID},
+	{name: "fee", usage: "Show the fee settings and quote the fee for an envelope", run: runFee},
+	{name: "watch", usage: "Stream envelope events", run: runWatch, longRunning: true},
+	{name: "serve", usage: "Run the REST API server", run: runServe, longRunning: true},
+	{name: "account", usage: "Manage keystore accounts (import, list)", run: runAccount},
+	{name: "offline", usage: "Build, sign (air-gapped) and broadcast transactions", run: runOffline},
+	{name: "deploy", usage: "Deploy RedEnvelope and write its address into the config", run: runDeploy},
+	{name: "decode", usage: "Decode a RedEnvelope transaction or calldata", run: runDecode},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(exitUsage)
+	}
+
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage()
+		return
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if cmd.longRunning {
+				defaultLogLevel = slog.LevelInfo
+			}
+			if err := cmd.run(os.Args[2:]); err != nil {
+				if !errors.Is(err, flag.ErrHelp) {
+					printError(err)
+				}
+				os.Exit(exitCode(err))
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	printUsage()
+	os.Exit(exitUsage)
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: redenvelope <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", cmd.name, cmd.usage)
+	}
+}
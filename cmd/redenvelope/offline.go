@@ -0,0 +1,196 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"rpcsol/redenvelope"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func runOffline(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: redenvelope offline <build|sign|broadcast> [flags]")
+	}
+
+	switch args[0] {
+	case "build":
+		return runOfflineBuild(args[1:])
+	case "sign":
+		return runOfflineSign(args[1:])
+	case "broadcast":
+		return runOfflineBroadcast(args[1:])
+	default:
+		return fmt.Errorf("unknown offline command %q", args[0])
+	}
+}
+
+// runOfflineBuild (online) membangun tx unsigned ke file JSON
+func runOfflineBuild(args []string) error {
+	fs := flag.NewFlagSet("offline build", flag.ContinueOnError)
+	rpcURL := fs.String("rpc", defaultRPCURL, "RPC URL")
+	contract := fs.String("contract", "", "RedEnvelope contract address")
+	from := fs.String("from", "", "Address that will sign the transaction offline")
+	method := fs.String("method", "", "create, claim or refund")
+	out := fs.String("out", "unsigned-tx.json", "Output file")
+	kind := fs.String("kind", "group_fixed", "Envelope kind (create)")
+	token := fs.String("token", "", "ERC-20 token address, empty for native (create)")
+	claims := fs.String("claims", "1", "Total claims (create)")
+	amount := fs.String("amount", "", "Amount in wei: per claim for group_fixed, total otherwise (create)")
+	expiry := fs.Duration("expiry", 0, "Expiry duration from now, e.g. 24h (create)")
+	room := fs.String("room", "", "Room ID, empty for no room restriction (create)")
+	recipient := fs.String("recipient", "", "Recipient address for direct_fixed (create)")
+	envelopeID := fs.String("envelope", "", "Envelope ID (claim, refund)")
+	addOutputFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	fromAddr, err := parseAddress("from", *from)
+	if err != nil {
+		return err
+	}
+	if *contract == "" || fromAddr == (common.Address{}) {
+		return fmt.Errorf("--contract and --from are required")
+	}
+
+	service, err := redenvelope.NewRedEnvelopeServiceWithSigner(*rpcURL, *contract, redenvelope.NewWatchOnlySigner(fromAddr), redenvelope.WithLogger(logger))
+	if err != nil {
+		return err
+	}
+	defer service.Client.Close()
+
+	var tx *redenvelope.OfflineTx
+	switch *method {
+	case "create":
+		kindValue, err := parseKind(*kind)
+		if err != nil {
+			return err
+		}
+		tokenAddr, err := parseAddress("token", *token)
+		if err != nil {
+			return err
+		}
+		totalClaims, err := parseUint32("claims", *claims)
+		if err != nil {
+			return err
+		}
+		amountWei, err := parseBigInt("amount", *amount)
+		if err != nil {
+			return err
+		}
+		recipientAddr, err := parseAddress("recipient", *recipient)
+		if err != nil {
+			return err
+		}
+		tx, err = service.BuildOfflineCreate(kindValue, tokenAddr, totalClaims, amountWei, *expiry, parseRoom(*room), recipientAddr)
+		if err != nil {
+			return err
+		}
+	case "claim", "refund":
+		id, err := parseBigInt("envelope", *envelopeID)
+		if err != nil {
+			return err
+		}
+		if *method == "claim" {
+			tx, err = service.BuildOfflineClaim(id)
+		} else {
+			tx, err = service.BuildOfflineRefund(id)
+		}
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("--method must be create, claim or refund")
+	}
+
+	if err := redenvelope.WriteOfflineTx(*out, tx); err != nil {
+		return err
+	}
+	return printResult(offlineResult{File: *out, OfflineTx: tx}, func() {
+		fmt.Printf("Unsigned %s tx (nonce %d, chain %s) written to %s\n", tx.Intent.Method, tx.Nonce, tx.ChainID.ToInt(), *out)
+	})
+}
+
+// runOfflineSign (air-gapped) menandatangani file dengan keystore
+func runOfflineSign(args []string) error {
+	fs := flag.NewFlagSet("offline sign", flag.ContinueOnError)
+	in := fs.String("in", "unsigned-tx.json", "Unsigned tx file")
+	out := fs.String("out", "signed-tx.json", "Output file")
+	chainID := fs.String("chain-id", "", "Expected chain ID (required)")
+	keys := addKeystoreFlags(fs)
+	addOutputFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	expected, err := parseBigInt("chain-id", *chainID)
+	if err != nil {
+		return fmt.Errorf("--chain-id is required: %v", err)
+	}
+
+	tx, err := redenvelope.ReadOfflineTx(*in)
+	if err != nil {
+		return err
+	}
+	signer, err := keys.signer()
+	if err != nil {
+		return err
+	}
+
+	progressf("Signing %s %v\n", tx.Intent.Method, tx.Intent.Args)
+	progressf("  from %s to %s, value %s wei, nonce %d\n", tx.From.Hex(), tx.To.Hex(), tx.Value.ToInt(), tx.Nonce)
+
+	if err := redenvelope.SignOfflineTx(tx, signer, expected); err != nil {
+		return err
+	}
+	if err := redenvelope.WriteOfflineTx(*out, tx); err != nil {
+		return err
+	}
+	return printResult(offlineResult{File: *out, OfflineTx: tx}, func() {
+		fmt.Printf("Signed tx %s written to %s\n", tx.Hash.Hex(), *out)
+	})
+}
+
+// runOfflineBroadcast (online) mengirim file yang sudah signed
+func runOfflineBroadcast(args []string) error {
+	fs := flag.NewFlagSet("offline broadcast", flag.ContinueOnError)
+	rpcURL := fs.String("rpc", defaultRPCURL, "RPC URL")
+	contract := fs.String("contract", "", "RedEnvelope contract address")
+	in := fs.String("in", "signed-tx.json", "Signed tx file")
+	addOutputFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	tx, err := redenvelope.ReadOfflineTx(*in)
+	if err != nil {
+		return err
+	}
+	if *contract == "" {
+		return fmt.Errorf("--contract is required")
+	}
+
+	service, err := redenvelope.NewRedEnvelopeServiceWithSigner(*rpcURL, *contract, redenvelope.NewWatchOnlySigner(tx.From), redenvelope.WithLogger(logger))
+	if err != nil {
+		return err
+	}
+	defer service.Client.Close()
+
+	sent, err := service.BroadcastOfflineTx(tx)
+	if err != nil {
+		return err
+	}
+	result := offlineResult{OfflineTx: tx, Transaction: redenvelope.NewTxJSON(sent, tx.From)}
+	return printResult(result, func() {
+		fmt.Printf("Broadcast %s tx %s\n", tx.Intent.Method, sent.Hash().Hex())
+	})
+}
+
+// offlineResult output offline build, sign dan broadcast
+type offlineResult struct {
+	File        string                 `json:"file,omitempty"` // File yang ditulis (build, sign)
+	OfflineTx   *redenvelope.OfflineTx `json:"offlineTx"`
+	Transaction *redenvelope.TxJSON    `json:"transaction,omitempty"` // broadcast
+}
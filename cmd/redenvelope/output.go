@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"rpcsol/redenvelope"
+)
+
+// outputMode format output CLI
+type outputMode string
+
+const (
+	outputText outputMode = "text"
+	outputJSON outputMode = "json"
+)
+
+func (m *outputMode) String() string { return string(*m) }
+
+func (m *outputMode) Set(value string) error {
+	switch outputMode(value) {
+	case outputText, outputJSON:
+		*m = outputMode(value)
+		return nil
+	}
+	return fmt.Errorf("must be text or json")
+}
+
+// output format yang dipilih subcommand. Satu proses hanya menjalankan satu
+// subcommand, jadi cukup satu variabel; main memakainya untuk format error.
+var output = outputText
+
+// addOutputFlag mendaftarkan --output di subcommand
+func addOutputFlag(fs *flag.FlagSet) {
+	fs.Var(&output, "output", "Output format: text or json")
+}
+
+// progressf pesan progres. Di mode json ditulis ke stderr supaya stdout
+// hanya berisi JSON.
+func progressf(format string, args ...interface{}) {
+	if output == outputJSON {
+		fmt.Fprintf(os.Stderr, format, args...)
+		return
+	}
+	fmt.Printf(format, args...)
+}
+
+// printResult menulis v sebagai satu baris JSON di mode json, atau
+// menjalankan text di mode text
+func printResult(v interface{}, text func()) error {
+	if output != outputJSON {
+		text()
+		return nil
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(v); err != nil {
+		return fmt.Errorf("failed to write JSON output: %v", err)
+	}
+	return nil
+}
+
+// errorOutput objek error di mode json: {"error": {"code": ..., "message": ...}}
+type errorOutput struct {
+	Error *redenvelope.ErrorJSON `json:"error"`
+}
+
+// printError menulis error sesuai output mode
+func printError(err error) {
+	if output != outputJSON {
+		fmt.Fprintf(os.Stderr, "error: %s\n", redenvelope.ExplainError(err))
+		return
+	}
+
+	out := redenvelope.NewErrorJSON(err)
+	var usage usageError
+	if errors.As(err, &usage) {
+		out.Code = redenvelope.ErrorCodeInvalidArgument
+	}
+	json.NewEncoder(os.Stdout).Encode(errorOutput{Error: out})
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"rpcsol/metrics"
+	"rpcsol/redenvelope"
+	"rpcsol/server"
+	"rpcsol/webhook"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// runServe menjalankan REST API sampai Ctrl+C
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	network := addNetworkFlags(fs)
+	keystore := addKeystoreFlags(fs)
+	addr := fs.String("addr", ":8080", "HTTP listen address")
+	index := fs.Bool("index", true, "Index envelope events to serve GET /envelopes and event subscriptions")
+	jsonrpc := fs.Bool("rpc", true, "Serve the JSON-RPC gateway on /rpc (HTTP and websocket)")
+	webhooks := fs.String("webhooks", "", "Webhook store file; enables /webhooks and /deliveries (requires --index)")
+	webhookNetworks := fs.String("webhook-allow-networks", "", "Comma-separated CIDRs that webhooks may target besides public addresses (e.g. 127.0.0.0/8 for local receivers)")
+	wsOrigins := fs.String("ws-origins", "", "Comma-separated allowed websocket origins for /rpc (\"*\" for any)")
+	authDomain := fs.String("auth-domain", "", "Require Sign-In With Ethereum for writes; domain expected in SIWE messages")
+	operators := fs.String("operators", "", "Comma-separated addresses allowed to call /admin endpoints besides the contract owner")
+	metricsOn := fs.Bool("metrics", true, "Serve Prometheus metrics on /metrics")
+	sessionTTL := fs.String("session-ttl", "24h", "Lifetime of a SIWE session token (e.g. 12h, 7d)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *webhooks != "" && !*index {
+		return usageError{fmt.Errorf("--webhooks requires --index")}
+	}
+	var allowedNetworks []netip.Prefix
+	if *webhookNetworks != "" {
+		if *webhooks == "" {
+			return usageError{fmt.Errorf("--webhook-allow-networks requires --webhooks")}
+		}
+		for _, value := range strings.Split(*webhookNetworks, ",") {
+			network, err := netip.ParsePrefix(strings.TrimSpace(value))
+			if err != nil {
+				return usageError{fmt.Errorf("invalid --webhook-allow-networks %q: %v", value, err)}
+			}
+			allowedNetworks = append(allowedNetworks, network)
+		}
+	}
+	var authConfig *server.AuthConfig
+	if *authDomain != "" {
+		ttl, err := parseDuration("session-ttl", *sessionTTL)
+		if err != nil {
+			return usageError{err}
+		}
+		authConfig = &server.AuthConfig{Domain: *authDomain, SessionTTL: ttl}
+		if *operators != "" {
+			for _, value := range strings.Split(*operators, ",") {
+				operator, err := parseAddress("operator", strings.TrimSpace(value))
+				if err != nil {
+					return usageError{err}
+				}
+				authConfig.Operators = append(authConfig.Operators, operator)
+			}
+		}
+	} else if *operators != "" {
+		return usageError{fmt.Errorf("--operators requires --auth-domain")}
+	}
+	// Tanpa auth setiap caller anonim bisa membuat server menandatangani
+	// (dan membayar gas) tx dengan key operator
+	if *keystore.keyfile != "" && authConfig == nil {
+		return usageError{fmt.Errorf("--keyfile requires --auth-domain, otherwise anyone can send transactions signed by the server key")}
+	}
+
+	// Tanpa keyfile server tidak menandatangani apa pun, endpoint write
+	// selalu mengembalikan tx unsigned
+	var signer redenvelope.Signer = redenvelope.NewWatchOnlySigner(common.Address{})
+	if *keystore.keyfile != "" {
+		keySigner, err := keystore.signer()
+		if err != nil {
+			return err
+		}
+		signer = keySigner
+	}
+
+	serviceOpts := []redenvelope.Option{redenvelope.WithSimulation()}
+	var registry *metrics.Registry
+	if *metricsOn {
+		registry = metrics.NewRegistry()
+		serviceOpts = append(serviceOpts, redenvelope.WithMetrics(redenvelope.NewMetrics(registry)))
+	}
+
+	service, err := network.service(signer, serviceOpts...)
+	if err != nil {
+		return err
+	}
+	defer service.Client.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var opts []server.Option
+	if registry != nil {
+		opts = append(opts, server.WithMetrics(registry))
+	}
+	if *index {
+		indexer := redenvelope.NewIndexer(service)
+		opts = append(opts, server.WithIndexer(indexer))
+		go func() {
+			if err := indexer.Run(ctx, redenvelope.WatchOptions{}); err != nil && ctx.Err() == nil {
+				logger.Error("indexer stopped", "error", err)
+				stop()
+			}
+		}()
+
+		if *webhooks != "" {
+			store, err := webhook.OpenStore(*webhooks)
+			if err != nil {
+				return err
+			}
+			dispatcher, err := webhook.NewDispatcher(store, indexer, webhook.WithAllowedNetworks(allowedNetworks...), webhook.WithLogger(logger))
+			if err != nil {
+				return err
+			}
+			opts = append(opts, server.WithWebhooks(dispatcher))
+			go func() {
+				if err := dispatcher.Run(ctx); err != nil && ctx.Err() == nil {
+					logger.Error("webhook dispatcher stopped", "error", err)
+					stop()
+				}
+			}()
+		}
+	}
+
+	if *jsonrpc {
+		var origins []string
+		if *wsOrigins != "" {
+			origins = strings.Split(*wsOrigins, ",")
+		}
+		opts = append(opts, server.WithJSONRPC(origins...))
+	}
+
+	if authConfig != nil {
+		opts = append(opts, server.WithAuth(*authConfig))
+	}
+
+	api, err := server.New(service, opts...)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           api,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- httpServer.ListenAndServe()
+	}()
+	logger.Info("serving RedEnvelope API", "network", service.Network.Name, "addr", *addr, "contract", service.ContractAddress.Hex(), "openapi", "/openapi.json")
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	// Websocket JSON-RPC tidak ikut ditunggu Shutdown, tutup lebih dulu
+	api.Close()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"rpcsol/redenvelope"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// runWatch mencetak event RedEnvelope sampai Ctrl+C
+func runWatch(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	network := addNetworkFlags(fs)
+	addOutputFlag(fs)
+	fromBlock := fs.String("from-block", "", "First block to read (default: deploymentBlock of the network)")
+	interval := fs.Duration("interval", redenvelope.DefaultPollInterval, "Poll interval")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	service, err := network.readService()
+	if err != nil {
+		return err
+	}
+	defer service.Client.Close()
+
+	from := service.Network.DeploymentBlock
+	if *fromBlock != "" {
+		from, err = strconv.ParseUint(*fromBlock, 10, 64)
+		if err != nil {
+			return usageError{fmt.Errorf("invalid from-block %q", *fromBlock)}
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info("watching events (Ctrl+C to stop)", "contract", service.ContractAddress.Hex(), "network", service.Network.Name, "fromBlock", from)
+	assets := assetCache{service: service}
+	err = service.WatchEvents(ctx, redenvelope.WatchOptions{FromBlock: from, PollInterval: *interval}, func(event redenvelope.EnvelopeEvent) error {
+		// Mode json: satu EventJSON per baris (NDJSON)
+		return printResult(redenvelope.NewEventJSON(event), func() {
+			if err := printEvent(&assets, event); err != nil {
+				logger.Warn("failed to format event", "event", event.Name, "envelopeId", event.EnvelopeID, "error", err)
+			}
+		})
+	})
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// assetCache asset per token dan per envelope supaya amount di event
+// bisa ditampilkan dalam unit token tanpa query berulang
+type assetCache struct {
+	service   *redenvelope.RedEnvelopeService
+	tokens    map[common.Address]*asset
+	envelopes map[string]*asset
+}
+
+func (c *assetCache) token(token common.Address) (*asset, error) {
+	if a, ok := c.tokens[token]; ok {
+		return a, nil
+	}
+	a, err := assetOf(c.service, token)
+	if err != nil {
+		return nil, err
+	}
+	if c.tokens == nil {
+		c.tokens = make(map[common.Address]*asset)
+	}
+	c.tokens[token] = a
+	return a, nil
+}
+
+func (c *assetCache) envelope(id *big.Int) (*asset, error) {
+	if a, ok := c.envelopes[id.String()]; ok {
+		return a, nil
+	}
+	env, err := c.service.GetEnvelope(id)
+	if err != nil {
+		return nil, err
+	}
+	a, err := c.token(env.Token)
+	if err != nil {
+		return nil, err
+	}
+	c.remember(id, a)
+	return a, nil
+}
+
+func (c *assetCache) remember(id *big.Int, a *asset) {
+	if c.envelopes == nil {
+		c.envelopes = make(map[string]*asset)
+	}
+	c.envelopes[id.String()] = a
+}
+
+// printEvent satu baris per event
+func printEvent(assets *assetCache, event redenvelope.EnvelopeEvent) error {
+	prefix := fmt.Sprintf("[block %d] %s #%s", event.BlockNumber, event.Name, event.EnvelopeID)
+
+	switch {
+	case event.Created != nil:
+		a, err := assets.token(event.Created.Token)
+		if err != nil {
+			return err
+		}
+		assets.remember(event.EnvelopeID, a)
+		fmt.Printf("%s by %s: %s, %s for %d claim(s), fee %s, expires %s\n",
+			prefix, event.Created.Creator.Hex(), redenvelope.KindName(event.Created.Kind),
+			a.format(event.Created.NetPot), event.Created.TotalClaims, a.format(event.Created.FeeAmount),
+			time.Unix(int64(event.Created.Expiry), 0).Format(time.RFC3339))
+	case event.Claimed != nil:
+		a, err := assets.envelope(event.EnvelopeID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s by %s: %s (claim %d)\n", prefix, event.Claimed.Claimer.Hex(), a.format(event.Claimed.Payout), event.Claimed.ClaimIndex)
+	case event.Refunded != nil:
+		a, err := assets.envelope(event.EnvelopeID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: %s returned to creator\n", prefix, a.format(event.Refunded.RefundAmount))
+	}
+	return nil
+}
@@ -2,32 +2,44 @@ package main
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"fmt"
-	"log"
+	"log/slog"
 	"math/big"
+	"os"
 	"rpcsol/redenvelope"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
-func main() {
-	// Konfigurasi
-	rpcURL := "http://127.0.0.1:8545"
-	// Private key dari Account #0 Hardhat
-	privateKeyHex := "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+// logger log demo ke stderr; key material disensor
+var logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{ReplaceAttr: redenvelope.RedactAttr}))
 
-	// !! GANTI DENGAN CONTRACT ADDRESS ANDA !!
-	contractAddress := "0x5FbDB2315678afecb367f032d93F642f64180aa3"
+// fatal mencatat error lalu keluar dengan status 1
+func fatal(msg string, args ...any) {
+	logger.Error(msg, args...)
+	os.Exit(1)
+}
+
+func main() {
+	// Konfigurasi network dari redenvelope.json (atau $REDENVELOPE_CONFIG),
+	// network dipilih lewat $REDENVELOPE_NETWORK
+	config, err := redenvelope.LoadConfigFromEnv()
+	if err != nil {
+		fatal("failed to load config", "error", err)
+	}
+	network, err := config.Network("")
+	if err != nil {
+		fatal("failed to load network", "error", err)
+	}
+	rpcURL := network.RPCURLs[0]
 
 	// Connect ke Ethereum node
 	client, err := ethclient.Dial(rpcURL)
 	if err != nil {
-		log.Fatalf("Failed to connect to ethereum node: %v", err)
+		fatal("failed to connect to ethereum node", "error", err)
 	}
 	defer client.Close()
 
@@ -35,19 +47,12 @@ func main() {
 	fmt.Printf("Connected to: %s\n", rpcURL)
 	fmt.Println()
 
-	// Load private key
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	// Load signer (keystore kalau REDENVELOPE_KEYSTORE di-set)
+	signer, err := loadSigner()
 	if err != nil {
-		log.Fatalf("Failed to load private key: %v", err)
+		fatal("failed to load signer", "error", err)
 	}
-
-	// Get address dari private key
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		log.Fatalf("Failed to cast public key to ECDSA")
-	}
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
+	fromAddress := signer.Address()
 	fmt.Printf("Your address: %s\n", fromAddress.Hex())
 	fmt.Println()
 
@@ -55,7 +60,7 @@ func main() {
 	fmt.Println("=== Get Balance ===")
 	balance, err := client.BalanceAt(context.Background(), fromAddress, nil)
 	if err != nil {
-		log.Fatalf("Failed to get balance: %v", err)
+		fatal("failed to get balance", "error", err)
 	}
 	fmt.Printf("Balance: %s ETH\n", weiToEther(balance))
 	fmt.Println()
@@ -64,14 +69,17 @@ func main() {
 	fmt.Println("=== Chain Information ===")
 	chainID, err := client.ChainID(context.Background())
 	if err != nil {
-		log.Fatalf("Failed to get chain ID: %v", err)
+		fatal("failed to get chain ID", "error", err)
 	}
 	fmt.Printf("Chain ID: %s\n", chainID.String())
+	if !chainID.IsUint64() || chainID.Uint64() != network.ChainID {
+		fatal("node is on a different chain than the network", "chainId", chainID, "network", network.Name, "expectedChainId", network.ChainID)
+	}
 
 	// 3. Get Block Number
 	blockNumber, err := client.BlockNumber(context.Background())
 	if err != nil {
-		log.Fatalf("Failed to get block number: %v", err)
+		fatal("failed to get block number", "error", err)
 	}
 	fmt.Printf("Current block number: %d\n", blockNumber)
 	fmt.Println()
@@ -81,7 +89,7 @@ func main() {
 		fmt.Println("=== Latest Block Info ===")
 		block, err := client.BlockByNumber(context.Background(), big.NewInt(int64(blockNumber)))
 		if err != nil {
-			log.Fatalf("Failed to get block: %v", err)
+			fatal("failed to get block", "error", err)
 		}
 		fmt.Printf("Block Hash: %s\n", block.Hash().Hex())
 		fmt.Printf("Block Time: %d\n", block.Time())
@@ -98,13 +106,13 @@ func main() {
 	// Get nonce
 	nonce, err := client.PendingNonceAt(context.Background(), fromAddress)
 	if err != nil {
-		log.Fatalf("Failed to get nonce: %v", err)
+		fatal("failed to get nonce", "error", err)
 	}
 
 	// Get gas price
 	gasPrice, err := client.SuggestGasPrice(context.Background())
 	if err != nil {
-		log.Fatalf("Failed to get gas price: %v", err)
+		fatal("failed to get gas price", "error", err)
 	}
 
 	// Create transaction
@@ -118,15 +126,15 @@ func main() {
 	)
 
 	// Sign transaction
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), privateKey)
+	signedTx, err := signer.SignTx(tx, chainID)
 	if err != nil {
-		log.Fatalf("Failed to sign transaction: %v", err)
+		fatal("failed to sign transaction", "error", err)
 	}
 
 	// Send transaction
 	err = client.SendTransaction(context.Background(), signedTx)
 	if err != nil {
-		log.Fatalf("Failed to send transaction: %v", err)
+		fatal("failed to send transaction", "error", err)
 	}
 
 	fmt.Printf("From: %s\n", fromAddress.Hex())
@@ -140,7 +148,7 @@ func main() {
 	fmt.Println("Waiting for transaction to be mined...")
 	receipt, err := waitForTransactionReceipt(client, signedTx.Hash())
 	if err != nil {
-		log.Printf("Warning: Failed to get receipt: %v", err)
+		logger.Warn("failed to get receipt", "tx", signedTx.Hash().Hex(), "error", err)
 	} else {
 		fmt.Printf("Status: %d (1 = success, 0 = failed)\n", receipt.Status)
 		fmt.Printf("Block Number: %d\n", receipt.BlockNumber.Uint64())
@@ -152,14 +160,14 @@ func main() {
 	fmt.Println("=== Updated Balances ===")
 	newBalance, err := client.BalanceAt(context.Background(), fromAddress, nil)
 	if err != nil {
-		log.Printf("Failed to get new balance: %v", err)
+		logger.Error("failed to get new balance", "error", err)
 	} else {
 		fmt.Printf("Your balance: %s ETH\n", weiToEther(newBalance))
 	}
 
 	toBalance, err := client.BalanceAt(context.Background(), toAddress, nil)
 	if err != nil {
-		log.Printf("Failed to get recipient balance: %v", err)
+		logger.Error("failed to get recipient balance", "error", err)
 	} else {
 		fmt.Printf("Recipient balance: %s ETH\n", weiToEther(toBalance))
 	}
@@ -175,27 +183,49 @@ func main() {
 	fmt.Println("=== RedEnvelope Contract Demo ===")
 	fmt.Println("========================================")
 
-	demoRedEnvelope(rpcURL, contractAddress, privateKeyHex)
+	demoRedEnvelope(network, signer)
 }
 
-func demoRedEnvelope(rpcURL, contractAddress, privateKeyHex string) {
+// loadSigner memakai external signer (Clef) kalau REDENVELOPE_SIGNER_URL
+// di-set, atau key dari keystore V3 kalau REDENVELOPE_KEYSTORE di-set
+// (passphrase dari REDENVELOPE_PASSWORD atau REDENVELOPE_PASSWORD_FILE).
+// Tanpa keduanya, pakai Account #0 Hardhat untuk demo lokal.
+func loadSigner() (redenvelope.Signer, error) {
+	if signerURL := os.Getenv("REDENVELOPE_SIGNER_URL"); signerURL != "" {
+		return redenvelope.NewExternalSigner(signerURL, common.HexToAddress(os.Getenv("REDENVELOPE_SIGNER_ACCOUNT")))
+	}
+
+	keyfile := os.Getenv("REDENVELOPE_KEYSTORE")
+	if keyfile == "" {
+		// Account #0 Hardhat, diturunkan dari mnemonic default
+		return redenvelope.HardhatAccount(0)
+	}
+
+	passphrase, err := redenvelope.ResolvePassphrase(redenvelope.PassphraseEnv, os.Getenv("REDENVELOPE_PASSWORD_FILE"))
+	if err != nil {
+		return nil, err
+	}
+	return redenvelope.NewKeystoreSigner(keyfile, passphrase)
+}
+
+func demoRedEnvelope(network *redenvelope.NetworkConfig, signer redenvelope.Signer) {
 	// Initialize RedEnvelope service
-	reService, err := redenvelope.NewRedEnvelopeService(rpcURL, contractAddress, privateKeyHex)
+	reService, err := redenvelope.NewRedEnvelopeServiceForNetwork(network, signer,
+		redenvelope.WithCompatibilityCheck(), redenvelope.WithLogger(logger))
 	if err != nil {
-		log.Printf("Failed to initialize RedEnvelope service: %v", err)
-		log.Println("Pastikan contract address sudah benar!")
+		logger.Error("failed to initialize RedEnvelope service; check the contract address", "contract", network.ContractAddress.Hex(), "error", err)
 		return
 	}
 	defer reService.Client.Close()
 
-	fmt.Printf("Connected to RedEnvelope contract: %s\n", contractAddress)
+	fmt.Printf("Connected to RedEnvelope contract: %s\n", reService.ContractAddress.Hex())
 	fmt.Printf("Your address: %s\n", reService.Address.Hex())
 	fmt.Println()
 
 	// Get balance
 	balance, err := reService.Client.BalanceAt(context.Background(), reService.Address, nil)
 	if err != nil {
-		log.Printf("Failed to get balance: %v", err)
+		logger.Error("failed to get balance", "error", err)
 		return
 	}
 	fmt.Printf("Balance: %s ETH\n", weiToEther(balance))
@@ -205,7 +235,7 @@ func demoRedEnvelope(rpcURL, contractAddress, privateKeyHex string) {
 	fmt.Println("=== Get Next Envelope ID ===")
 	nextId, err := reService.GetNextEnvelopeId()
 	if err != nil {
-		log.Printf("Failed to get next envelope ID: %v", err)
+		logger.Error("failed to get next envelope ID", "error", err)
 	} else {
 		fmt.Printf("Next Envelope ID: %s\n", nextId.String())
 	}
@@ -241,7 +271,7 @@ func demoRedEnvelope(rpcURL, contractAddress, privateKeyHex string) {
 	)
 
 	if err != nil {
-		log.Printf("Failed to create envelope: %v", err)
+		logger.Error("failed to create envelope", "error", err)
 	} else {
 		fmt.Printf("✓ Transaction sent: %s\n", tx.Hash().Hex())
 		fmt.Println("Waiting for confirmation...")
@@ -260,11 +290,11 @@ func demoRedEnvelope(rpcURL, contractAddress, privateKeyHex string) {
 		fmt.Println("=== Get Envelope Information ===")
 		envelope, err := reService.GetEnvelope(nextId)
 		if err != nil {
-			log.Printf("Failed to get envelope: %v", err)
+			logger.Error("failed to get envelope", "envelopeId", nextId, "error", err)
 		} else {
 			fmt.Printf("Envelope ID: %s\n", nextId.String())
 			fmt.Printf("Creator: %s\n", envelope.Creator.Hex())
-			fmt.Printf("Kind: %s\n", getEnvelopeKindName(envelope.Kind))
+			fmt.Printf("Kind: %s\n", redenvelope.KindName(envelope.Kind))
 			fmt.Printf("Total Claims: %d\n", envelope.TotalClaims)
 			fmt.Printf("Remaining Claims: %d\n", envelope.RemainingClaims)
 			fmt.Printf("Amount Per Claim: %s ETH\n", weiToEther(envelope.AmountPerClaim))
@@ -277,7 +307,7 @@ func demoRedEnvelope(rpcURL, contractAddress, privateKeyHex string) {
 		fmt.Println("=== Check Claim Status ===")
 		hasClaimed, err := reService.HasClaimed(nextId, reService.Address)
 		if err != nil {
-			log.Printf("Failed to check claim status: %v", err)
+			logger.Error("failed to check claim status", "envelopeId", nextId, "address", reService.Address.Hex(), "error", err)
 		} else {
 			fmt.Printf("Has claimed: %v\n", hasClaimed)
 		}
@@ -288,7 +318,7 @@ func demoRedEnvelope(rpcURL, contractAddress, privateKeyHex string) {
 			fmt.Println("=== Claiming Envelope ===")
 			claimTx, err := reService.ClaimEnvelope(nextId)
 			if err != nil {
-				log.Printf("Failed to claim envelope: %v", err)
+				logger.Error("failed to claim envelope", "envelopeId", nextId, "error", err)
 			} else {
 				fmt.Printf("✓ Claim transaction sent: %s\n", claimTx.Hash().Hex())
 				fmt.Println("Waiting for confirmation...")
@@ -321,19 +351,6 @@ func demoRedEnvelope(rpcURL, contractAddress, privateKeyHex string) {
 	fmt.Println("✓ Claim envelope")
 }
 
-func getEnvelopeKindName(kind uint8) string {
-	switch kind {
-	case redenvelope.DIRECT_FIXED:
-		return "DIRECT_FIXED"
-	case redenvelope.GROUP_FIXED:
-		return "GROUP_FIXED"
-	case redenvelope.GROUP_RANDOM:
-		return "GROUP_RANDOM"
-	default:
-		return "UNKNOWN"
-	}
-}
-
 // weiToEther converts wei to ether
 func weiToEther(wei *big.Int) string {
 	ether := new(big.Float).SetInt(wei)
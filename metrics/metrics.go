@@ -0,0 +1,282 @@
+// Package metrics exporter metric kecil dalam format teks Prometheus
+// (text exposition format 0.0.4): counter, gauge dan histogram dengan label.
+//
+//	reg := metrics.NewRegistry()
+//	requests := reg.Counter("app_requests_total", "Requests handled", "method")
+//	requests.With("GET").Inc()
+//	http.Handle("/metrics", reg.Handler())
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType content type format teks Prometheus
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefBuckets bucket histogram default dalam detik, sama dengan client Prometheus
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// ExponentialBuckets count bucket mulai start, setiap bucket dikali factor
+func ExponentialBuckets(start, factor float64, count int) []float64 {
+	buckets := make([]float64, count)
+	for i := range buckets {
+		buckets[i] = start
+		start *= factor
+	}
+	return buckets
+}
+
+// Registry kumpulan metric yang ditulis bersama oleh Handler
+type Registry struct {
+	mu       sync.Mutex
+	families []*family
+	names    map[string]bool
+}
+
+// NewRegistry membuat Registry kosong
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+// family satu metric dengan semua kombinasi labelnya
+type family struct {
+	name    string
+	help    string
+	kind    string // counter, gauge, histogram
+	labels  []string
+	buckets []float64 // Hanya histogram
+
+	mu     sync.Mutex
+	series map[string]*series // Key: nilai label digabung "\xff"
+}
+
+// series nilai satu kombinasi label
+type series struct {
+	mu     sync.Mutex
+	labels []string
+	value  float64  // Counter / gauge, sum untuk histogram
+	counts []uint64 // Histogram: jumlah observasi per bucket (non-kumulatif)
+	count  uint64   // Histogram: total observasi
+}
+
+// register mendaftarkan family baru; nama ganda adalah bug, jadi panic
+func (r *Registry) register(f *family) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[f.name] {
+		panic(fmt.Sprintf("metrics: %s registered twice", f.name))
+	}
+	r.names[f.name] = true
+	f.series = make(map[string]*series)
+	r.families = append(r.families, f)
+	return f
+}
+
+// with series untuk nilai label, dibuat kalau belum ada
+func (f *family) with(values []string) *series {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labels: slices.Clone(values)}
+		if f.kind == "histogram" {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// Counter membuat counter dengan nama label
+func (r *Registry) Counter(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{f: r.register(&family{name: name, help: help, kind: "counter", labels: labels})}
+}
+
+// Gauge membuat gauge dengan nama label
+func (r *Registry) Gauge(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{f: r.register(&family{name: name, help: help, kind: "gauge", labels: labels})}
+}
+
+// Histogram membuat histogram dengan batas atas bucket (urut naik, tanpa
+// +Inf) dan nama label. buckets nil berarti DefBuckets.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	if !slices.IsSorted(buckets) {
+		panic(fmt.Sprintf("metrics: %s buckets must be sorted", name))
+	}
+	return &HistogramVec{f: r.register(&family{name: name, help: help, kind: "histogram", labels: labels, buckets: slices.Clone(buckets)})}
+}
+
+// CounterVec counter per kombinasi label
+type CounterVec struct{ f *family }
+
+// With counter untuk nilai label, urut sesuai nama label
+func (v *CounterVec) With(values ...string) *Counter { return &Counter{v.f.with(values)} }
+
+// Counter nilai yang hanya naik
+type Counter struct{ s *series }
+
+// Inc tambah 1
+func (c *Counter) Inc() { c.Add(1) }
+
+// Add tambah delta; delta negatif diabaikan
+func (c *Counter) Add(delta float64) {
+	if delta < 0 {
+		return
+	}
+	c.s.mu.Lock()
+	c.s.value += delta
+	c.s.mu.Unlock()
+}
+
+// GaugeVec gauge per kombinasi label
+type GaugeVec struct{ f *family }
+
+// With gauge untuk nilai label, urut sesuai nama label
+func (v *GaugeVec) With(values ...string) *Gauge { return &Gauge{v.f.with(values)} }
+
+// Gauge nilai yang bisa naik dan turun
+type Gauge struct{ s *series }
+
+// Set mengganti nilai
+func (g *Gauge) Set(value float64) {
+	g.s.mu.Lock()
+	g.s.value = value
+	g.s.mu.Unlock()
+}
+
+// Add tambah delta (boleh negatif)
+func (g *Gauge) Add(delta float64) {
+	g.s.mu.Lock()
+	g.s.value += delta
+	g.s.mu.Unlock()
+}
+
+// HistogramVec histogram per kombinasi label
+type HistogramVec struct{ f *family }
+
+// With histogram untuk nilai label, urut sesuai nama label
+func (v *HistogramVec) With(values ...string) *Histogram {
+	return &Histogram{s: v.f.with(values), buckets: v.f.buckets}
+}
+
+// Histogram distribusi observasi dalam bucket
+type Histogram struct {
+	s       *series
+	buckets []float64
+}
+
+// Observe mencatat satu nilai
+func (h *Histogram) Observe(value float64) {
+	i, _ := slices.BinarySearch(h.buckets, value)
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	if i < len(h.s.counts) {
+		h.s.counts[i]++
+	}
+	h.s.count++
+	h.s.value += value
+}
+
+// WriteTo menulis semua metric dalam format teks Prometheus, urut nama
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	families := slices.Clone(r.families)
+	r.mu.Unlock()
+	slices.SortFunc(families, func(a, b *family) int { return strings.Compare(a.name, b.name) })
+
+	var b strings.Builder
+	for _, f := range families {
+		f.write(&b)
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// Handler http.Handler untuk endpoint /metrics
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		r.WriteTo(w)
+	})
+}
+
+func (f *family) write(b *strings.Builder) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", f.name, escapeHelp(f.help), f.name, f.kind)
+
+	f.mu.Lock()
+	all := make([]*series, 0, len(f.series))
+	for _, s := range f.series {
+		all = append(all, s)
+	}
+	f.mu.Unlock()
+	slices.SortFunc(all, func(a, b *series) int { return slices.Compare(a.labels, b.labels) })
+
+	for _, s := range all {
+		s.mu.Lock()
+		if f.kind != "histogram" {
+			fmt.Fprintf(b, "%s%s %s\n", f.name, f.labelSet(s.labels, "", ""), formatFloat(s.value))
+			s.mu.Unlock()
+			continue
+		}
+		var cumulative uint64
+		for i, upper := range f.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(b, "%s_bucket%s %d\n", f.name, f.labelSet(s.labels, "le", formatFloat(upper)), cumulative)
+		}
+		fmt.Fprintf(b, "%s_bucket%s %d\n", f.name, f.labelSet(s.labels, "le", "+Inf"), s.count)
+		fmt.Fprintf(b, "%s_sum%s %s\n", f.name, f.labelSet(s.labels, "", ""), formatFloat(s.value))
+		fmt.Fprintf(b, "%s_count%s %d\n", f.name, f.labelSet(s.labels, "", ""), s.count)
+		s.mu.Unlock()
+	}
+}
+
+// labelSet {a="1",b="2"}, ditambah label extra (le) kalau diisi
+func (f *family) labelSet(values []string, extraName, extraValue string) string {
+	if len(values) == 0 && extraName == "" {
+		return ""
+	}
+	pairs := make([]string, 0, len(values)+1)
+	for i, value := range values {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", f.labels[i], escapeLabel(value)))
+	}
+	if extraName != "" {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", extraName, extraValue))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
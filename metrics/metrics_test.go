@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestRegistry_TextExposition(t *testing.T) {
+	reg := NewRegistry()
+	requests := reg.Counter("test_requests_total", "Requests\nhandled", "method")
+	requests.With("GET").Inc()
+	requests.With("GET").Add(2)
+	requests.With(`a"b`).Inc()
+	requests.With("GET").Add(-1) // Diabaikan
+
+	pending := reg.Gauge("test_pending", "Pending items")
+	pending.With().Set(5)
+	pending.With().Add(-2)
+
+	latency := reg.Histogram("test_latency_seconds", "Latency", []float64{0.1, 1}, "method")
+	latency.With("GET").Observe(0.05)
+	latency.With("GET").Observe(1)
+	latency.With("GET").Observe(3)
+
+	rec := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if got := rec.Header().Get("Content-Type"); got != ContentType {
+		t.Errorf("Expected content type %q, got %q", ContentType, got)
+	}
+
+	want := `# HELP test_latency_seconds Latency
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{method="GET",le="0.1"} 1
+test_latency_seconds_bucket{method="GET",le="1"} 2
+test_latency_seconds_bucket{method="GET",le="+Inf"} 3
+test_latency_seconds_sum{method="GET"} 4.05
+test_latency_seconds_count{method="GET"} 3
+# HELP test_pending Pending items
+# TYPE test_pending gauge
+test_pending 3
+# HELP test_requests_total Requests\nhandled
+# TYPE test_requests_total counter
+test_requests_total{method="GET"} 3
+test_requests_total{method="a\"b"} 1
+`
+	if got := rec.Body.String(); got != want {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRegistry_PanicsOnDuplicateName(t *testing.T) {
+	reg := NewRegistry()
+	reg.Counter("dup_total", "First")
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for duplicate metric name")
+		}
+	}()
+	reg.Gauge("dup_total", "Second")
+}
+
+func TestExponentialBuckets(t *testing.T) {
+	if got := ExponentialBuckets(1, 2, 4); !slices.Equal(got, []float64{1, 2, 4, 8}) {
+		t.Errorf("Unexpected buckets %v", got)
+	}
+}
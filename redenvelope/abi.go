@@ -16,5 +16,7 @@ var (
 	TestRoomIdHash = GenerateRoomIdHash("test-room-123")
 )
 
-// RedEnvelope Contract ABI - from compiled contract
-const RedEnvelopeABI = `[{"inputs":[{"internalType":"address","name":"_treasury","type":"address"},{"internalType":"uint16","name":"_feeBps","type":"uint16"}],"stateMutability":"nonpayable","type":"constructor"},{"inputs":[],"name":"AlreadyClaimed","type":"error"},{"inputs":[],"name":"EnvelopeExpired","type":"error"},{"inputs":[],"name":"EnvelopeNotFound","type":"error"},{"inputs":[],"name":"InvalidParameters","type":"error"},{"inputs":[],"name":"NotEligible","type":"error"},{"inputs":[],"name":"TransferFailed","type":"error"},{"inputs":[],"name":"Unauthorized","type":"error"},{"anonymous":false,"inputs":[{"indexed":false,"internalType":"uint256","name":"envelopeId","type":"uint256"},{"indexed":false,"internalType":"address","name":"claimer","type":"address"},{"indexed":false,"internalType":"uint256","name":"payout","type":"uint256"},{"indexed":false,"internalType":"uint32","name":"claimIndex","type":"uint32"}],"name":"EnvelopeClaimed","type":"event"},{"anonymous":false,"inputs":[{"indexed":false,"internalType":"uint256","name":"envelopeId","type":"uint256"},{"indexed":false,"internalType":"address","name":"creator","type":"address"},{"indexed":false,"internalType":"enum EnvelopeKind","name":"kind","type":"uint8"},{"indexed":false,"internalType":"address","name":"token","type":"address"},{"indexed":false,"internalType":"uint256","name":"netPot","type":"uint256"},{"indexed":false,"internalType":"uint32","name":"totalClaims","type":"uint32"},{"indexed":false,"internalType":"uint64","name":"expiry","type":"uint64"},{"indexed":false,"internalType":"uint256","name":"feeAmount","type":"uint256"},{"indexed":false,"internalType":"bytes32","name":"roomIdHash","type":"bytes32"},{"indexed":false,"internalType":"address","name":"recipient","type":"address"}],"name":"EnvelopeCreated","type":"event"},{"anonymous":false,"inputs":[{"indexed":false,"internalType":"uint256","name":"envelopeId","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"refundAmount","type":"uint256"}],"name":"EnvelopeRefunded","type":"event"},{"inputs":[],"name":"BPS_DENOMINATOR","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"envelopeId","type":"uint256"}],"name":"claimEnvelope","outputs":[{"internalType":"uint256","name":"payout","type":"uint256"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"enum EnvelopeKind","name":"kind","type":"uint8"},{"internalType":"address","name":"token","type":"address"},{"internalType":"uint32","name":"totalClaims","type":"uint32"},{"internalType":"uint256","name":"amountPerClaimOrPot","type":"uint256"},{"internalType":"uint64","name":"expiry","type":"uint64"},{"internalType":"bytes32","name":"roomIdHash","type":"bytes32"},{"internalType":"address","name":"recipient","type":"address"}],"name":"createEnvelope","outputs":[{"internalType":"uint256","name":"envelopeId","type":"uint256"}],"stateMutability":"payable","type":"function"},{"inputs":[{"internalType":"uint256","name":"","type":"uint256"}],"name":"envelopes","outputs":[{"internalType":"address","name":"creator","type":"address"},{"internalType":"address","name":"token","type":"address"},{"internalType":"enum EnvelopeKind","name":"kind","type":"uint8"},{"internalType":"uint256","name":"amountPerClaim","type":"uint256"},{"internalType":"uint256","name":"remainingAmount","type":"uint256"},{"internalType":"uint32","name":"totalClaims","type":"uint32"},{"internalType":"uint32","name":"remainingClaims","type":"uint32"},{"internalType":"uint32","name":"claimIndex","type":"uint32"},{"internalType":"uint64","name":"expiry","type":"uint64"},{"internalType":"bytes32","name":"roomIdHash","type":"bytes32"},{"internalType":"address","name":"recipient","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"feeBps","outputs":[{"internalType":"uint16","name":"","type":"uint16"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"envelopeId","type":"uint256"}],"name":"getEnvelope","outputs":[{"components":[{"internalType":"address","name":"creator","type":"address"},{"internalType":"address","name":"token","type":"address"},{"internalType":"enum EnvelopeKind","name":"kind","type":"uint8"},{"internalType":"uint256","name":"amountPerClaim","type":"uint256"},{"internalType":"uint256","name":"remainingAmount","type":"uint256"},{"internalType":"uint32","name":"totalClaims","type":"uint32"},{"internalType":"uint32","name":"remainingClaims","type":"uint32"},{"internalType":"uint32","name":"claimIndex","type":"uint32"},{"internalType":"uint64","name":"expiry","type":"uint64"},{"internalType":"bytes32","name":"roomIdHash","type":"bytes32"},{"internalType":"address","name":"recipient","type":"address"}],"internalType":"struct Envelope","name":"","type":"tuple"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"","type":"uint256"},{"internalType":"address","name":"","type":"address"}],"name":"hasClaimed","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"envelopeId","type":"uint256"},{"internalType":"address","name":"user","type":"address"}],"name":"hasUserClaimed","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"nextEnvelopeId","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"owner","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"envelopeId","type":"uint256"}],"name":"refundEnvelope","outputs":[{"internalType":"uint256","name":"refundAmount","type":"uint256"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[],"name":"treasury","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint16","name":"_feeBps","type":"uint16"}],"name":"updateFeeBps","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"_treasury","type":"address"}],"name":"updateTreasury","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+// RedEnvelopeABI ABI contract, diambil dari artifacts/RedEnvelope.json yang
+// di-embed supaya tidak bisa berbeda dari artifact. RedEnvelope.abi untuk
+// abigen ditulis dari artifact yang sama lewat `go generate ./redenvelope`.
+var RedEnvelopeABI = artifactABIJSON(embeddedArtifact)
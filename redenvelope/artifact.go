@@ -0,0 +1,215 @@
+package redenvelope
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// embeddedArtifact artifact Hardhat RedEnvelope yang ikut di-build. Update
+// dengan menyalin artifacts/contracts/RedEnvelope.sol/RedEnvelope.json
+// setelah `npx hardhat compile`.
+//
+//go:embed artifacts/RedEnvelope.json
+var embeddedArtifact []byte
+
+// Artifact ABI dan bytecode contract dari artifact Hardhat / Foundry
+type Artifact struct {
+	ContractName     string
+	ABI              abi.ABI
+	RawABI           json.RawMessage
+	Bytecode         []byte // Creation bytecode, kosong kalau artifact tidak membawanya
+	DeployedBytecode []byte
+}
+
+// ABIMismatchError perbedaan signature antara ABI di package dan artifact
+type ABIMismatchError struct {
+	Differences []string
+}
+
+func (e *ABIMismatchError) Error() string {
+	return fmt.Sprintf("ABI mismatch with artifact: %s", strings.Join(e.Differences, "; "))
+}
+
+// artifactJSON format artifact. Hardhat menyimpan bytecode sebagai string
+// hex, Foundry sebagai object {"object": "0x..."}.
+type artifactJSON struct {
+	ContractName     string          `json:"contractName"`
+	ABI              json.RawMessage `json:"abi"`
+	Bytecode         json.RawMessage `json:"bytecode"`
+	DeployedBytecode json.RawMessage `json:"deployedBytecode"`
+}
+
+// ParseArtifact parse artifact JSON Hardhat atau Foundry
+func ParseArtifact(data []byte) (*Artifact, error) {
+	var raw artifactJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse artifact: %v", err)
+	}
+	if len(raw.ABI) == 0 {
+		return nil, fmt.Errorf("artifact has no abi")
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(string(raw.ABI)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse artifact ABI: %v", err)
+	}
+
+	bytecode, err := parseArtifactBytecode(raw.Bytecode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bytecode: %v", err)
+	}
+	deployed, err := parseArtifactBytecode(raw.DeployedBytecode)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deployedBytecode: %v", err)
+	}
+
+	return &Artifact{
+		ContractName:     raw.ContractName,
+		ABI:              parsed,
+		RawABI:           raw.ABI,
+		Bytecode:         bytecode,
+		DeployedBytecode: deployed,
+	}, nil
+}
+
+// artifactABIJSON ABI artifact sebagai JSON compact. String kosong kalau
+// artifact rusak, sehingga contractABI gagal dengan error parse.
+func artifactABIJSON(data []byte) string {
+	var raw artifactJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return ""
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, raw.ABI); err != nil {
+		return ""
+	}
+	return compact.String()
+}
+
+// LoadArtifact membaca artifact dari path, mis.
+// artifacts/contracts/RedEnvelope.sol/RedEnvelope.json (Hardhat) atau
+// out/RedEnvelope.sol/RedEnvelope.json (Foundry)
+func LoadArtifact(path string) (*Artifact, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact: %v", err)
+	}
+	return ParseArtifact(data)
+}
+
+// EmbeddedArtifact artifact yang di-embed di package
+func EmbeddedArtifact() (*Artifact, error) {
+	return ParseArtifact(embeddedArtifact)
+}
+
+// WithArtifact memakai artifact untuk service (bytecode untuk deploy dan
+// compatibility check). Gagal kalau ABI artifact berbeda dari RedEnvelopeABI.
+func WithArtifact(artifact *Artifact) Option {
+	return func(s *RedEnvelopeService) error {
+		if err := CheckArtifactABI(artifact); err != nil {
+			return err
+		}
+		s.Artifact = artifact
+		return nil
+	}
+}
+
+// CheckArtifactABI membandingkan semua signature function, event, error dan
+// constructor di artifact dengan RedEnvelopeABI. Perbedaan apa pun
+// dikembalikan sebagai *ABIMismatchError.
+func CheckArtifactABI(artifact *Artifact) error {
+	expected, err := contractABI()
+	if err != nil {
+		return err
+	}
+
+	want := abiSignatures(expected)
+	got := abiSignatures(artifact.ABI)
+
+	var differences []string
+	for key, signature := range want {
+		actual, ok := got[key]
+		switch {
+		case !ok:
+			differences = append(differences, fmt.Sprintf("%s missing from artifact", key))
+		case actual != signature:
+			differences = append(differences, fmt.Sprintf("%s is %q in artifact, expected %q", key, actual, signature))
+		}
+	}
+	for key := range got {
+		if _, ok := want[key]; !ok {
+			differences = append(differences, fmt.Sprintf("%s only in artifact", key))
+		}
+	}
+
+	if len(differences) > 0 {
+		sort.Strings(differences)
+		return &ABIMismatchError{Differences: differences}
+	}
+	return nil
+}
+
+// abiSignatures signature setiap entry ABI tanpa nama parameter, termasuk
+// output dan state mutability function serta flag indexed event
+func abiSignatures(parsed abi.ABI) map[string]string {
+	signatures := make(map[string]string)
+
+	signatures["constructor"] = fmt.Sprintf("constructor(%s)", argumentTypes(parsed.Constructor.Inputs))
+	for name, method := range parsed.Methods {
+		signatures["function "+name] = fmt.Sprintf("%s returns (%s) %s", method.Sig, argumentTypes(method.Outputs), method.StateMutability)
+	}
+	for name, event := range parsed.Events {
+		signatures["event "+name] = fmt.Sprintf("%s(%s)", name, argumentTypes(event.Inputs))
+	}
+	for name, abiErr := range parsed.Errors {
+		signatures["error "+name] = abiErr.Sig
+	}
+	return signatures
+}
+
+func argumentTypes(arguments abi.Arguments) string {
+	types := make([]string, len(arguments))
+	for i, arg := range arguments {
+		types[i] = arg.Type.String()
+		if arg.Indexed {
+			types[i] += " indexed"
+		}
+	}
+	return strings.Join(types, ",")
+}
+
+func parseArtifactBytecode(raw json.RawMessage) ([]byte, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var code string
+	if err := json.Unmarshal(raw, &code); err != nil {
+		var foundry struct {
+			Object string `json:"object"`
+		}
+		if err := json.Unmarshal(raw, &foundry); err != nil {
+			return nil, fmt.Errorf("expected hex string or {\"object\": ...}")
+		}
+		code = foundry.Object
+	}
+
+	if code == "" || code == "0x" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(code, "0x") {
+		code = "0x" + code // Foundry kadang tanpa prefix
+	}
+	if strings.Contains(code, "__") {
+		return nil, fmt.Errorf("bytecode has unlinked library references")
+	}
+	return hexutil.Decode(code)
+}
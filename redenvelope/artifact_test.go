@@ -0,0 +1,102 @@
+package redenvelope
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEmbeddedArtifact_MatchesABI(t *testing.T) {
+	artifact, err := EmbeddedArtifact()
+	if err != nil {
+		t.Fatalf("Failed to load embedded artifact: %v", err)
+	}
+	if artifact.ContractName != "RedEnvelope" {
+		t.Errorf("Unexpected contract name %q", artifact.ContractName)
+	}
+	if err := CheckArtifactABI(artifact); err != nil {
+		t.Fatalf("Embedded artifact drifted from RedEnvelopeABI: %v", err)
+	}
+	if len(artifact.Bytecode) == 0 || len(artifact.DeployedBytecode) == 0 {
+		t.Skip("Embedded artifact is ABI-only - copy the compiled Hardhat artifact into artifacts/RedEnvelope.json")
+	}
+}
+
+func TestABIFile_MatchesArtifact(t *testing.T) {
+	artifact, err := EmbeddedArtifact()
+	if err != nil {
+		t.Fatalf("Failed to load embedded artifact: %v", err)
+	}
+	data, err := os.ReadFile("RedEnvelope.abi")
+	if err != nil {
+		t.Fatalf("Failed to read RedEnvelope.abi: %v", err)
+	}
+
+	var fromFile, fromArtifact any
+	if err := json.Unmarshal(data, &fromFile); err != nil {
+		t.Fatalf("Failed to parse RedEnvelope.abi: %v", err)
+	}
+	if err := json.Unmarshal(artifact.RawABI, &fromArtifact); err != nil {
+		t.Fatalf("Failed to parse artifact ABI: %v", err)
+	}
+	if !reflect.DeepEqual(fromFile, fromArtifact) {
+		t.Error("RedEnvelope.abi is out of date, run go generate ./redenvelope")
+	}
+	if RedEnvelopeABI == "" {
+		t.Error("RedEnvelopeABI was not derived from the embedded artifact")
+	}
+}
+
+func TestParseArtifact_Foundry(t *testing.T) {
+	data := `{"abi":` + RedEnvelopeABI + `,"bytecode":{"object":"0x6080604052"},"deployedBytecode":{"object":"6080"}}`
+
+	artifact, err := ParseArtifact([]byte(data))
+	if err != nil {
+		t.Fatalf("Failed to parse Foundry artifact: %v", err)
+	}
+	if len(artifact.Bytecode) != 5 || len(artifact.DeployedBytecode) != 2 {
+		t.Errorf("Unexpected bytecode: %x / %x", artifact.Bytecode, artifact.DeployedBytecode)
+	}
+}
+
+func TestParseArtifact_UnlinkedLibrary(t *testing.T) {
+	data := `{"abi":[],"bytecode":"0x6080__$abcdef$__"}`
+	if _, err := ParseArtifact([]byte(data)); err == nil {
+		t.Fatal("Expected error for unlinked bytecode")
+	}
+}
+
+func TestCheckArtifactABI_Mismatch(t *testing.T) {
+	// Event dengan field indexed dan error baru harus terdeteksi
+	changed := strings.Replace(RedEnvelopeABI, `{"indexed":false,"internalType":"uint256","name":"refundAmount"`, `{"indexed":true,"internalType":"uint256","name":"refundAmount"`, 1)
+	changed = strings.Replace(changed, `{"inputs":[],"name":"Unauthorized","type":"error"}`, `{"inputs":[],"name":"Paused","type":"error"}`, 1)
+
+	path := filepath.Join(t.TempDir(), "RedEnvelope.json")
+	if err := os.WriteFile(path, []byte(`{"contractName":"RedEnvelope","abi":`+changed+`,"bytecode":"0x"}`), 0o644); err != nil {
+		t.Fatalf("Failed to write artifact: %v", err)
+	}
+	artifact, err := LoadArtifact(path)
+	if err != nil {
+		t.Fatalf("Failed to load artifact: %v", err)
+	}
+
+	err = CheckArtifactABI(artifact)
+	var mismatch *ABIMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Expected ABIMismatchError, got %v", err)
+	}
+	message := mismatch.Error()
+	for _, expected := range []string{"event EnvelopeRefunded", "error Unauthorized missing", "error Paused only in artifact"} {
+		if !strings.Contains(message, expected) {
+			t.Errorf("Expected %q in %s", expected, message)
+		}
+	}
+
+	if err := WithArtifact(artifact)(&RedEnvelopeService{}); err == nil {
+		t.Error("WithArtifact should reject a mismatching artifact")
+	}
+}
@@ -0,0 +1,229 @@
+package redenvelope
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// erc20ABI ABI minimal ERC-20 untuk cek balance, allowance, decimals dan
+// approve
+const erc20ABI = `[{"constant":false,"inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"name":"approve","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"},{"constant":true,"inputs":[{"name":"owner","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"name":"allowance","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"stateMutability":"view","type":"function"}]`
+
+// approveGasLimit gas limit tx approve ERC-20
+const approveGasLimit = 100000
+
+// NativeAsset nama asset untuk native token (ETH/BNB) di InsufficientFundsError
+const NativeAsset = "native"
+
+// InsufficientFundsError balance (atau allowance ERC-20 ke contract) tidak
+// cukup untuk menjalankan transaksi
+type InsufficientFundsError struct {
+	Asset     string         // NativeAsset atau "erc20"
+	Token     common.Address // Zero address untuk native token
+	Allowance bool           // ERC-20: yang kurang allowance ke contract, bukan balance
+	Decimals  uint8          // ERC-20: decimals token untuk format amount
+	Required  *big.Int       // Native: msg.value + gasLimit × maxFeePerGas
+	Available *big.Int       // Balance, atau allowance kalau Allowance
+	Missing   *big.Int
+}
+
+// Error jumlah native dalam wei, jumlah ERC-20 dalam satuan token sesuai
+// Decimals
+func (e *InsufficientFundsError) Error() string {
+	if e.Token == (common.Address{}) {
+		return fmt.Sprintf("insufficient %s balance: required %s wei, available %s wei, missing %s wei",
+			e.Asset, e.Required, e.Available, e.Missing)
+	}
+	if e.Allowance {
+		return fmt.Sprintf("insufficient %s %s allowance for the contract: required %s, approved %s, missing %s (decimals %d)",
+			e.Asset, e.Token.Hex(), FormatAmount(e.Required, e.Decimals), FormatAmount(e.Available, e.Decimals),
+			FormatAmount(e.Missing, e.Decimals), e.Decimals)
+	}
+	return fmt.Sprintf("insufficient %s %s balance: required %s, available %s, missing %s (decimals %d)",
+		e.Asset, e.Token.Hex(), FormatAmount(e.Required, e.Decimals), FormatAmount(e.Available, e.Decimals),
+		FormatAmount(e.Missing, e.Decimals), e.Decimals)
+}
+
+// checkNativeBalance memastikan balance native cukup untuk value + gas terburuk
+func (s *RedEnvelopeService) checkNativeBalance(value *big.Int, gasLimit uint64, maxFeePerGas *big.Int) error {
+	required := new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), maxFeePerGas)
+	if value != nil {
+		required.Add(required, value)
+	}
+
+	balance, err := s.Client.BalanceAt(context.Background(), s.Address, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get balance: %v", err)
+	}
+
+	if balance.Cmp(required) < 0 {
+		return &InsufficientFundsError{
+			Asset:     NativeAsset,
+			Required:  required,
+			Available: balance,
+			Missing:   new(big.Int).Sub(required, balance),
+		}
+	}
+	return nil
+}
+
+// checkTokenBalance memastikan balance ERC-20 sender dan allowance-nya ke
+// contract cukup untuk amount (createEnvelope memakai transferFrom)
+func (s *RedEnvelopeService) checkTokenBalance(token common.Address, amount *big.Int) error {
+	balance, err := s.TokenBalance(token, s.Address)
+	if err != nil {
+		return err
+	}
+	if balance.Cmp(amount) < 0 {
+		return s.tokenShortfall(token, amount, balance, false)
+	}
+
+	allowance, err := s.TokenAllowance(token, s.Address, s.ContractAddress)
+	if err != nil {
+		return err
+	}
+	if allowance.Cmp(amount) < 0 {
+		return s.tokenShortfall(token, amount, allowance, true)
+	}
+	return nil
+}
+
+// tokenShortfall InsufficientFundsError ERC-20. Token tanpa decimals()
+// (opsional di ERC-20) ditampilkan dalam unit terkecil.
+func (s *RedEnvelopeService) tokenShortfall(token common.Address, required, available *big.Int, allowance bool) error {
+	decimals, err := s.TokenDecimals(token)
+	if err != nil {
+		decimals = 0
+	}
+	return &InsufficientFundsError{
+		Asset:     "erc20",
+		Token:     token,
+		Allowance: allowance,
+		Decimals:  decimals,
+		Required:  new(big.Int).Set(required),
+		Available: available,
+		Missing:   new(big.Int).Sub(required, available),
+	}
+}
+
+// TokenBalance mendapatkan balance ERC-20 milik owner
+func (s *RedEnvelopeService) TokenBalance(token common.Address, owner common.Address) (*big.Int, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(erc20ABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ERC-20 ABI: %v", err)
+	}
+
+	boundContract := bind.NewBoundContract(token, parsedABI, s.Client, s.Client, s.Client)
+
+	var result []interface{}
+	err = boundContract.Call(&bind.CallOpts{}, &result, "balanceOf", owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token balance: %v", err)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no result returned from token contract")
+	}
+
+	balance, ok := result[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected balanceOf result type %T", result[0])
+	}
+	return balance, nil
+}
+
+// ApproveToken mengirim approve(contract, amount) ke token ERC-20 supaya
+// createEnvelope bisa menarik amount lewat transferFrom. Receipt ditunggu
+// caller (WaitMined).
+func (s *RedEnvelopeService) ApproveToken(token common.Address, amount *big.Int) (*types.Transaction, error) {
+	if err := checkAmount(amount); err != nil {
+		return nil, err
+	}
+	parsedABI, err := abi.JSON(strings.NewReader(erc20ABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ERC-20 ABI: %v", err)
+	}
+	data, err := parsedABI.Pack("approve", s.ContractAddress, amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack approve: %v", err)
+	}
+
+	gasPrice, err := s.gasPrice(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkNativeBalance(nil, approveGasLimit, gasPrice); err != nil {
+		return nil, err
+	}
+
+	auth := s.transactOpts()
+	auth.GasLimit = approveGasLimit
+	auth.GasPrice = gasPrice
+	boundContract := bind.NewBoundContract(token, parsedABI, s.Client, s.Client, s.Client)
+	tx, err := bind.Transact(boundContract, auth, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to approve token: %v", err)
+	}
+	s.log().Info("sent approve", slog.String("token", token.Hex()), slog.String("amount", amount.String()), slog.String("tx", tx.Hash().Hex()))
+	return tx, nil
+}
+
+// TokenAllowance mendapatkan allowance ERC-20 dari owner ke spender
+func (s *RedEnvelopeService) TokenAllowance(token common.Address, owner common.Address, spender common.Address) (*big.Int, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(erc20ABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ERC-20 ABI: %v", err)
+	}
+
+	boundContract := bind.NewBoundContract(token, parsedABI, s.Client, s.Client, s.Client)
+
+	var result []interface{}
+	err = boundContract.Call(&bind.CallOpts{}, &result, "allowance", owner, spender)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token allowance: %v", err)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no result returned from token contract")
+	}
+
+	allowance, ok := result[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected allowance result type %T", result[0])
+	}
+	return allowance, nil
+}
+
+// TokenDecimals mendapatkan decimals ERC-20, dipakai untuk konversi amount
+func (s *RedEnvelopeService) TokenDecimals(token common.Address) (uint8, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(erc20ABI))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse ERC-20 ABI: %v", err)
+	}
+
+	boundContract := bind.NewBoundContract(token, parsedABI, s.Client, s.Client, s.Client)
+
+	var result []interface{}
+	err = boundContract.Call(&bind.CallOpts{}, &result, "decimals")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get token decimals: %v", err)
+	}
+
+	if len(result) == 0 {
+		return 0, fmt.Errorf("no result returned from token contract")
+	}
+
+	decimals, ok := result[0].(uint8)
+	if !ok {
+		return 0, fmt.Errorf("unexpected decimals result type %T", result[0])
+	}
+	return decimals, nil
+}
@@ -0,0 +1,124 @@
+package redenvelope
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestCheckNativeBalance(t *testing.T) {
+	tests := []struct {
+		name     string
+		balance  int64
+		value    *big.Int
+		gasLimit uint64
+		gasPrice int64
+		missing  int64 // 0 kalau balance cukup
+	}{
+		{"exact", 1100, big.NewInt(100), 100, 10, 0},
+		{"gas only", 1000, nil, 100, 10, 0},
+		{"short by value", 1099, big.NewInt(100), 100, 10, 1},
+		{"short by gas", 500, big.NewInt(0), 100, 10, 500},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service := newFakeSenderService(t, &fakeEthAPI{balance: big.NewInt(tc.balance)})
+			err := service.checkNativeBalance(tc.value, tc.gasLimit, big.NewInt(tc.gasPrice))
+			if tc.missing == 0 {
+				if err != nil {
+					t.Fatalf("Expected enough balance, got %v", err)
+				}
+				return
+			}
+
+			var fundsErr *InsufficientFundsError
+			if !errors.As(err, &fundsErr) {
+				t.Fatalf("Expected InsufficientFundsError, got %v", err)
+			}
+			required := tc.balance + tc.missing
+			if fundsErr.Asset != NativeAsset || fundsErr.Token != (common.Address{}) ||
+				fundsErr.Required.Int64() != required || fundsErr.Available.Int64() != tc.balance || fundsErr.Missing.Int64() != tc.missing {
+				t.Errorf("Unexpected error fields %+v", fundsErr)
+			}
+			if !strings.Contains(err.Error(), "wei") || strings.Contains(err.Error(), "token units") {
+				t.Errorf("Native error should be in wei: %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckTokenBalance(t *testing.T) {
+	token := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	word := func(v int64) hexutil.Bytes { return common.LeftPadBytes(big.NewInt(v).Bytes(), 32) }
+	tests := []struct {
+		name      string
+		balance   int64
+		allowance int64
+		amount    int64
+		missing   int64 // 0 kalau balance dan allowance cukup
+		approval  bool  // Yang kurang allowance
+		message   string
+	}{
+		{"exact", 1500000, 1500000, 1500000, 0, false, ""},
+		{"more than enough", 9000000, 9000000, 1500000, 0, false, ""},
+		{"short balance", 200000, 9000000, 1500000, 1300000, false, "required 1.5, available 0.2, missing 1.3 (decimals 6)"},
+		{"short allowance", 9000000, 500000, 1500000, 1000000, true, "allowance for the contract: required 1.5, approved 0.5, missing 1 (decimals 6)"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service := newFakeSenderService(t, &fakeEthAPI{outputs: map[string]hexutil.Bytes{
+				"0x70a08231": word(tc.balance),   // balanceOf
+				"0xdd62ed3e": word(tc.allowance), // allowance
+				"0x313ce567": word(6),            // decimals
+			}})
+			err := service.checkTokenBalance(token, big.NewInt(tc.amount))
+			if tc.missing == 0 {
+				if err != nil {
+					t.Fatalf("Expected enough balance and allowance, got %v", err)
+				}
+				return
+			}
+
+			var fundsErr *InsufficientFundsError
+			if !errors.As(err, &fundsErr) {
+				t.Fatalf("Expected InsufficientFundsError, got %v", err)
+			}
+			if fundsErr.Asset != "erc20" || fundsErr.Token != token || fundsErr.Allowance != tc.approval || fundsErr.Decimals != 6 ||
+				fundsErr.Required.Int64() != tc.amount || fundsErr.Missing.Int64() != tc.missing {
+				t.Errorf("Unexpected error fields %+v", fundsErr)
+			}
+			if !strings.Contains(err.Error(), token.Hex()) || !strings.Contains(err.Error(), tc.message) || strings.Contains(err.Error(), "wei") {
+				t.Errorf("Token error should name the token and use token decimals, got %v", err)
+			}
+		})
+	}
+}
+
+func TestApproveToken(t *testing.T) {
+	token := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	api := &fakeEthAPI{balance: big.NewInt(1e18)}
+	service := newFakeSenderService(t, api)
+
+	tx, err := service.ApproveToken(token, big.NewInt(1500))
+	if err != nil {
+		t.Fatalf("Failed to approve: %v", err)
+	}
+	sent := api.sentTxs()
+	if len(sent) != 1 || sent[0].Hash() != tx.Hash() || *tx.To() != token {
+		t.Fatalf("Expected one approve tx to the token, got %v", sent)
+	}
+	want := append(common.FromHex("0x095ea7b3"), common.LeftPadBytes(service.ContractAddress.Bytes(), 32)...)
+	want = append(want, common.LeftPadBytes(big.NewInt(1500).Bytes(), 32)...)
+	if !bytes.Equal(tx.Data(), want) {
+		t.Errorf("Unexpected approve calldata %x", tx.Data())
+	}
+
+	if _, err := service.ApproveToken(token, big.NewInt(0)); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("Expected ErrInvalidAmount, got %v", err)
+	}
+}
@@ -0,0 +1,893 @@
+// Code generated via abigen V2 - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package bindings
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = bytes.Equal
+	_ = errors.New
+	_ = big.NewInt
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = abi.ConvertType
+)
+
+// Envelope is an auto generated low-level Go binding around an user-defined struct.
+type Envelope struct {
+	Creator         common.Address
+	Token           common.Address
+	Kind            uint8
+	AmountPerClaim  *big.Int
+	RemainingAmount *big.Int
+	TotalClaims     uint32
+	RemainingClaims uint32
+	ClaimIndex      uint32
+	Expiry          uint64
+	RoomIdHash      [32]byte
+	Recipient       common.Address
+}
+
+// RedEnvelopeMetaData contains all meta data concerning the RedEnvelope contract.
+var RedEnvelopeMetaData = bind.MetaData{
+	ABI: "[{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_treasury\",\"type\":\"address\"},{\"internalType\":\"uint16\",\"name\":\"_feeBps\",\"type\":\"uint16\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"inputs\":[],\"name\":\"AlreadyClaimed\",\"type\":\"error\"},{\"inputs\":[],\"name\":\"EnvelopeExpired\",\"type\":\"error\"},{\"inputs\":[],\"name\":\"EnvelopeNotFound\",\"type\":\"error\"},{\"inputs\":[],\"name\":\"InvalidParameters\",\"type\":\"error\"},{\"inputs\":[],\"name\":\"NotEligible\",\"type\":\"error\"},{\"inputs\":[],\"name\":\"TransferFailed\",\"type\":\"error\"},{\"inputs\":[],\"name\":\"Unauthorized\",\"type\":\"error\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"envelopeId\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"claimer\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"payout\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint32\",\"name\":\"claimIndex\",\"type\":\"uint32\"}],\"name\":\"EnvelopeClaimed\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"envelopeId\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"creator\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"enumEnvelopeKind\",\"name\":\"kind\",\"type\":\"uint8\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"token\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"netPot\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint32\",\"name\":\"totalClaims\",\"type\":\"uint32\"},{\"indexed\":false,\"internalType\":\"uint64\",\"name\":\"expiry\",\"type\":\"uint64\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"feeAmount\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"bytes32\",\"name\":\"roomIdHash\",\"type\":\"bytes32\"},{\"indexed\":false,\"internalType\":\"address\",\"name\":\"recipient\",\"type\":\"address\"}],\"name\":\"EnvelopeCreated\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"envelopeId\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"refundAmount\",\"type\":\"uint256\"}],\"name\":\"EnvelopeRefunded\",\"type\":\"event\"},{\"inputs\":[],\"name\":\"BPS_DENOMINATOR\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"envelopeId\",\"type\":\"uint256\"}],\"name\":\"claimEnvelope\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"payout\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"enumEnvelopeKind\",\"name\":\"kind\",\"type\":\"uint8\"},{\"internalType\":\"address\",\"name\":\"token\",\"type\":\"address\"},{\"internalType\":\"uint32\",\"name\":\"totalClaims\",\"type\":\"uint32\"},{\"internalType\":\"uint256\",\"name\":\"amountPerClaimOrPot\",\"type\":\"uint256\"},{\"internalType\":\"uint64\",\"name\":\"expiry\",\"type\":\"uint64\"},{\"internalType\":\"bytes32\",\"name\":\"roomIdHash\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"recipient\",\"type\":\"address\"}],\"name\":\"createEnvelope\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"envelopeId\",\"type\":\"uint256\"}],\"stateMutability\":\"payable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"envelopes\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"creator\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"token\",\"type\":\"address\"},{\"internalType\":\"enumEnvelopeKind\",\"name\":\"kind\",\"type\":\"uint8\"},{\"internalType\":\"uint256\",\"name\":\"amountPerClaim\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"remainingAmount\",\"type\":\"uint256\"},{\"internalType\":\"uint32\",\"name\":\"totalClaims\",\"type\":\"uint32\"},{\"internalType\":\"uint32\",\"name\":\"remainingClaims\",\"type\":\"uint32\"},{\"internalType\":\"uint32\",\"name\":\"claimIndex\",\"type\":\"uint32\"},{\"internalType\":\"uint64\",\"name\":\"expiry\",\"type\":\"uint64\"},{\"internalType\":\"bytes32\",\"name\":\"roomIdHash\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"recipient\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"feeBps\",\"outputs\":[{\"internalType\":\"uint16\",\"name\":\"\",\"type\":\"uint16\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"envelopeId\",\"type\":\"uint256\"}],\"name\":\"getEnvelope\",\"outputs\":[{\"components\":[{\"internalType\":\"address\",\"name\":\"creator\",\"type\":\"address\"},{\"internalType\":\"address\",\"name\":\"token\",\"type\":\"address\"},{\"internalType\":\"enumEnvelopeKind\",\"name\":\"kind\",\"type\":\"uint8\"},{\"internalType\":\"uint256\",\"name\":\"amountPerClaim\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"remainingAmount\",\"type\":\"uint256\"},{\"internalType\":\"uint32\",\"name\":\"totalClaims\",\"type\":\"uint32\"},{\"internalType\":\"uint32\",\"name\":\"remainingClaims\",\"type\":\"uint32\"},{\"internalType\":\"uint32\",\"name\":\"claimIndex\",\"type\":\"uint32\"},{\"internalType\":\"uint64\",\"name\":\"expiry\",\"type\":\"uint64\"},{\"internalType\":\"bytes32\",\"name\":\"roomIdHash\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"recipient\",\"type\":\"address\"}],\"internalType\":\"structEnvelope\",\"name\":\"\",\"type\":\"tuple\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"name\":\"hasClaimed\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"envelopeId\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"user\",\"type\":\"address\"}],\"name\":\"hasUserClaimed\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"nextEnvelopeId\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"owner\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"envelopeId\",\"type\":\"uint256\"}],\"name\":\"refundEnvelope\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"refundAmount\",\"type\":\"uint256\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"treasury\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint16\",\"name\":\"_feeBps\",\"type\":\"uint16\"}],\"name\":\"updateFeeBps\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_treasury\",\"type\":\"address\"}],\"name\":\"updateTreasury\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
+	ID:  "RedEnvelope",
+}
+
+// RedEnvelope is an auto generated Go binding around an Ethereum contract.
+type RedEnvelope struct {
+	abi abi.ABI
+}
+
+// NewRedEnvelope creates a new instance of RedEnvelope.
+func NewRedEnvelope() *RedEnvelope {
+	parsed, err := RedEnvelopeMetaData.ParseABI()
+	if err != nil {
+		panic(errors.New("invalid ABI: " + err.Error()))
+	}
+	return &RedEnvelope{abi: *parsed}
+}
+
+// Instance creates a wrapper for a deployed contract instance at the given address.
+// Use this to create the instance object passed to abigen v2 library functions Call, Transact, etc.
+func (c *RedEnvelope) Instance(backend bind.ContractBackend, addr common.Address) *bind.BoundContract {
+	return bind.NewBoundContract(addr, c.abi, backend, backend, backend)
+}
+
+// PackConstructor is the Go binding used to pack the parameters required for
+// contract deployment.
+//
+// Solidity: constructor(address _treasury, uint16 _feeBps) returns()
+func (redEnvelope *RedEnvelope) PackConstructor(_treasury common.Address, _feeBps uint16) []byte {
+	enc, err := redEnvelope.abi.Pack("", _treasury, _feeBps)
+	if err != nil {
+		panic(err)
+	}
+	return enc
+}
+
+// PackBPSDENOMINATOR is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0xe1a45218.  This method will panic if any
+// invalid/nil inputs are passed.
+//
+// Solidity: function BPS_DENOMINATOR() view returns(uint256)
+func (redEnvelope *RedEnvelope) PackBPSDENOMINATOR() []byte {
+	enc, err := redEnvelope.abi.Pack("BPS_DENOMINATOR")
+	if err != nil {
+		panic(err)
+	}
+	return enc
+}
+
+// TryPackBPSDENOMINATOR is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0xe1a45218.  This method will return an error
+// if any inputs are invalid/nil.
+//
+// Solidity: function BPS_DENOMINATOR() view returns(uint256)
+func (redEnvelope *RedEnvelope) TryPackBPSDENOMINATOR() ([]byte, error) {
+	return redEnvelope.abi.Pack("BPS_DENOMINATOR")
+}
+
+// UnpackBPSDENOMINATOR is the Go binding that unpacks the parameters returned
+// from invoking the contract method with ID 0xe1a45218.
+//
+// Solidity: function BPS_DENOMINATOR() view returns(uint256)
+func (redEnvelope *RedEnvelope) UnpackBPSDENOMINATOR(data []byte) (*big.Int, error) {
+	out, err := redEnvelope.abi.Unpack("BPS_DENOMINATOR", data)
+	if err != nil {
+		return new(big.Int), err
+	}
+	out0 := abi.ConvertType(out[0], new(big.Int)).(*big.Int)
+	return out0, nil
+}
+
+// PackClaimEnvelope is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0x63bc90f1.  This method will panic if any
+// invalid/nil inputs are passed.
+//
+// Solidity: function claimEnvelope(uint256 envelopeId) returns(uint256 payout)
+func (redEnvelope *RedEnvelope) PackClaimEnvelope(envelopeId *big.Int) []byte {
+	enc, err := redEnvelope.abi.Pack("claimEnvelope", envelopeId)
+	if err != nil {
+		panic(err)
+	}
+	return enc
+}
+
+// TryPackClaimEnvelope is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0x63bc90f1.  This method will return an error
+// if any inputs are invalid/nil.
+//
+// Solidity: function claimEnvelope(uint256 envelopeId) returns(uint256 payout)
+func (redEnvelope *RedEnvelope) TryPackClaimEnvelope(envelopeId *big.Int) ([]byte, error) {
+	return redEnvelope.abi.Pack("claimEnvelope", envelopeId)
+}
+
+// UnpackClaimEnvelope is the Go binding that unpacks the parameters returned
+// from invoking the contract method with ID 0x63bc90f1.
+//
+// Solidity: function claimEnvelope(uint256 envelopeId) returns(uint256 payout)
+func (redEnvelope *RedEnvelope) UnpackClaimEnvelope(data []byte) (*big.Int, error) {
+	out, err := redEnvelope.abi.Unpack("claimEnvelope", data)
+	if err != nil {
+		return new(big.Int), err
+	}
+	out0 := abi.ConvertType(out[0], new(big.Int)).(*big.Int)
+	return out0, nil
+}
+
+// PackCreateEnvelope is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0xee5b0607.  This method will panic if any
+// invalid/nil inputs are passed.
+//
+// Solidity: function createEnvelope(uint8 kind, address token, uint32 totalClaims, uint256 amountPerClaimOrPot, uint64 expiry, bytes32 roomIdHash, address recipient) payable returns(uint256 envelopeId)
+func (redEnvelope *RedEnvelope) PackCreateEnvelope(kind uint8, token common.Address, totalClaims uint32, amountPerClaimOrPot *big.Int, expiry uint64, roomIdHash [32]byte, recipient common.Address) []byte {
+	enc, err := redEnvelope.abi.Pack("createEnvelope", kind, token, totalClaims, amountPerClaimOrPot, expiry, roomIdHash, recipient)
+	if err != nil {
+		panic(err)
+	}
+	return enc
+}
+
+// TryPackCreateEnvelope is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0xee5b0607.  This method will return an error
+// if any inputs are invalid/nil.
+//
+// Solidity: function createEnvelope(uint8 kind, address token, uint32 totalClaims, uint256 amountPerClaimOrPot, uint64 expiry, bytes32 roomIdHash, address recipient) payable returns(uint256 envelopeId)
+func (redEnvelope *RedEnvelope) TryPackCreateEnvelope(kind uint8, token common.Address, totalClaims uint32, amountPerClaimOrPot *big.Int, expiry uint64, roomIdHash [32]byte, recipient common.Address) ([]byte, error) {
+	return redEnvelope.abi.Pack("createEnvelope", kind, token, totalClaims, amountPerClaimOrPot, expiry, roomIdHash, recipient)
+}
+
+// UnpackCreateEnvelope is the Go binding that unpacks the parameters returned
+// from invoking the contract method with ID 0xee5b0607.
+//
+// Solidity: function createEnvelope(uint8 kind, address token, uint32 totalClaims, uint256 amountPerClaimOrPot, uint64 expiry, bytes32 roomIdHash, address recipient) payable returns(uint256 envelopeId)
+func (redEnvelope *RedEnvelope) UnpackCreateEnvelope(data []byte) (*big.Int, error) {
+	out, err := redEnvelope.abi.Unpack("createEnvelope", data)
+	if err != nil {
+		return new(big.Int), err
+	}
+	out0 := abi.ConvertType(out[0], new(big.Int)).(*big.Int)
+	return out0, nil
+}
+
+// PackEnvelopes is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0x1df95786.  This method will panic if any
+// invalid/nil inputs are passed.
+//
+// Solidity: function envelopes(uint256 ) view returns(address creator, address token, uint8 kind, uint256 amountPerClaim, uint256 remainingAmount, uint32 totalClaims, uint32 remainingClaims, uint32 claimIndex, uint64 expiry, bytes32 roomIdHash, address recipient)
+func (redEnvelope *RedEnvelope) PackEnvelopes(arg0 *big.Int) []byte {
+	enc, err := redEnvelope.abi.Pack("envelopes", arg0)
+	if err != nil {
+		panic(err)
+	}
+	return enc
+}
+
+// TryPackEnvelopes is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0x1df95786.  This method will return an error
+// if any inputs are invalid/nil.
+//
+// Solidity: function envelopes(uint256 ) view returns(address creator, address token, uint8 kind, uint256 amountPerClaim, uint256 remainingAmount, uint32 totalClaims, uint32 remainingClaims, uint32 claimIndex, uint64 expiry, bytes32 roomIdHash, address recipient)
+func (redEnvelope *RedEnvelope) TryPackEnvelopes(arg0 *big.Int) ([]byte, error) {
+	return redEnvelope.abi.Pack("envelopes", arg0)
+}
+
+// EnvelopesOutput serves as a container for the return parameters of contract
+// method Envelopes.
+type EnvelopesOutput struct {
+	Creator         common.Address
+	Token           common.Address
+	Kind            uint8
+	AmountPerClaim  *big.Int
+	RemainingAmount *big.Int
+	TotalClaims     uint32
+	RemainingClaims uint32
+	ClaimIndex      uint32
+	Expiry          uint64
+	RoomIdHash      [32]byte
+	Recipient       common.Address
+}
+
+// UnpackEnvelopes is the Go binding that unpacks the parameters returned
+// from invoking the contract method with ID 0x1df95786.
+//
+// Solidity: function envelopes(uint256 ) view returns(address creator, address token, uint8 kind, uint256 amountPerClaim, uint256 remainingAmount, uint32 totalClaims, uint32 remainingClaims, uint32 claimIndex, uint64 expiry, bytes32 roomIdHash, address recipient)
+func (redEnvelope *RedEnvelope) UnpackEnvelopes(data []byte) (EnvelopesOutput, error) {
+	out, err := redEnvelope.abi.Unpack("envelopes", data)
+	outstruct := new(EnvelopesOutput)
+	if err != nil {
+		return *outstruct, err
+	}
+	outstruct.Creator = *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+	outstruct.Token = *abi.ConvertType(out[1], new(common.Address)).(*common.Address)
+	outstruct.Kind = *abi.ConvertType(out[2], new(uint8)).(*uint8)
+	outstruct.AmountPerClaim = abi.ConvertType(out[3], new(big.Int)).(*big.Int)
+	outstruct.RemainingAmount = abi.ConvertType(out[4], new(big.Int)).(*big.Int)
+	outstruct.TotalClaims = *abi.ConvertType(out[5], new(uint32)).(*uint32)
+	outstruct.RemainingClaims = *abi.ConvertType(out[6], new(uint32)).(*uint32)
+	outstruct.ClaimIndex = *abi.ConvertType(out[7], new(uint32)).(*uint32)
+	outstruct.Expiry = *abi.ConvertType(out[8], new(uint64)).(*uint64)
+	outstruct.RoomIdHash = *abi.ConvertType(out[9], new([32]byte)).(*[32]byte)
+	outstruct.Recipient = *abi.ConvertType(out[10], new(common.Address)).(*common.Address)
+	return *outstruct, nil
+}
+
+// PackFeeBps is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0x24a9d853.  This method will panic if any
+// invalid/nil inputs are passed.
+//
+// Solidity: function feeBps() view returns(uint16)
+func (redEnvelope *RedEnvelope) PackFeeBps() []byte {
+	enc, err := redEnvelope.abi.Pack("feeBps")
+	if err != nil {
+		panic(err)
+	}
+	return enc
+}
+
+// TryPackFeeBps is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0x24a9d853.  This method will return an error
+// if any inputs are invalid/nil.
+//
+// Solidity: function feeBps() view returns(uint16)
+func (redEnvelope *RedEnvelope) TryPackFeeBps() ([]byte, error) {
+	return redEnvelope.abi.Pack("feeBps")
+}
+
+// UnpackFeeBps is the Go binding that unpacks the parameters returned
+// from invoking the contract method with ID 0x24a9d853.
+//
+// Solidity: function feeBps() view returns(uint16)
+func (redEnvelope *RedEnvelope) UnpackFeeBps(data []byte) (uint16, error) {
+	out, err := redEnvelope.abi.Unpack("feeBps", data)
+	if err != nil {
+		return *new(uint16), err
+	}
+	out0 := *abi.ConvertType(out[0], new(uint16)).(*uint16)
+	return out0, nil
+}
+
+// PackGetEnvelope is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0xcf4a748c.  This method will panic if any
+// invalid/nil inputs are passed.
+//
+// Solidity: function getEnvelope(uint256 envelopeId) view returns((address,address,uint8,uint256,uint256,uint32,uint32,uint32,uint64,bytes32,address))
+func (redEnvelope *RedEnvelope) PackGetEnvelope(envelopeId *big.Int) []byte {
+	enc, err := redEnvelope.abi.Pack("getEnvelope", envelopeId)
+	if err != nil {
+		panic(err)
+	}
+	return enc
+}
+
+// TryPackGetEnvelope is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0xcf4a748c.  This method will return an error
+// if any inputs are invalid/nil.
+//
+// Solidity: function getEnvelope(uint256 envelopeId) view returns((address,address,uint8,uint256,uint256,uint32,uint32,uint32,uint64,bytes32,address))
+func (redEnvelope *RedEnvelope) TryPackGetEnvelope(envelopeId *big.Int) ([]byte, error) {
+	return redEnvelope.abi.Pack("getEnvelope", envelopeId)
+}
+
+// UnpackGetEnvelope is the Go binding that unpacks the parameters returned
+// from invoking the contract method with ID 0xcf4a748c.
+//
+// Solidity: function getEnvelope(uint256 envelopeId) view returns((address,address,uint8,uint256,uint256,uint32,uint32,uint32,uint64,bytes32,address))
+func (redEnvelope *RedEnvelope) UnpackGetEnvelope(data []byte) (Envelope, error) {
+	out, err := redEnvelope.abi.Unpack("getEnvelope", data)
+	if err != nil {
+		return *new(Envelope), err
+	}
+	out0 := *abi.ConvertType(out[0], new(Envelope)).(*Envelope)
+	return out0, nil
+}
+
+// PackHasClaimed is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0x873f6f9e.  This method will panic if any
+// invalid/nil inputs are passed.
+//
+// Solidity: function hasClaimed(uint256 , address ) view returns(bool)
+func (redEnvelope *RedEnvelope) PackHasClaimed(arg0 *big.Int, arg1 common.Address) []byte {
+	enc, err := redEnvelope.abi.Pack("hasClaimed", arg0, arg1)
+	if err != nil {
+		panic(err)
+	}
+	return enc
+}
+
+// TryPackHasClaimed is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0x873f6f9e.  This method will return an error
+// if any inputs are invalid/nil.
+//
+// Solidity: function hasClaimed(uint256 , address ) view returns(bool)
+func (redEnvelope *RedEnvelope) TryPackHasClaimed(arg0 *big.Int, arg1 common.Address) ([]byte, error) {
+	return redEnvelope.abi.Pack("hasClaimed", arg0, arg1)
+}
+
+// UnpackHasClaimed is the Go binding that unpacks the parameters returned
+// from invoking the contract method with ID 0x873f6f9e.
+//
+// Solidity: function hasClaimed(uint256 , address ) view returns(bool)
+func (redEnvelope *RedEnvelope) UnpackHasClaimed(data []byte) (bool, error) {
+	out, err := redEnvelope.abi.Unpack("hasClaimed", data)
+	if err != nil {
+		return *new(bool), err
+	}
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+	return out0, nil
+}
+
+// PackHasUserClaimed is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0x07c7a72d.  This method will panic if any
+// invalid/nil inputs are passed.
+//
+// Solidity: function hasUserClaimed(uint256 envelopeId, address user) view returns(bool)
+func (redEnvelope *RedEnvelope) PackHasUserClaimed(envelopeId *big.Int, user common.Address) []byte {
+	enc, err := redEnvelope.abi.Pack("hasUserClaimed", envelopeId, user)
+	if err != nil {
+		panic(err)
+	}
+	return enc
+}
+
+// TryPackHasUserClaimed is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0x07c7a72d.  This method will return an error
+// if any inputs are invalid/nil.
+//
+// Solidity: function hasUserClaimed(uint256 envelopeId, address user) view returns(bool)
+func (redEnvelope *RedEnvelope) TryPackHasUserClaimed(envelopeId *big.Int, user common.Address) ([]byte, error) {
+	return redEnvelope.abi.Pack("hasUserClaimed", envelopeId, user)
+}
+
+// UnpackHasUserClaimed is the Go binding that unpacks the parameters returned
+// from invoking the contract method with ID 0x07c7a72d.
+//
+// Solidity: function hasUserClaimed(uint256 envelopeId, address user) view returns(bool)
+func (redEnvelope *RedEnvelope) UnpackHasUserClaimed(data []byte) (bool, error) {
+	out, err := redEnvelope.abi.Unpack("hasUserClaimed", data)
+	if err != nil {
+		return *new(bool), err
+	}
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+	return out0, nil
+}
+
+// PackNextEnvelopeId is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0xb0daacc8.  This method will panic if any
+// invalid/nil inputs are passed.
+//
+// Solidity: function nextEnvelopeId() view returns(uint256)
+func (redEnvelope *RedEnvelope) PackNextEnvelopeId() []byte {
+	enc, err := redEnvelope.abi.Pack("nextEnvelopeId")
+	if err != nil {
+		panic(err)
+	}
+	return enc
+}
+
+// TryPackNextEnvelopeId is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0xb0daacc8.  This method will return an error
+// if any inputs are invalid/nil.
+//
+// Solidity: function nextEnvelopeId() view returns(uint256)
+func (redEnvelope *RedEnvelope) TryPackNextEnvelopeId() ([]byte, error) {
+	return redEnvelope.abi.Pack("nextEnvelopeId")
+}
+
+// UnpackNextEnvelopeId is the Go binding that unpacks the parameters returned
+// from invoking the contract method with ID 0xb0daacc8.
+//
+// Solidity: function nextEnvelopeId() view returns(uint256)
+func (redEnvelope *RedEnvelope) UnpackNextEnvelopeId(data []byte) (*big.Int, error) {
+	out, err := redEnvelope.abi.Unpack("nextEnvelopeId", data)
+	if err != nil {
+		return new(big.Int), err
+	}
+	out0 := abi.ConvertType(out[0], new(big.Int)).(*big.Int)
+	return out0, nil
+}
+
+// PackOwner is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0x8da5cb5b.  This method will panic if any
+// invalid/nil inputs are passed.
+//
+// Solidity: function owner() view returns(address)
+func (redEnvelope *RedEnvelope) PackOwner() []byte {
+	enc, err := redEnvelope.abi.Pack("owner")
+	if err != nil {
+		panic(err)
+	}
+	return enc
+}
+
+// TryPackOwner is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0x8da5cb5b.  This method will return an error
+// if any inputs are invalid/nil.
+//
+// Solidity: function owner() view returns(address)
+func (redEnvelope *RedEnvelope) TryPackOwner() ([]byte, error) {
+	return redEnvelope.abi.Pack("owner")
+}
+
+// UnpackOwner is the Go binding that unpacks the parameters returned
+// from invoking the contract method with ID 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (redEnvelope *RedEnvelope) UnpackOwner(data []byte) (common.Address, error) {
+	out, err := redEnvelope.abi.Unpack("owner", data)
+	if err != nil {
+		return *new(common.Address), err
+	}
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+	return out0, nil
+}
+
+// PackRefundEnvelope is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0x0ac9b108.  This method will panic if any
+// invalid/nil inputs are passed.
+//
+// Solidity: function refundEnvelope(uint256 envelopeId) returns(uint256 refundAmount)
+func (redEnvelope *RedEnvelope) PackRefundEnvelope(envelopeId *big.Int) []byte {
+	enc, err := redEnvelope.abi.Pack("refundEnvelope", envelopeId)
+	if err != nil {
+		panic(err)
+	}
+	return enc
+}
+
+// TryPackRefundEnvelope is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0x0ac9b108.  This method will return an error
+// if any inputs are invalid/nil.
+//
+// Solidity: function refundEnvelope(uint256 envelopeId) returns(uint256 refundAmount)
+func (redEnvelope *RedEnvelope) TryPackRefundEnvelope(envelopeId *big.Int) ([]byte, error) {
+	return redEnvelope.abi.Pack("refundEnvelope", envelopeId)
+}
+
+// UnpackRefundEnvelope is the Go binding that unpacks the parameters returned
+// from invoking the contract method with ID 0x0ac9b108.
+//
+// Solidity: function refundEnvelope(uint256 envelopeId) returns(uint256 refundAmount)
+func (redEnvelope *RedEnvelope) UnpackRefundEnvelope(data []byte) (*big.Int, error) {
+	out, err := redEnvelope.abi.Unpack("refundEnvelope", data)
+	if err != nil {
+		return new(big.Int), err
+	}
+	out0 := abi.ConvertType(out[0], new(big.Int)).(*big.Int)
+	return out0, nil
+}
+
+// PackTreasury is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0x61d027b3.  This method will panic if any
+// invalid/nil inputs are passed.
+//
+// Solidity: function treasury() view returns(address)
+func (redEnvelope *RedEnvelope) PackTreasury() []byte {
+	enc, err := redEnvelope.abi.Pack("treasury")
+	if err != nil {
+		panic(err)
+	}
+	return enc
+}
+
+// TryPackTreasury is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0x61d027b3.  This method will return an error
+// if any inputs are invalid/nil.
+//
+// Solidity: function treasury() view returns(address)
+func (redEnvelope *RedEnvelope) TryPackTreasury() ([]byte, error) {
+	return redEnvelope.abi.Pack("treasury")
+}
+
+// UnpackTreasury is the Go binding that unpacks the parameters returned
+// from invoking the contract method with ID 0x61d027b3.
+//
+// Solidity: function treasury() view returns(address)
+func (redEnvelope *RedEnvelope) UnpackTreasury(data []byte) (common.Address, error) {
+	out, err := redEnvelope.abi.Unpack("treasury", data)
+	if err != nil {
+		return *new(common.Address), err
+	}
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+	return out0, nil
+}
+
+// PackUpdateFeeBps is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0xa7bf4936.  This method will panic if any
+// invalid/nil inputs are passed.
+//
+// Solidity: function updateFeeBps(uint16 _feeBps) returns()
+func (redEnvelope *RedEnvelope) PackUpdateFeeBps(feeBps uint16) []byte {
+	enc, err := redEnvelope.abi.Pack("updateFeeBps", feeBps)
+	if err != nil {
+		panic(err)
+	}
+	return enc
+}
+
+// TryPackUpdateFeeBps is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0xa7bf4936.  This method will return an error
+// if any inputs are invalid/nil.
+//
+// Solidity: function updateFeeBps(uint16 _feeBps) returns()
+func (redEnvelope *RedEnvelope) TryPackUpdateFeeBps(feeBps uint16) ([]byte, error) {
+	return redEnvelope.abi.Pack("updateFeeBps", feeBps)
+}
+
+// PackUpdateTreasury is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0x7f51bb1f.  This method will panic if any
+// invalid/nil inputs are passed.
+//
+// Solidity: function updateTreasury(address _treasury) returns()
+func (redEnvelope *RedEnvelope) PackUpdateTreasury(treasury common.Address) []byte {
+	enc, err := redEnvelope.abi.Pack("updateTreasury", treasury)
+	if err != nil {
+		panic(err)
+	}
+	return enc
+}
+
+// TryPackUpdateTreasury is the Go binding used to pack the parameters required for calling
+// the contract method with ID 0x7f51bb1f.  This method will return an error
+// if any inputs are invalid/nil.
+//
+// Solidity: function updateTreasury(address _treasury) returns()
+func (redEnvelope *RedEnvelope) TryPackUpdateTreasury(treasury common.Address) ([]byte, error) {
+	return redEnvelope.abi.Pack("updateTreasury", treasury)
+}
+
+// RedEnvelopeEnvelopeClaimed represents a EnvelopeClaimed event raised by the RedEnvelope contract.
+type RedEnvelopeEnvelopeClaimed struct {
+	EnvelopeId *big.Int
+	Claimer    common.Address
+	Payout     *big.Int
+	ClaimIndex uint32
+	Raw        *types.Log // Blockchain specific contextual infos
+}
+
+const RedEnvelopeEnvelopeClaimedEventName = "EnvelopeClaimed"
+
+// ContractEventName returns the user-defined event name.
+func (RedEnvelopeEnvelopeClaimed) ContractEventName() string {
+	return RedEnvelopeEnvelopeClaimedEventName
+}
+
+// UnpackEnvelopeClaimedEvent is the Go binding that unpacks the event data emitted
+// by contract.
+//
+// Solidity: event EnvelopeClaimed(uint256 envelopeId, address claimer, uint256 payout, uint32 claimIndex)
+func (redEnvelope *RedEnvelope) UnpackEnvelopeClaimedEvent(log *types.Log) (*RedEnvelopeEnvelopeClaimed, error) {
+	event := "EnvelopeClaimed"
+	if len(log.Topics) == 0 || log.Topics[0] != redEnvelope.abi.Events[event].ID {
+		return nil, errors.New("event signature mismatch")
+	}
+	out := new(RedEnvelopeEnvelopeClaimed)
+	if len(log.Data) > 0 {
+		if err := redEnvelope.abi.UnpackIntoInterface(out, event, log.Data); err != nil {
+			return nil, err
+		}
+	}
+	var indexed abi.Arguments
+	for _, arg := range redEnvelope.abi.Events[event].Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if err := abi.ParseTopics(out, indexed, log.Topics[1:]); err != nil {
+		return nil, err
+	}
+	out.Raw = log
+	return out, nil
+}
+
+// RedEnvelopeEnvelopeCreated represents a EnvelopeCreated event raised by the RedEnvelope contract.
+type RedEnvelopeEnvelopeCreated struct {
+	EnvelopeId  *big.Int
+	Creator     common.Address
+	Kind        uint8
+	Token       common.Address
+	NetPot      *big.Int
+	TotalClaims uint32
+	Expiry      uint64
+	FeeAmount   *big.Int
+	RoomIdHash  [32]byte
+	Recipient   common.Address
+	Raw         *types.Log // Blockchain specific contextual infos
+}
+
+const RedEnvelopeEnvelopeCreatedEventName = "EnvelopeCreated"
+
+// ContractEventName returns the user-defined event name.
+func (RedEnvelopeEnvelopeCreated) ContractEventName() string {
+	return RedEnvelopeEnvelopeCreatedEventName
+}
+
+// UnpackEnvelopeCreatedEvent is the Go binding that unpacks the event data emitted
+// by contract.
+//
+// Solidity: event EnvelopeCreated(uint256 envelopeId, address creator, uint8 kind, address token, uint256 netPot, uint32 totalClaims, uint64 expiry, uint256 feeAmount, bytes32 roomIdHash, address recipient)
+func (redEnvelope *RedEnvelope) UnpackEnvelopeCreatedEvent(log *types.Log) (*RedEnvelopeEnvelopeCreated, error) {
+	event := "EnvelopeCreated"
+	if len(log.Topics) == 0 || log.Topics[0] != redEnvelope.abi.Events[event].ID {
+		return nil, errors.New("event signature mismatch")
+	}
+	out := new(RedEnvelopeEnvelopeCreated)
+	if len(log.Data) > 0 {
+		if err := redEnvelope.abi.UnpackIntoInterface(out, event, log.Data); err != nil {
+			return nil, err
+		}
+	}
+	var indexed abi.Arguments
+	for _, arg := range redEnvelope.abi.Events[event].Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if err := abi.ParseTopics(out, indexed, log.Topics[1:]); err != nil {
+		return nil, err
+	}
+	out.Raw = log
+	return out, nil
+}
+
+// RedEnvelopeEnvelopeRefunded represents a EnvelopeRefunded event raised by the RedEnvelope contract.
+type RedEnvelopeEnvelopeRefunded struct {
+	EnvelopeId   *big.Int
+	RefundAmount *big.Int
+	Raw          *types.Log // Blockchain specific contextual infos
+}
+
+const RedEnvelopeEnvelopeRefundedEventName = "EnvelopeRefunded"
+
+// ContractEventName returns the user-defined event name.
+func (RedEnvelopeEnvelopeRefunded) ContractEventName() string {
+	return RedEnvelopeEnvelopeRefundedEventName
+}
+
+// UnpackEnvelopeRefundedEvent is the Go binding that unpacks the event data emitted
+// by contract.
+//
+// Solidity: event EnvelopeRefunded(uint256 envelopeId, uint256 refundAmount)
+func (redEnvelope *RedEnvelope) UnpackEnvelopeRefundedEvent(log *types.Log) (*RedEnvelopeEnvelopeRefunded, error) {
+	event := "EnvelopeRefunded"
+	if len(log.Topics) == 0 || log.Topics[0] != redEnvelope.abi.Events[event].ID {
+		return nil, errors.New("event signature mismatch")
+	}
+	out := new(RedEnvelopeEnvelopeRefunded)
+	if len(log.Data) > 0 {
+		if err := redEnvelope.abi.UnpackIntoInterface(out, event, log.Data); err != nil {
+			return nil, err
+		}
+	}
+	var indexed abi.Arguments
+	for _, arg := range redEnvelope.abi.Events[event].Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if err := abi.ParseTopics(out, indexed, log.Topics[1:]); err != nil {
+		return nil, err
+	}
+	out.Raw = log
+	return out, nil
+}
+
+// UnpackError attempts to decode the provided error data using user-defined
+// error definitions.
+func (redEnvelope *RedEnvelope) UnpackError(raw []byte) (any, error) {
+	if bytes.Equal(raw[:4], redEnvelope.abi.Errors["AlreadyClaimed"].ID.Bytes()[:4]) {
+		return redEnvelope.UnpackAlreadyClaimedError(raw[4:])
+	}
+	if bytes.Equal(raw[:4], redEnvelope.abi.Errors["EnvelopeExpired"].ID.Bytes()[:4]) {
+		return redEnvelope.UnpackEnvelopeExpiredError(raw[4:])
+	}
+	if bytes.Equal(raw[:4], redEnvelope.abi.Errors["EnvelopeNotFound"].ID.Bytes()[:4]) {
+		return redEnvelope.UnpackEnvelopeNotFoundError(raw[4:])
+	}
+	if bytes.Equal(raw[:4], redEnvelope.abi.Errors["InvalidParameters"].ID.Bytes()[:4]) {
+		return redEnvelope.UnpackInvalidParametersError(raw[4:])
+	}
+	if bytes.Equal(raw[:4], redEnvelope.abi.Errors["NotEligible"].ID.Bytes()[:4]) {
+		return redEnvelope.UnpackNotEligibleError(raw[4:])
+	}
+	if bytes.Equal(raw[:4], redEnvelope.abi.Errors["TransferFailed"].ID.Bytes()[:4]) {
+		return redEnvelope.UnpackTransferFailedError(raw[4:])
+	}
+	if bytes.Equal(raw[:4], redEnvelope.abi.Errors["Unauthorized"].ID.Bytes()[:4]) {
+		return redEnvelope.UnpackUnauthorizedError(raw[4:])
+	}
+	return nil, errors.New("Unknown error")
+}
+
+// RedEnvelopeAlreadyClaimed represents a AlreadyClaimed error raised by the RedEnvelope contract.
+type RedEnvelopeAlreadyClaimed struct {
+}
+
+// ErrorID returns the hash of canonical representation of the error's signature.
+//
+// Solidity: error AlreadyClaimed()
+func RedEnvelopeAlreadyClaimedErrorID() common.Hash {
+	return common.HexToHash("0x646cf558a545d59f8a09cbf8a0eb8a9332f1d17834843b20fc8d154839dc46d7")
+}
+
+// UnpackAlreadyClaimedError is the Go binding used to decode the provided
+// error data into the corresponding Go error struct.
+//
+// Solidity: error AlreadyClaimed()
+func (redEnvelope *RedEnvelope) UnpackAlreadyClaimedError(raw []byte) (*RedEnvelopeAlreadyClaimed, error) {
+	out := new(RedEnvelopeAlreadyClaimed)
+	if err := redEnvelope.abi.UnpackIntoInterface(out, "AlreadyClaimed", raw); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RedEnvelopeEnvelopeExpired represents a EnvelopeExpired error raised by the RedEnvelope contract.
+type RedEnvelopeEnvelopeExpired struct {
+}
+
+// ErrorID returns the hash of canonical representation of the error's signature.
+//
+// Solidity: error EnvelopeExpired()
+func RedEnvelopeEnvelopeExpiredErrorID() common.Hash {
+	return common.HexToHash("0xfd427c09299480c6d6e10b681000deed03cb2da6b0be76e216d9951f3c8653a9")
+}
+
+// UnpackEnvelopeExpiredError is the Go binding used to decode the provided
+// error data into the corresponding Go error struct.
+//
+// Solidity: error EnvelopeExpired()
+func (redEnvelope *RedEnvelope) UnpackEnvelopeExpiredError(raw []byte) (*RedEnvelopeEnvelopeExpired, error) {
+	out := new(RedEnvelopeEnvelopeExpired)
+	if err := redEnvelope.abi.UnpackIntoInterface(out, "EnvelopeExpired", raw); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RedEnvelopeEnvelopeNotFound represents a EnvelopeNotFound error raised by the RedEnvelope contract.
+type RedEnvelopeEnvelopeNotFound struct {
+}
+
+// ErrorID returns the hash of canonical representation of the error's signature.
+//
+// Solidity: error EnvelopeNotFound()
+func RedEnvelopeEnvelopeNotFoundErrorID() common.Hash {
+	return common.HexToHash("0xbef4a2e859d87cd26e3b2e48f7c80d7fec1b5906e2b94ca7d8551db5bb7f6815")
+}
+
+// UnpackEnvelopeNotFoundError is the Go binding used to decode the provided
+// error data into the corresponding Go error struct.
+//
+// Solidity: error EnvelopeNotFound()
+func (redEnvelope *RedEnvelope) UnpackEnvelopeNotFoundError(raw []byte) (*RedEnvelopeEnvelopeNotFound, error) {
+	out := new(RedEnvelopeEnvelopeNotFound)
+	if err := redEnvelope.abi.UnpackIntoInterface(out, "EnvelopeNotFound", raw); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RedEnvelopeInvalidParameters represents a InvalidParameters error raised by the RedEnvelope contract.
+type RedEnvelopeInvalidParameters struct {
+}
+
+// ErrorID returns the hash of canonical representation of the error's signature.
+//
+// Solidity: error InvalidParameters()
+func RedEnvelopeInvalidParametersErrorID() common.Hash {
+	return common.HexToHash("0xe52390909f87229f872871b8ab57a0e139663fb951ac6346f3852d332ca460b7")
+}
+
+// UnpackInvalidParametersError is the Go binding used to decode the provided
+// error data into the corresponding Go error struct.
+//
+// Solidity: error InvalidParameters()
+func (redEnvelope *RedEnvelope) UnpackInvalidParametersError(raw []byte) (*RedEnvelopeInvalidParameters, error) {
+	out := new(RedEnvelopeInvalidParameters)
+	if err := redEnvelope.abi.UnpackIntoInterface(out, "InvalidParameters", raw); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RedEnvelopeNotEligible represents a NotEligible error raised by the RedEnvelope contract.
+type RedEnvelopeNotEligible struct {
+}
+
+// ErrorID returns the hash of canonical representation of the error's signature.
+//
+// Solidity: error NotEligible()
+func RedEnvelopeNotEligibleErrorID() common.Hash {
+	return common.HexToHash("0xf8eb54de284c97bfa6191d3cb5e2a0cef222e6ce29e2e7ed2edafab612c84e14")
+}
+
+// UnpackNotEligibleError is the Go binding used to decode the provided
+// error data into the corresponding Go error struct.
+//
+// Solidity: error NotEligible()
+func (redEnvelope *RedEnvelope) UnpackNotEligibleError(raw []byte) (*RedEnvelopeNotEligible, error) {
+	out := new(RedEnvelopeNotEligible)
+	if err := redEnvelope.abi.UnpackIntoInterface(out, "NotEligible", raw); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RedEnvelopeTransferFailed represents a TransferFailed error raised by the RedEnvelope contract.
+type RedEnvelopeTransferFailed struct {
+}
+
+// ErrorID returns the hash of canonical representation of the error's signature.
+//
+// Solidity: error TransferFailed()
+func RedEnvelopeTransferFailedErrorID() common.Hash {
+	return common.HexToHash("0x90b8ec1877afffd816d05d9b13947f3ff18ec5851c38bad15ec2b710f92391b1")
+}
+
+// UnpackTransferFailedError is the Go binding used to decode the provided
+// error data into the corresponding Go error struct.
+//
+// Solidity: error TransferFailed()
+func (redEnvelope *RedEnvelope) UnpackTransferFailedError(raw []byte) (*RedEnvelopeTransferFailed, error) {
+	out := new(RedEnvelopeTransferFailed)
+	if err := redEnvelope.abi.UnpackIntoInterface(out, "TransferFailed", raw); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RedEnvelopeUnauthorized represents a Unauthorized error raised by the RedEnvelope contract.
+type RedEnvelopeUnauthorized struct {
+}
+
+// ErrorID returns the hash of canonical representation of the error's signature.
+//
+// Solidity: error Unauthorized()
+func RedEnvelopeUnauthorizedErrorID() common.Hash {
+	return common.HexToHash("0x82b4290015f7ec7256ca2a6247d3c2a89c4865c0e791456df195f40ad0a81367")
+}
+
+// UnpackUnauthorizedError is the Go binding used to decode the provided
+// error data into the corresponding Go error struct.
+//
+// Solidity: error Unauthorized()
+func (redEnvelope *RedEnvelope) UnpackUnauthorizedError(raw []byte) (*RedEnvelopeUnauthorized, error) {
+	out := new(RedEnvelopeUnauthorized)
+	if err := redEnvelope.abi.UnpackIntoInterface(out, "Unauthorized", raw); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,56 @@
+package redenvelope
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+// bip39English wordlist English BIP-39: 2048 kata, nomor baris = nilai 11 bit
+//
+//go:embed bip39_english.txt
+var bip39English string
+
+var (
+	bip39IndexOnce sync.Once
+	bip39Index     map[string]int64
+)
+
+// bip39WordIndex kata -> index di wordlist (dibangun sekali saja)
+func bip39WordIndex() map[string]int64 {
+	bip39IndexOnce.Do(func() {
+		words := strings.Fields(bip39English)
+		bip39Index = make(map[string]int64, len(words))
+		for i, word := range words {
+			bip39Index[word] = int64(i)
+		}
+	})
+	return bip39Index
+}
+
+// checkMnemonic memastikan setiap kata ada di wordlist English dan checksum
+// cocok: n kata = 11n bit, n/3 bit terakhir adalah awal SHA-256(entropy)
+func checkMnemonic(words []string) error {
+	index := bip39WordIndex()
+	bits := new(big.Int)
+	for _, word := range words {
+		i, ok := index[word]
+		if !ok {
+			return fmt.Errorf("invalid mnemonic: %q is not in the BIP-39 English wordlist", word)
+		}
+		bits.Lsh(bits, 11).Or(bits, big.NewInt(i))
+	}
+
+	checksumBits := uint(len(words) / 3)
+	checksum := new(big.Int).And(bits, big.NewInt(1<<checksumBits-1)).Uint64()
+	entropy := new(big.Int).Rsh(bits, checksumBits).FillBytes(make([]byte, len(words)*4/3))
+
+	hash := sha256.Sum256(entropy)
+	if uint64(hash[0]>>(8-checksumBits)) != checksum {
+		return fmt.Errorf("invalid mnemonic: checksum mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package redenvelope
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// TxRequest transaksi unsigned yang siap dikirim wallet lewat
+// eth_sendTransaction. From boleh kosong, wallet yang mengisi.
+type TxRequest struct {
+	From  *common.Address `json:"from,omitempty"`
+	To    common.Address  `json:"to"`
+	Value *hexutil.Big    `json:"value"`
+	Data  hexutil.Bytes   `json:"data"`
+	Gas   hexutil.Uint64  `json:"gas,omitempty"` // Gas limit yang sama dengan RedEnvelopeService
+}
+
+var (
+	parsedABIOnce sync.Once
+	parsedABI     abi.ABI
+	parsedABIErr  error
+)
+
+// contractABI ABI RedEnvelope yang sudah di-parse (sekali saja)
+func contractABI() (abi.ABI, error) {
+	parsedABIOnce.Do(func() {
+		parsedABI, parsedABIErr = abi.JSON(strings.NewReader(RedEnvelopeABI))
+		if parsedABIErr != nil {
+			parsedABIErr = fmt.Errorf("failed to parse ABI: %v", parsedABIErr)
+		}
+	})
+	return parsedABI, parsedABIErr
+}
+
+// ErrInvalidAmount dikembalikan kalau amount createEnvelope nil atau tidak
+// lebih dari nol
+var ErrInvalidAmount = errors.New("amount must be greater than zero")
+
+// checkAmount amount createEnvelope harus > 0; GrossPot tidak menerima nil
+func checkAmount(amount *big.Int) error {
+	if amount == nil || amount.Sign() <= 0 {
+		return ErrInvalidAmount
+	}
+	return nil
+}
+
+// GrossPot jumlah yang ditarik dari creator (msg.value untuk native token):
+//   - GROUP_FIXED: amount × totalClaims
+//   - DIRECT_FIXED & GROUP_RANDOM: amount
+func GrossPot(kind uint8, totalClaims uint32, amount *big.Int) *big.Int {
+	if kind == GROUP_FIXED {
+		return new(big.Int).Mul(amount, big.NewInt(int64(totalClaims)))
+	}
+	return new(big.Int).Set(amount)
+}
+
+// BuildCreateEnvelopeTx membangun TxRequest createEnvelope dengan aturan
+// amount dan msg.value yang sama persis dengan CreateEnvelope
+func BuildCreateEnvelopeTx(
+	contract common.Address,
+	kind uint8,
+	token common.Address,
+	totalClaims uint32,
+	amount *big.Int,
+	expiryDuration time.Duration,
+	roomIdHash [32]byte,
+	recipient common.Address,
+) (*TxRequest, error) {
+	if err := checkAmount(amount); err != nil {
+		return nil, err
+	}
+	expiry := uint64(time.Now().Add(expiryDuration).Unix())
+	return buildTxRequest(contract, createEnvelopeCall(kind, token, totalClaims, amount, expiry, roomIdHash, recipient))
+}
+
+// BuildClaimEnvelopeTx membangun TxRequest claimEnvelope
+func BuildClaimEnvelopeTx(contract common.Address, envelopeId *big.Int) (*TxRequest, error) {
+	return buildTxRequest(contract, claimEnvelopeCall(envelopeId))
+}
+
+// BuildRefundEnvelopeTx membangun TxRequest refundEnvelope
+func BuildRefundEnvelopeTx(contract common.Address, envelopeId *big.Int) (*TxRequest, error) {
+	return buildTxRequest(contract, refundEnvelopeCall(envelopeId))
+}
+
+func buildTxRequest(contract common.Address, call *writeCall) (*TxRequest, error) {
+	data, err := call.calldata()
+	if err != nil {
+		return nil, err
+	}
+
+	return &TxRequest{
+		To:    contract,
+		Value: (*hexutil.Big)(new(big.Int).Set(call.value)),
+		Data:  data,
+		Gas:   hexutil.Uint64(call.gasLimit),
+	}, nil
+}
+
+// claimEnvelopeCall writeCall claimEnvelope
+func claimEnvelopeCall(envelopeId *big.Int) *writeCall {
+	return &writeCall{
+		method:   "claimEnvelope",
+		value:    big.NewInt(0),
+		gasLimit: 300000,
+		args:     []interface{}{envelopeId},
+		pack: func() ([]byte, error) {
+			return redEnvelope.TryPackClaimEnvelope(envelopeId)
+		},
+	}
+}
+
+// refundEnvelopeCall writeCall refundEnvelope
+func refundEnvelopeCall(envelopeId *big.Int) *writeCall {
+	return &writeCall{
+		method:   "refundEnvelope",
+		value:    big.NewInt(0),
+		gasLimit: 300000,
+		args:     []interface{}{envelopeId},
+		pack: func() ([]byte, error) {
+			return redEnvelope.TryPackRefundEnvelope(envelopeId)
+		},
+	}
+}
+
+// updateFeeBpsCall writeCall updateFeeBps (hanya owner contract)
+func updateFeeBpsCall(feeBps uint16) *writeCall {
+	return &writeCall{
+		method:   "updateFeeBps",
+		value:    big.NewInt(0),
+		gasLimit: 100000,
+		args:     []interface{}{feeBps},
+		pack: func() ([]byte, error) {
+			return redEnvelope.TryPackUpdateFeeBps(feeBps)
+		},
+	}
+}
+
+// updateTreasuryCall writeCall updateTreasury (hanya owner contract)
+func updateTreasuryCall(treasury common.Address) *writeCall {
+	return &writeCall{
+		method:   "updateTreasury",
+		value:    big.NewInt(0),
+		gasLimit: 100000,
+		args:     []interface{}{treasury},
+		pack: func() ([]byte, error) {
+			return redEnvelope.TryPackUpdateTreasury(treasury)
+		},
+	}
+}
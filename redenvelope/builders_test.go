@@ -0,0 +1,97 @@
+package redenvelope
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestGrossPot(t *testing.T) {
+	amount := big.NewInt(100)
+
+	if got := GrossPot(GROUP_FIXED, 5, amount); got.Int64() != 500 {
+		t.Errorf("GROUP_FIXED: expected 500, got %s", got)
+	}
+	if got := GrossPot(GROUP_RANDOM, 5, amount); got.Int64() != 100 {
+		t.Errorf("GROUP_RANDOM: expected 100, got %s", got)
+	}
+	if got := GrossPot(DIRECT_FIXED, 1, amount); got.Int64() != 100 {
+		t.Errorf("DIRECT_FIXED: expected 100, got %s", got)
+	}
+
+	GrossPot(DIRECT_FIXED, 1, amount).SetInt64(0)
+	if amount.Int64() != 100 {
+		t.Error("GrossPot must not alias the amount argument")
+	}
+}
+
+func TestBuildCreateEnvelopeTx(t *testing.T) {
+	contract := common.HexToAddress(testContractAddress)
+	parsed, err := contractABI()
+	if err != nil {
+		t.Fatalf("Failed to parse ABI: %v", err)
+	}
+
+	native, err := BuildCreateEnvelopeTx(contract, GROUP_FIXED, common.Address{}, 4, big.NewInt(250), time.Hour, EmptyRoomIdHash, common.Address{})
+	if err != nil {
+		t.Fatalf("Failed to build native create: %v", err)
+	}
+	if native.To != contract || native.Value.ToInt().Int64() != 1000 {
+		t.Errorf("Unexpected native create request: to=%s value=%s", native.To.Hex(), native.Value.ToInt())
+	}
+	if !bytes.Equal(native.Data[:4], parsed.Methods["createEnvelope"].ID) {
+		t.Errorf("Unexpected selector %x", native.Data[:4])
+	}
+
+	token := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	erc20, err := BuildCreateEnvelopeTx(contract, GROUP_FIXED, token, 4, big.NewInt(250), time.Hour, EmptyRoomIdHash, common.Address{})
+	if err != nil {
+		t.Fatalf("Failed to build token create: %v", err)
+	}
+	if erc20.Value.ToInt().Sign() != 0 {
+		t.Errorf("ERC-20 create must not send value, got %s", erc20.Value.ToInt())
+	}
+
+	for _, tc := range []struct {
+		name   string
+		kind   uint8
+		amount *big.Int
+	}{
+		{"nil fixed", GROUP_FIXED, nil},
+		{"nil random", GROUP_RANDOM, nil},
+		{"zero", GROUP_FIXED, big.NewInt(0)},
+		{"negative", DIRECT_FIXED, big.NewInt(-1)},
+	} {
+		if _, err := BuildCreateEnvelopeTx(contract, tc.kind, common.Address{}, 4, tc.amount, time.Hour, EmptyRoomIdHash, common.Address{}); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("%s: expected ErrInvalidAmount, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestBuildClaimEnvelopeTx_JSON(t *testing.T) {
+	request, err := BuildClaimEnvelopeTx(common.HexToAddress(testContractAddress), big.NewInt(7))
+	if err != nil {
+		t.Fatalf("Failed to build claim: %v", err)
+	}
+
+	encoded, err := json.Marshal(request)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if fields["value"] != "0x0" || fields["gas"] != "0x493e0" {
+		t.Errorf("Unexpected JSON: %s", encoded)
+	}
+	if _, ok := fields["from"]; ok {
+		t.Errorf("from should be omitted when empty: %s", encoded)
+	}
+}
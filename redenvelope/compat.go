@@ -0,0 +1,178 @@
+package redenvelope
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// SelectorCheck hasil pencarian satu function selector di bytecode
+type SelectorCheck struct {
+	Method   string `json:"method"`
+	Selector string `json:"selector"`
+	Found    bool   `json:"found"`
+}
+
+// CompatibilityReport hasil pengecekan contract di ContractAddress terhadap
+// ABI yang dipakai package ini
+type CompatibilityReport struct {
+	Address        common.Address  `json:"address"`
+	ChainID        *big.Int        `json:"chainId"`
+	CodeSize       int             `json:"codeSize"`
+	NextEnvelopeID *big.Int        `json:"nextEnvelopeId,omitempty"`
+	FeeBps         *uint16         `json:"feeBps,omitempty"`
+	Owner          *common.Address `json:"owner,omitempty"`
+	Selectors      []SelectorCheck `json:"selectors"`
+	Problems       []string        `json:"problems,omitempty"`
+}
+
+// Compatible true kalau tidak ada masalah yang ditemukan
+func (r *CompatibilityReport) Compatible() bool {
+	return len(r.Problems) == 0
+}
+
+// String ringkasan report yang bisa dibaca
+func (r *CompatibilityReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "RedEnvelope at %s (chain %s): ", r.Address.Hex(), r.ChainID)
+	if r.Compatible() {
+		fmt.Fprintf(&b, "compatible, %d bytes of code, %d selectors found", r.CodeSize, len(r.Selectors))
+		return b.String()
+	}
+	fmt.Fprintf(&b, "INCOMPATIBLE")
+	for _, problem := range r.Problems {
+		fmt.Fprintf(&b, "\n  - %s", problem)
+	}
+	return b.String()
+}
+
+// IncompatibleContractError dikembalikan WithCompatibilityCheck kalau
+// contract tidak cocok
+type IncompatibleContractError struct {
+	Report *CompatibilityReport
+}
+
+func (e *IncompatibleContractError) Error() string {
+	return e.Report.String()
+}
+
+// WithCompatibilityCheck memastikan contract di ContractAddress cocok
+// sebelum service dipakai: ada code, nextEnvelopeId / feeBps / owner
+// merespons, dan semua selector ABI ada di dispatch table.
+func WithCompatibilityCheck() Option {
+	return func(s *RedEnvelopeService) error {
+		report, err := s.CheckCompatibility()
+		if err != nil {
+			return err
+		}
+		if !report.Compatible() {
+			return &IncompatibleContractError{Report: report}
+		}
+		return nil
+	}
+}
+
+// CheckCompatibility memeriksa contract di ContractAddress. Error hanya
+// dikembalikan kalau node tidak bisa dihubungi; ketidakcocokan dicatat di
+// report.Problems.
+func (s *RedEnvelopeService) CheckCompatibility() (*CompatibilityReport, error) {
+	report := &CompatibilityReport{
+		Address: s.ContractAddress,
+		ChainID: s.ChainID,
+	}
+
+	code, err := s.Client.CodeAt(context.Background(), s.ContractAddress, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get code: %v", err)
+	}
+	report.CodeSize = len(code)
+	if len(code) == 0 {
+		report.Problems = append(report.Problems, fmt.Sprintf("no contract code at %s, check contractAddress and the network", s.ContractAddress.Hex()))
+		return report, nil
+	}
+
+	if nextId, err := s.GetNextEnvelopeId(); err != nil {
+		report.Problems = append(report.Problems, fmt.Sprintf("nextEnvelopeId() did not respond: %v", err))
+	} else {
+		report.NextEnvelopeID = nextId
+	}
+	if feeBps, err := s.GetFeeBps(); err != nil {
+		report.Problems = append(report.Problems, fmt.Sprintf("feeBps() did not respond: %v", err))
+	} else {
+		report.FeeBps = &feeBps
+	}
+	if owner, err := s.GetOwner(); err != nil {
+		report.Problems = append(report.Problems, fmt.Sprintf("owner() did not respond: %v", err))
+	} else {
+		report.Owner = &owner
+	}
+
+	report.Selectors = checkSelectors(s.ABI.Methods, code)
+	var missing []string
+	for _, check := range report.Selectors {
+		if !check.Found {
+			missing = append(missing, fmt.Sprintf("%s (%s)", check.Method, check.Selector))
+		}
+	}
+	if len(missing) == len(report.Selectors) {
+		report.Problems = append(report.Problems, "no RedEnvelope selectors in bytecode (proxy contracts are not supported)")
+	} else if len(missing) > 0 {
+		report.Problems = append(report.Problems, fmt.Sprintf("selectors missing from dispatch table: %s", strings.Join(missing, ", ")))
+	}
+
+	return report, nil
+}
+
+// checkSelectors mencari selector setiap method sebagai operand PUSH4 di
+// bytecode, yaitu bentuk dispatch table yang dihasilkan solc
+func checkSelectors(methods map[string]abi.Method, code []byte) []SelectorCheck {
+	pushed := dispatchSelectors(code)
+
+	checks := make([]SelectorCheck, 0, len(methods))
+	for name, method := range methods {
+		var selector [4]byte
+		copy(selector[:], method.ID)
+		checks = append(checks, SelectorCheck{
+			Method:   name,
+			Selector: hexutil.Encode(method.ID),
+			Found:    pushed[selector],
+		})
+	}
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Method < checks[j].Method })
+	return checks
+}
+
+// Opcode PUSH EVM: PUSH0 tanpa operand, PUSHn (0x60-0x7f) dengan operand n
+// byte. Ditulis sendiri supaya package ini tidak menarik core/vm.
+const (
+	opPush0  = 0x5f
+	opPush1  = 0x60
+	opPush32 = 0x7f
+)
+
+// dispatchSelectors semua operand PUSH1..PUSH4 di bytecode, di-pad ke 4
+// byte (solc memakai PUSH yang lebih pendek untuk selector berawalan 0x00).
+// Operand PUSH lain dilewati supaya data yang mirip opcode tidak terbaca.
+func dispatchSelectors(code []byte) map[[4]byte]bool {
+	selectors := make(map[[4]byte]bool)
+	for pc := 0; pc < len(code); pc++ {
+		op := code[pc]
+		if op < opPush0 || op > opPush32 {
+			continue
+		}
+		size := int(op - opPush0)
+		if size >= 1 && size <= 4 && pc+size < len(code) {
+			var selector [4]byte
+			copy(selector[4-size:], code[pc+1:pc+1+size])
+			selectors[selector] = true
+		}
+		pc += size
+	}
+	return selectors
+}
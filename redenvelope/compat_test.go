@@ -0,0 +1,112 @@
+package redenvelope
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Opcode lain untuk bytecode test
+const (
+	opMstore = 0x52
+	opJumpi  = 0x57
+	opDup1   = 0x80
+	opEq     = 0x14
+)
+
+// testDispatchCode bytecode dengan dispatch table ala solc untuk methods
+func testDispatchCode(t *testing.T, skip string) []byte {
+	code := []byte{opPush1, 0x80, opPush1, 0x40, opMstore}
+	for name, method := range newABIOnlyService(t).ABI.Methods {
+		if name == skip {
+			continue
+		}
+		code = append(code, opDup1, opPush1+3)
+		code = append(code, method.ID...)
+		code = append(code, opEq, opPush1+1, 0x01, 0x00, opJumpi)
+	}
+	return code
+}
+
+// testCompatNode fake node yang menjawab nextEnvelopeId, feeBps dan owner
+func testCompatNode(t *testing.T, code []byte) *fakeEthAPI {
+	methods := newABIOnlyService(t).ABI.Methods
+	pack := func(name string, values ...interface{}) hexutil.Bytes {
+		output, err := methods[name].Outputs.Pack(values...)
+		if err != nil {
+			t.Fatalf("Failed to pack %s: %v", name, err)
+		}
+		return output
+	}
+
+	return &fakeEthAPI{
+		code: code,
+		outputs: map[string]hexutil.Bytes{
+			hexutil.Encode(methods["nextEnvelopeId"].ID): pack("nextEnvelopeId", big.NewInt(7)),
+			hexutil.Encode(methods["feeBps"].ID):         pack("feeBps", uint16(100)),
+			hexutil.Encode(methods["owner"].ID):          pack("owner", testAddress0),
+		},
+	}
+}
+
+func TestCheckCompatibility_Compatible(t *testing.T) {
+	service := newFakeNode(t, testCompatNode(t, testDispatchCode(t, "")))
+
+	report, err := service.CheckCompatibility()
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if !report.Compatible() {
+		t.Fatalf("Expected compatible, got %s", report)
+	}
+	if report.NextEnvelopeID.Int64() != 7 || *report.FeeBps != 100 || *report.Owner != testAddress0 {
+		t.Errorf("Unexpected report values: %+v", report)
+	}
+	if err := WithCompatibilityCheck()(service); err != nil {
+		t.Errorf("WithCompatibilityCheck should pass: %v", err)
+	}
+}
+
+func TestCheckCompatibility_MissingSelector(t *testing.T) {
+	service := newFakeNode(t, testCompatNode(t, testDispatchCode(t, "refundEnvelope")))
+
+	err := WithCompatibilityCheck()(service)
+	var incompatible *IncompatibleContractError
+	if !errors.As(err, &incompatible) {
+		t.Fatalf("Expected IncompatibleContractError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "refundEnvelope") {
+		t.Errorf("Report should name the missing selector: %s", err)
+	}
+}
+
+func TestCheckCompatibility_NoCode(t *testing.T) {
+	service := newFakeNode(t, &fakeEthAPI{})
+
+	report, err := service.CheckCompatibility()
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if report.Compatible() || !strings.Contains(report.String(), "no contract code") {
+		t.Errorf("Expected no-code problem, got %s", report)
+	}
+}
+
+func TestDispatchSelectors_SkipsPushData(t *testing.T) {
+	// Selector di dalam operand PUSH32 bukan dispatch entry
+	code := append([]byte{opPush32}, make([]byte, 32)...)
+	code[1] = opPush1 + 3
+	copy(code[2:], []byte{0xde, 0xad, 0xbe, 0xef})
+	code = append(code, opPush1+2, 0x12, 0x34, 0x56)
+
+	selectors := dispatchSelectors(code)
+	if selectors[[4]byte{0xde, 0xad, 0xbe, 0xef}] {
+		t.Error("Selector inside push data should be ignored")
+	}
+	if !selectors[[4]byte{0x00, 0x12, 0x34, 0x56}] {
+		t.Error("Short PUSH should be padded to a 4-byte selector")
+	}
+}
@@ -0,0 +1,183 @@
+package redenvelope
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultConfigPath lokasi config default, relatif ke working directory
+const DefaultConfigPath = "redenvelope.json"
+
+// Environment variable yang meng-override config network terpilih
+const (
+	EnvConfig          = "REDENVELOPE_CONFIG"           // Path config
+	EnvNetwork         = "REDENVELOPE_NETWORK"          // Nama network
+	EnvRPCURL          = "REDENVELOPE_RPC_URL"          // RPC URL, boleh dipisah koma
+	EnvChainID         = "REDENVELOPE_CHAIN_ID"         // Chain ID yang diharapkan
+	EnvContract        = "REDENVELOPE_CONTRACT"         // Address RedEnvelope
+	EnvDeploymentBlock = "REDENVELOPE_DEPLOYMENT_BLOCK" // Block deployment
+	EnvConfirmations   = "REDENVELOPE_CONFIRMATIONS"    // Confirmation depth
+	EnvMaxGasPrice     = "REDENVELOPE_MAX_GAS_PRICE"    // Batas gas price (wei)
+)
+
+// ErrUnknownNetwork network tidak ada di config
+var ErrUnknownNetwork = errors.New("unknown network")
+
+// GasPolicy aturan gas price untuk transaksi write
+type GasPolicy struct {
+	// PriceMultiplier pengali gas price dari eth_gasPrice, 0 berarti 1
+	PriceMultiplier float64 `json:"priceMultiplier,omitempty"`
+	// MaxGasPrice batas gas price dalam wei, transaksi ditolak kalau lebih
+	MaxGasPrice *big.Int `json:"maxGasPrice,omitempty"`
+}
+
+// NetworkConfig satu network bernama di config
+type NetworkConfig struct {
+	Name            string         `json:"-"`
+	RPCURLs         []string       `json:"rpcUrls"`
+	ChainID         uint64         `json:"chainId"`
+	ContractAddress common.Address `json:"contractAddress"`
+	DeploymentBlock uint64         `json:"deploymentBlock,omitempty"`
+	NativeSymbol    string         `json:"nativeSymbol,omitempty"`
+	Confirmations   uint64         `json:"confirmations,omitempty"`
+	Gas             GasPolicy      `json:"gas"`
+}
+
+// Symbol simbol native token, default ETH
+func (n *NetworkConfig) Symbol() string {
+	if n.NativeSymbol == "" {
+		return "ETH"
+	}
+	return n.NativeSymbol
+}
+
+// Config daftar network yang dipakai demo, CLI dan server
+type Config struct {
+	DefaultNetwork string                    `json:"defaultNetwork"`
+	Networks       map[string]*NetworkConfig `json:"networks"`
+}
+
+// LoadConfig membaca config JSON dari path
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %v", path, err)
+	}
+	for name, network := range config.Networks {
+		if network == nil {
+			return nil, fmt.Errorf("network %q in %s is empty", name, path)
+		}
+		network.Name = name
+	}
+	return &config, nil
+}
+
+// LoadConfigFromEnv membaca config dari REDENVELOPE_CONFIG atau DefaultConfigPath
+func LoadConfigFromEnv() (*Config, error) {
+	path := os.Getenv(EnvConfig)
+	if path == "" {
+		path = DefaultConfigPath
+	}
+	return LoadConfig(path)
+}
+
+// SaveConfig menulis config JSON ke path secara atomic
+func SaveConfig(path string, config *Config) error {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %v", err)
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// Network mengambil network bernama name (kosong: REDENVELOPE_NETWORK, lalu
+// DefaultNetwork) dengan override dari environment. Config tidak diubah.
+func (c *Config) Network(name string) (*NetworkConfig, error) {
+	if name == "" {
+		name = os.Getenv(EnvNetwork)
+	}
+	if name == "" {
+		name = c.DefaultNetwork
+	}
+
+	network, ok := c.Networks[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q (configured: %s)", ErrUnknownNetwork, name, strings.Join(c.names(), ", "))
+	}
+
+	resolved := *network
+	resolved.Name = name
+	resolved.RPCURLs = append([]string(nil), network.RPCURLs...)
+	if err := resolved.applyEnv(); err != nil {
+		return nil, err
+	}
+	if len(resolved.RPCURLs) == 0 {
+		return nil, fmt.Errorf("network %q has no rpcUrls", name)
+	}
+	if resolved.ChainID == 0 {
+		return nil, fmt.Errorf("network %q has no chainId", name)
+	}
+	return &resolved, nil
+}
+
+func (c *Config) names() []string {
+	names := make([]string, 0, len(c.Networks))
+	for name := range c.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyEnv override field dari environment variable
+func (n *NetworkConfig) applyEnv() error {
+	if value := os.Getenv(EnvRPCURL); value != "" {
+		n.RPCURLs = nil
+		for _, url := range strings.Split(value, ",") {
+			if url = strings.TrimSpace(url); url != "" {
+				n.RPCURLs = append(n.RPCURLs, url)
+			}
+		}
+	}
+	if value := os.Getenv(EnvContract); value != "" {
+		if !common.IsHexAddress(value) {
+			return fmt.Errorf("invalid %s %q", EnvContract, value)
+		}
+		n.ContractAddress = common.HexToAddress(value)
+	}
+	for env, field := range map[string]*uint64{
+		EnvChainID:         &n.ChainID,
+		EnvDeploymentBlock: &n.DeploymentBlock,
+		EnvConfirmations:   &n.Confirmations,
+	} {
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", env, value)
+		}
+		*field = parsed
+	}
+	if value := os.Getenv(EnvMaxGasPrice); value != "" {
+		maxGasPrice, ok := new(big.Int).SetString(value, 10)
+		if !ok || maxGasPrice.Sign() <= 0 {
+			return fmt.Errorf("invalid %s %q", EnvMaxGasPrice, value)
+		}
+		n.Gas.MaxGasPrice = maxGasPrice
+	}
+	return nil
+}
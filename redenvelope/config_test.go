@@ -0,0 +1,111 @@
+package redenvelope
+
+import (
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestConfig_RepoConfig(t *testing.T) {
+	config, err := LoadConfig(filepath.Join("..", DefaultConfigPath))
+	if err != nil {
+		t.Fatalf("Failed to load repo config: %v", err)
+	}
+	network, err := config.Network("")
+	if err != nil {
+		t.Fatalf("Failed to resolve default network: %v", err)
+	}
+	if network.Name != "localhost" || network.ChainID != 31337 || network.Symbol() != "ETH" {
+		t.Errorf("Unexpected default network: %+v", network)
+	}
+}
+
+func TestConfig_NetworkEnvOverrides(t *testing.T) {
+	config := &Config{
+		DefaultNetwork: "local",
+		Networks: map[string]*NetworkConfig{
+			"local": {RPCURLs: []string{"http://127.0.0.1:8545"}, ChainID: 31337},
+		},
+	}
+	t.Setenv(EnvRPCURL, "http://a:8545, http://b:8545")
+	t.Setenv(EnvContract, testContractAddress)
+	t.Setenv(EnvConfirmations, "2")
+	t.Setenv(EnvMaxGasPrice, "5000000000")
+
+	network, err := config.Network("")
+	if err != nil {
+		t.Fatalf("Failed to resolve network: %v", err)
+	}
+	if len(network.RPCURLs) != 2 || network.RPCURLs[1] != "http://b:8545" {
+		t.Errorf("Unexpected RPC URLs %v", network.RPCURLs)
+	}
+	if network.ContractAddress != common.HexToAddress(testContractAddress) || network.Confirmations != 2 {
+		t.Errorf("Env overrides not applied: %+v", network)
+	}
+	if network.Gas.MaxGasPrice.Int64() != 5000000000 {
+		t.Errorf("Unexpected max gas price %s", network.Gas.MaxGasPrice)
+	}
+	if config.Networks["local"].ContractAddress != (common.Address{}) {
+		t.Error("Network must not modify the loaded config")
+	}
+
+	if _, err := config.Network("mainnet"); !errors.Is(err, ErrUnknownNetwork) {
+		t.Errorf("Expected ErrUnknownNetwork, got %v", err)
+	}
+}
+
+func TestConfig_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redenvelope.json")
+	config := &Config{
+		DefaultNetwork: "local",
+		Networks: map[string]*NetworkConfig{
+			"local": {
+				RPCURLs:         []string{"http://127.0.0.1:8545"},
+				ChainID:         31337,
+				ContractAddress: common.HexToAddress(testContractAddress),
+				DeploymentBlock: 12,
+				Gas:             GasPolicy{MaxGasPrice: big.NewInt(1000)},
+			},
+		},
+	}
+	if err := SaveConfig(path, config); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	loaded, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	local := loaded.Networks["local"]
+	if local.Name != "local" || local.DeploymentBlock != 12 || local.Gas.MaxGasPrice.Int64() != 1000 {
+		t.Errorf("Unexpected network after round trip: %+v", local)
+	}
+}
+
+func TestGasPolicy_Apply(t *testing.T) {
+	policy := GasPolicy{PriceMultiplier: 1.5, MaxGasPrice: big.NewInt(2000)}
+
+	price, err := policy.apply(big.NewInt(1000))
+	if err != nil || price.Int64() != 1500 {
+		t.Fatalf("Expected 1500, got %v (%v)", price, err)
+	}
+	if _, err := policy.apply(big.NewInt(1500)); !errors.Is(err, ErrGasPriceTooHigh) {
+		t.Errorf("Expected ErrGasPriceTooHigh, got %v", err)
+	}
+}
+
+func TestWithExpectedChainID(t *testing.T) {
+	service := &RedEnvelopeService{ChainID: big.NewInt(1)}
+
+	err := WithExpectedChainID(31337)(service)
+	var mismatch *ChainIDMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Expected != 31337 {
+		t.Fatalf("Expected ChainIDMismatchError, got %v", err)
+	}
+	if err := WithExpectedChainID(1)(service); err != nil {
+		t.Errorf("Matching chain ID should pass: %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package redenvelope
+
+//go:generate go run gen_abi.go
+//go:generate abigen --v2 --abi RedEnvelope.abi --pkg bindings --type RedEnvelope --out bindings/redenvelope.go
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/v2"
+	"rpcsol/redenvelope/bindings"
+)
+
+// redEnvelope binding hasil abigen. Kalau contract berubah, salin artifact
+// baru ke artifacts/RedEnvelope.json lalu jalankan `go generate ./redenvelope`
+// untuk menulis ulang RedEnvelope.abi dan binding (butuh abigen v1.16.x:
+// go install github.com/ethereum/go-ethereum/cmd/abigen@v1.16.8).
+var redEnvelope = bindings.NewRedEnvelope()
+
+// instance BoundContract v2 untuk contract di s.ContractAddress
+func (s *RedEnvelopeService) instance() *bind.BoundContract {
+	return redEnvelope.Instance(s.Client, s.ContractAddress)
+}
+
+// callContract menjalankan eth_call dengan calldata dari binding dan decode
+// hasilnya dengan unpack yang typed. Output yang tidak sesuai ABI menjadi
+// error, bukan panic.
+func callContract[T any](s *RedEnvelopeService, data []byte, unpack func([]byte) (T, error)) (T, error) {
+	var zero T
+	result, err := bind.Call(s.instance(), &bind.CallOpts{}, data, unpack)
+	if err != nil {
+		return zero, s.DecodeRevert(err)
+	}
+	return result, nil
+}
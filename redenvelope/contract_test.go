@@ -0,0 +1,194 @@
+package redenvelope
+
+import (
+	"context"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"rpcsol/redenvelope/bindings"
+)
+
+// fakeEthAPI node palsu. eth_call mengembalikan outputs[selector] kalau
+// ada, selain itu output. eth_sendRawTransaction gagal dengan sendErr
+// kalau diisi, tx yang diterima disimpan di sent. Lookup tx dan receipt
+// mengembalikan tx/txErr dan receipt (nil -> ethereum.NotFound).
+type fakeEthAPI struct {
+	output  hexutil.Bytes
+	outputs map[string]hexutil.Bytes
+	code    hexutil.Bytes
+	balance *big.Int
+	sendErr error
+	tx      *types.Transaction
+	txErr   error
+	receipt *types.Receipt
+
+	mu   sync.Mutex
+	sent []*types.Transaction
+}
+
+func (f *fakeEthAPI) Call(ctx context.Context, args map[string]interface{}, block string) (hexutil.Bytes, error) {
+	input, _ := args["input"].(string)
+	if len(input) >= 10 {
+		if output, ok := f.outputs[input[:10]]; ok {
+			return output, nil
+		}
+	}
+	return f.output, nil
+}
+
+func (f *fakeEthAPI) GetCode(ctx context.Context, address common.Address, block string) (hexutil.Bytes, error) {
+	return f.code, nil
+}
+
+func (f *fakeEthAPI) GetTransactionCount(ctx context.Context, address common.Address, block string) (hexutil.Uint64, error) {
+	return 0, nil
+}
+
+func (f *fakeEthAPI) GasPrice() *hexutil.Big { return (*hexutil.Big)(big.NewInt(1)) }
+
+func (f *fakeEthAPI) GetBalance(ctx context.Context, address common.Address, block string) *hexutil.Big {
+	if f.balance == nil {
+		return (*hexutil.Big)(new(big.Int))
+	}
+	return (*hexutil.Big)(f.balance)
+}
+
+func (f *fakeEthAPI) SendRawTransaction(ctx context.Context, raw hexutil.Bytes) (common.Hash, error) {
+	if f.sendErr != nil {
+		return common.Hash{}, f.sendErr
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return common.Hash{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, tx)
+	return tx.Hash(), nil
+}
+
+func (f *fakeEthAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, error) {
+	return f.tx, f.txErr
+}
+
+func (f *fakeEthAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	return f.receipt, nil
+}
+
+// sentTxs tx yang sudah diterima node
+func (f *fakeEthAPI) sentTxs() []*types.Transaction {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.sent)
+}
+
+// newFakeSenderService service dengan KeySigner testPrivateKey di chain
+// 31337 yang terhubung ke api, untuk test yang mengirim tx
+func newFakeSenderService(t *testing.T, api *fakeEthAPI) *RedEnvelopeService {
+	signer, err := NewKeySigner(testPrivateKey)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+	service := newFakeNode(t, api)
+	service.Signer = signer
+	service.Address = signer.Address()
+	service.ChainID = big.NewInt(31337)
+	return service
+}
+
+// newFakeNodeService RedEnvelopeService yang terhubung ke fakeEthAPI in-process
+func newFakeNodeService(t *testing.T, output []byte) *RedEnvelopeService {
+	return newFakeNode(t, &fakeEthAPI{output: output, code: hexutil.Bytes{0x60, 0x80}})
+}
+
+func newFakeNode(t *testing.T, api *fakeEthAPI) *RedEnvelopeService {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", api); err != nil {
+		t.Fatalf("Failed to register fake eth API: %v", err)
+	}
+	client := ethclient.NewClient(rpc.DialInProc(server))
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+
+	service := newABIOnlyService(t)
+	service.Client = client
+	service.ContractAddress = common.HexToAddress(testContractAddress)
+	return service
+}
+
+func TestBindings_MatchContractABI(t *testing.T) {
+	generated, err := bindings.RedEnvelopeMetaData.ParseABI()
+	if err != nil {
+		t.Fatalf("Failed to parse generated ABI: %v", err)
+	}
+	embedded, err := abi.JSON(strings.NewReader(RedEnvelopeABI))
+	if err != nil {
+		t.Fatalf("Failed to parse RedEnvelopeABI: %v", err)
+	}
+
+	if len(generated.Methods) != len(embedded.Methods) || len(generated.Events) != len(embedded.Events) || len(generated.Errors) != len(embedded.Errors) {
+		t.Fatal("Generated bindings are out of date, run go generate ./redenvelope")
+	}
+	for name, method := range embedded.Methods {
+		if generated.Methods[name].Sig != method.Sig {
+			t.Errorf("Method %s: bindings have %q, ABI has %q", name, generated.Methods[name].Sig, method.Sig)
+		}
+	}
+	for name, event := range embedded.Events {
+		if generated.Events[name].ID != event.ID {
+			t.Errorf("Event %s differs between bindings and ABI", name)
+		}
+	}
+}
+
+func TestGetEnvelope_DecodeMismatchReturnsError(t *testing.T) {
+	service := newFakeNodeService(t, []byte{0x12, 0x34})
+
+	if _, err := service.GetEnvelope(big.NewInt(1)); err == nil {
+		t.Fatal("Expected error for malformed getEnvelope output")
+	}
+	if _, err := service.HasClaimed(big.NewInt(1), testAddress0); err == nil {
+		t.Fatal("Expected error for malformed hasUserClaimed output")
+	}
+}
+
+func TestGetEnvelope_TypedDecode(t *testing.T) {
+	creator := testAddress0
+	output, err := newABIOnlyService(t).ABI.Methods["getEnvelope"].Outputs.Pack(bindings.Envelope{
+		Creator:         creator,
+		Kind:            GROUP_RANDOM,
+		AmountPerClaim:  big.NewInt(0),
+		RemainingAmount: big.NewInt(900),
+		TotalClaims:     3,
+		RemainingClaims: 2,
+		ClaimIndex:      1,
+		Expiry:          1700000000,
+		RoomIdHash:      TestRoomIdHash,
+	})
+	if err != nil {
+		t.Fatalf("Failed to pack envelope: %v", err)
+	}
+
+	envelope, err := newFakeNodeService(t, output).GetEnvelope(big.NewInt(1))
+	if err != nil {
+		t.Fatalf("Failed to get envelope: %v", err)
+	}
+	if envelope.Creator != creator || envelope.Kind != GROUP_RANDOM || envelope.RemainingAmount.Int64() != 900 {
+		t.Errorf("Unexpected envelope: %+v", envelope)
+	}
+	if envelope.RemainingClaims != 2 || envelope.RoomIdHash != TestRoomIdHash {
+		t.Errorf("Unexpected envelope: %+v", envelope)
+	}
+}
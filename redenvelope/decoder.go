@@ -0,0 +1,315 @@
+package redenvelope
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Status transaksi di DecodedTx
+const (
+	TxStatusPending  = "pending"
+	TxStatusSuccess  = "success"
+	TxStatusReverted = "reverted"
+)
+
+// DecodedArg satu argumen method / field event yang sudah di-decode.
+// Value bertipe Go: kind menjadi nama kind, expiry menjadi time.Time,
+// roomIdHash menjadi common.Hash.
+type DecodedArg struct {
+	Name    string      `json:"name"`
+	Type    string      `json:"type"`
+	Value   interface{} `json:"value"`
+	Display string      `json:"display"`
+}
+
+// DecodedCall calldata RedEnvelope yang sudah di-decode
+type DecodedCall struct {
+	Method   string       `json:"method"`
+	Selector string       `json:"selector"`
+	Args     []DecodedArg `json:"args"`
+}
+
+// DecodedEvent log RedEnvelope yang sudah di-decode
+type DecodedEvent struct {
+	Name     string       `json:"name"`
+	LogIndex uint         `json:"logIndex"`
+	Fields   []DecodedArg `json:"fields"`
+}
+
+// DecodedTx transaksi RedEnvelope lengkap dengan receipt dan event
+type DecodedTx struct {
+	Hash        common.Hash     `json:"hash"`
+	From        common.Address  `json:"from"`
+	To          *common.Address `json:"to"`
+	Value       *big.Int        `json:"value"`
+	Nonce       uint64          `json:"nonce"`
+	Call        *DecodedCall    `json:"call,omitempty"`
+	Status      string          `json:"status"`
+	BlockNumber uint64          `json:"blockNumber,omitempty"`
+	GasUsed     uint64          `json:"gasUsed,omitempty"`
+	Events      []DecodedEvent  `json:"events,omitempty"`
+	Failure     string          `json:"failure,omitempty"` // Penjelasan kalau transaksi revert
+}
+
+// KindName nama envelope kind, "UNKNOWN" kalau tidak dikenal
+func KindName(kind uint8) string {
+	switch kind {
+	case DIRECT_FIXED:
+		return "DIRECT_FIXED"
+	case GROUP_FIXED:
+		return "GROUP_FIXED"
+	case GROUP_RANDOM:
+		return "GROUP_RANDOM"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseKind kebalikan KindName
+func ParseKind(name string) (uint8, error) {
+	for _, kind := range []uint8{DIRECT_FIXED, GROUP_FIXED, GROUP_RANDOM} {
+		if KindName(kind) == name {
+			return kind, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid envelope kind %q", name)
+}
+
+// DecodeCalldata decode input transaksi ke contract RedEnvelope
+func DecodeCalldata(input []byte) (*DecodedCall, error) {
+	parsed, err := contractABI()
+	if err != nil {
+		return nil, err
+	}
+	if len(input) < 4 {
+		return nil, fmt.Errorf("calldata too short")
+	}
+
+	method, err := parsed.MethodById(input[:4])
+	if err != nil {
+		return nil, fmt.Errorf("unknown method selector %s", hexutil.Encode(input[:4]))
+	}
+
+	values, err := method.Inputs.Unpack(input[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s arguments: %v", method.Name, err)
+	}
+
+	return &DecodedCall{
+		Method:   method.Name,
+		Selector: hexutil.Encode(method.ID),
+		Args:     decodeArgs(method.Inputs, values),
+	}, nil
+}
+
+// DecodeLog decode log event RedEnvelope
+func DecodeLog(log *types.Log) (*DecodedEvent, error) {
+	parsed, err := contractABI()
+	if err != nil {
+		return nil, err
+	}
+	if len(log.Topics) == 0 {
+		return nil, fmt.Errorf("log has no topics")
+	}
+
+	event, err := parsed.EventByID(log.Topics[0])
+	if err != nil {
+		return nil, fmt.Errorf("unknown event topic %s", log.Topics[0].Hex())
+	}
+
+	values, err := event.Inputs.NonIndexed().Unpack(log.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %v", event.Name, err)
+	}
+
+	return &DecodedEvent{
+		Name:     event.Name,
+		LogIndex: log.Index,
+		Fields:   decodeArgs(event.Inputs.NonIndexed(), values),
+	}, nil
+}
+
+// DecodeTransaction mengambil transaksi, receipt dan event berdasarkan
+// hash, lalu decode semuanya. Untuk transaksi revert, Failure berisi
+// penjelasan dari hasil replay eth_call.
+func (s *RedEnvelopeService) DecodeTransaction(hash common.Hash) (*DecodedTx, error) {
+	ctx := context.Background()
+
+	tx, isPending, err := s.Client.TransactionByHash(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %v", err)
+	}
+
+	from, err := types.Sender(types.LatestSignerForChainID(s.ChainID), tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover sender: %v", err)
+	}
+
+	decoded := &DecodedTx{
+		Hash:   hash,
+		From:   from,
+		To:     tx.To(),
+		Value:  tx.Value(),
+		Nonce:  tx.Nonce(),
+		Status: TxStatusPending,
+	}
+
+	if tx.To() != nil && *tx.To() == s.ContractAddress {
+		if call, err := DecodeCalldata(tx.Data()); err == nil {
+			decoded.Call = call
+		}
+	}
+
+	if isPending {
+		return decoded, nil
+	}
+
+	receipt, err := s.Client.TransactionReceipt(ctx, hash)
+	if err == ethereum.NotFound {
+		return decoded, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipt: %v", err)
+	}
+
+	decoded.BlockNumber = receipt.BlockNumber.Uint64()
+	decoded.GasUsed = receipt.GasUsed
+
+	for _, log := range receipt.Logs {
+		if log.Address != s.ContractAddress {
+			continue
+		}
+		if event, err := DecodeLog(log); err == nil {
+			decoded.Events = append(decoded.Events, *event)
+		}
+	}
+
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		decoded.Status = TxStatusSuccess
+		return decoded, nil
+	}
+
+	decoded.Status = TxStatusReverted
+	decoded.Failure = s.explainFailure(ctx, tx, from, receipt)
+	return decoded, nil
+}
+
+// explainFailure penjelasan yang bisa dibaca untuk transaksi yang revert
+func (s *RedEnvelopeService) explainFailure(ctx context.Context, tx *types.Transaction, from common.Address, receipt *types.Receipt) string {
+	return ExplainError(s.replayFailure(ctx, tx, from, receipt))
+}
+
+// replayFailure replay transaksi di state block sebelumnya untuk mendapat
+// revert reason. Hasilnya *ContractError kalau revert data dikenali.
+func (s *RedEnvelopeService) replayFailure(ctx context.Context, tx *types.Transaction, from common.Address, receipt *types.Receipt) error {
+	if receipt.GasUsed >= tx.Gas() {
+		return fmt.Errorf("out of gas: used all %d gas, raise the gas limit", tx.Gas())
+	}
+
+	msg := ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	parent := new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+	_, err := s.Client.CallContract(ctx, msg, parent)
+	if err == nil {
+		return errors.New("reverted on-chain, but replaying it against the previous block succeeds (state changed within the block)")
+	}
+	return s.DecodeRevert(err)
+}
+
+// ReceiptError nil kalau receipt sukses. Kalau revert, error berisi alasan
+// hasil replay dan bisa dicek dengan errors.Is(err, ErrAlreadyClaimed) dst.
+func (s *RedEnvelopeService) ReceiptError(tx *types.Transaction, receipt *types.Receipt) error {
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		return nil
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(s.ChainID), tx)
+	if err != nil {
+		from = s.Address
+	}
+	err = s.replayFailure(context.Background(), tx, from, receipt)
+	return fmt.Errorf("tx %s reverted: %w", tx.Hash().Hex(), err)
+}
+
+// ExplainError penjelasan yang bisa dibaca untuk error contract
+func ExplainError(err error) string {
+	var contractErr *ContractError
+	if !errors.As(err, &contractErr) {
+		return err.Error()
+	}
+
+	switch contractErr {
+	case ErrAlreadyClaimed:
+		return "AlreadyClaimed: this address has already claimed the envelope"
+	case ErrEnvelopeExpired:
+		return "EnvelopeExpired: the envelope is past its expiry and can no longer be claimed"
+	case ErrEnvelopeNotFound:
+		return "EnvelopeNotFound: no envelope exists with this ID"
+	case ErrInvalidParameters:
+		return "InvalidParameters: the arguments or msg.value were rejected (check kind, totalClaims, amount, expiry and value)"
+	case ErrNotEligible:
+		return "NotEligible: the caller is not allowed to claim (wrong recipient, no claims left, or refund before expiry)"
+	case ErrTransferFailed:
+		return "TransferFailed: the contract could not transfer the payout or refund"
+	case ErrUnauthorized:
+		return "Unauthorized: only the creator (or owner) may call this"
+	}
+	return contractErr.Error()
+}
+
+// decodeArgs ubah nilai ABI menjadi DecodedArg dengan nilai yang lebih bermakna
+func decodeArgs(arguments abi.Arguments, values []interface{}) []DecodedArg {
+	args := make([]DecodedArg, 0, len(values))
+	for i, value := range values {
+		arg := DecodedArg{
+			Name:    arguments[i].Name,
+			Type:    arguments[i].Type.String(),
+			Value:   value,
+			Display: formatArg(value),
+		}
+
+		switch arguments[i].Name {
+		case "kind":
+			if kind, ok := value.(uint8); ok {
+				arg.Value = KindName(kind)
+				arg.Display = fmt.Sprintf("%s (%d)", KindName(kind), kind)
+			}
+		case "expiry":
+			if expiry, ok := value.(uint64); ok {
+				at := time.Unix(int64(expiry), 0).UTC()
+				arg.Value = at
+				arg.Display = at.Format(time.RFC3339)
+			}
+		case "roomIdHash":
+			if hash, ok := value.([32]byte); ok {
+				arg.Value = common.Hash(hash)
+				if hash == EmptyRoomIdHash {
+					arg.Display = "none (no room restriction)"
+				}
+			}
+		case "token":
+			if token, ok := value.(common.Address); ok && token == (common.Address{}) {
+				arg.Display = "native"
+			}
+		case "recipient":
+			if recipient, ok := value.(common.Address); ok && recipient == (common.Address{}) {
+				arg.Display = "anyone"
+			}
+		}
+		args = append(args, arg)
+	}
+	return args
+}
@@ -0,0 +1,85 @@
+package redenvelope
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestDecodeCalldata_CreateEnvelope(t *testing.T) {
+	recipient := testAccount(t, 1).Address()
+	request, err := BuildCreateEnvelopeTx(common.HexToAddress(testContractAddress), DIRECT_FIXED, common.Address{}, 1, big.NewInt(42), time.Hour, TestRoomIdHash, recipient)
+	if err != nil {
+		t.Fatalf("Failed to build: %v", err)
+	}
+
+	call, err := DecodeCalldata(request.Data)
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if call.Method != "createEnvelope" || len(call.Args) != 7 {
+		t.Fatalf("Unexpected call: %+v", call)
+	}
+
+	args := make(map[string]DecodedArg)
+	for _, arg := range call.Args {
+		args[arg.Name] = arg
+	}
+	if args["kind"].Value != "DIRECT_FIXED" {
+		t.Errorf("Expected kind name, got %v", args["kind"].Value)
+	}
+	if _, ok := args["expiry"].Value.(time.Time); !ok {
+		t.Errorf("Expected expiry as time.Time, got %T", args["expiry"].Value)
+	}
+	if args["roomIdHash"].Value != common.Hash(TestRoomIdHash) {
+		t.Errorf("Unexpected roomIdHash %v", args["roomIdHash"].Value)
+	}
+	if args["recipient"].Value != recipient || args["token"].Display != "native" {
+		t.Errorf("Unexpected recipient/token: %+v %+v", args["recipient"], args["token"])
+	}
+}
+
+func TestDecodeCalldata_UnknownSelector(t *testing.T) {
+	if _, err := DecodeCalldata([]byte{0x12, 0x34, 0x56, 0x78}); err == nil {
+		t.Fatal("Expected error for unknown selector")
+	}
+}
+
+func TestDecodeLog_EnvelopeClaimed(t *testing.T) {
+	parsed, err := contractABI()
+	if err != nil {
+		t.Fatalf("Failed to parse ABI: %v", err)
+	}
+	event := parsed.Events["EnvelopeClaimed"]
+	claimer := testAccount(t, 1).Address()
+	data, err := event.Inputs.Pack(big.NewInt(3), claimer, big.NewInt(1000), uint32(2))
+	if err != nil {
+		t.Fatalf("Failed to pack event: %v", err)
+	}
+
+	decoded, err := DecodeLog(&types.Log{Topics: []common.Hash{event.ID}, Data: data, Index: 5})
+	if err != nil {
+		t.Fatalf("Failed to decode log: %v", err)
+	}
+	if decoded.Name != "EnvelopeClaimed" || decoded.LogIndex != 5 || len(decoded.Fields) != 4 {
+		t.Fatalf("Unexpected event: %+v", decoded)
+	}
+	if decoded.Fields[1].Value != claimer || decoded.Fields[2].Display != "1000" {
+		t.Errorf("Unexpected fields: %+v", decoded.Fields)
+	}
+}
+
+func TestExplainError(t *testing.T) {
+	explanation := ExplainError(ErrAlreadyClaimed)
+	if !strings.HasPrefix(explanation, "AlreadyClaimed") {
+		t.Errorf("Unexpected explanation: %s", explanation)
+	}
+	if ExplainError(errors.New("boom")) != "boom" {
+		t.Error("Non-contract errors should be returned as-is")
+	}
+}
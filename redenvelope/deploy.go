@@ -0,0 +1,149 @@
+package redenvelope
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrNoBytecode artifact tidak membawa creation bytecode
+var ErrNoBytecode = errors.New("artifact has no creation bytecode, load a compiled Hardhat/Foundry artifact")
+
+// MaxFeeBps batas feeBps, sama dengan BPS_DENOMINATOR di contract (100%)
+const MaxFeeBps = 10000
+
+// DeployRedEnvelope deploy RedEnvelope dengan artifact yang di-embed, lalu
+// mengembalikan service yang sudah terikat ke address baru beserta receipt
+// deployment. Option dijalankan setelah contract ter-deploy.
+func DeployRedEnvelope(rpcURL string, signer Signer, treasury common.Address, feeBps uint16, opts ...Option) (*RedEnvelopeService, *types.Receipt, error) {
+	artifact, err := EmbeddedArtifact()
+	if err != nil {
+		return nil, nil, err
+	}
+	return DeployRedEnvelopeFromArtifact(artifact, rpcURL, signer, treasury, feeBps, opts...)
+}
+
+// DeployRedEnvelopeFromArtifact sama dengan DeployRedEnvelope tapi memakai
+// bytecode dari artifact, mis. hasil LoadArtifact
+func DeployRedEnvelopeFromArtifact(artifact *Artifact, rpcURL string, signer Signer, treasury common.Address, feeBps uint16, opts ...Option) (*RedEnvelopeService, *types.Receipt, error) {
+	service, err := dialService(rpcURL, common.Address{}, signer)
+	if err != nil {
+		return nil, nil, err
+	}
+	return service.deployArtifact(artifact, treasury, feeBps, opts)
+}
+
+// DeployRedEnvelopeToNetwork deploy ke network dari config. ChainID node
+// dicek sebelum tx dikirim; ContractAddress dan DeploymentBlock network
+// diisi dari hasil deployment.
+func DeployRedEnvelopeToNetwork(artifact *Artifact, network *NetworkConfig, signer Signer, treasury common.Address, feeBps uint16, opts ...Option) (*RedEnvelopeService, *types.Receipt, error) {
+	if len(network.RPCURLs) == 0 {
+		return nil, nil, fmt.Errorf("network %q has no rpcUrls", network.Name)
+	}
+	service, err := dialService(network.RPCURLs[0], common.Address{}, signer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := service.apply([]Option{WithNetwork(network)}); err != nil {
+		return nil, nil, err
+	}
+
+	service, receipt, err := service.deployArtifact(artifact, treasury, feeBps, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	network.ContractAddress = receipt.ContractAddress
+	network.DeploymentBlock = receipt.BlockNumber.Uint64()
+	return service, receipt, nil
+}
+
+// deployArtifact deploy lewat service yang sudah terhubung, lalu mengikat
+// service ke address baru dan menjalankan opts
+func (s *RedEnvelopeService) deployArtifact(artifact *Artifact, treasury common.Address, feeBps uint16, opts []Option) (*RedEnvelopeService, *types.Receipt, error) {
+	input, err := deployInput(artifact, treasury, feeBps)
+	if err != nil {
+		s.Client.Close()
+		return nil, nil, err
+	}
+
+	receipt, err := s.deploy(input)
+	if err != nil {
+		s.Client.Close()
+		return nil, nil, fmt.Errorf("failed to deploy RedEnvelope: %w", err)
+	}
+
+	s.ContractAddress = receipt.ContractAddress
+	s.Artifact = artifact
+	if err := s.apply(opts); err != nil {
+		return nil, nil, err
+	}
+
+	return s, receipt, nil
+}
+
+// deployInput creation bytecode + argumen constructor yang sudah di-pack
+func deployInput(artifact *Artifact, treasury common.Address, feeBps uint16) ([]byte, error) {
+	if err := CheckArtifactABI(artifact); err != nil {
+		return nil, err
+	}
+	if len(artifact.Bytecode) == 0 {
+		return nil, ErrNoBytecode
+	}
+	if treasury == (common.Address{}) {
+		return nil, fmt.Errorf("treasury cannot be the zero address")
+	}
+	if feeBps > MaxFeeBps {
+		return nil, fmt.Errorf("feeBps %d exceeds %d", feeBps, MaxFeeBps)
+	}
+
+	args, err := artifact.ABI.Pack("", treasury, feeBps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack constructor: %v", err)
+	}
+
+	input := make([]byte, 0, len(artifact.Bytecode)+len(args))
+	input = append(input, artifact.Bytecode...)
+	return append(input, args...), nil
+}
+
+// deploy mengirim creation tx, menunggu receipt, lalu memastikan code ada
+// di address baru
+func (s *RedEnvelopeService) deploy(input []byte) (*types.Receipt, error) {
+	ctx := context.Background()
+
+	gasPrice, err := s.gasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	auth := s.transactOpts()
+	auth.GasPrice = gasPrice
+	address, tx, err := bind.DeployContract(auth, nil, s.Client, input)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := s.WaitMined(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("deployment tx %s reverted", tx.Hash().Hex())
+	}
+	if receipt.ContractAddress != address {
+		return nil, fmt.Errorf("receipt contract address %s does not match expected %s", receipt.ContractAddress.Hex(), address.Hex())
+	}
+
+	code, err := s.Client.CodeAt(ctx, address, receipt.BlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployed code: %v", err)
+	}
+	if len(code) == 0 {
+		return nil, fmt.Errorf("no code at %s after deployment", address.Hex())
+	}
+	return receipt, nil
+}
@@ -0,0 +1,66 @@
+package redenvelope
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestDeployInput(t *testing.T) {
+	embedded, err := EmbeddedArtifact()
+	if err != nil {
+		t.Fatalf("Failed to load embedded artifact: %v", err)
+	}
+	treasury := testAddress0
+
+	empty := *embedded
+	empty.Bytecode = nil
+	if _, err := deployInput(&empty, treasury, 100); !errors.Is(err, ErrNoBytecode) {
+		t.Errorf("Expected ErrNoBytecode, got %v", err)
+	}
+
+	artifact := *embedded
+	artifact.Bytecode = []byte{0x60, 0x80, 0x60, 0x40}
+
+	input, err := deployInput(&artifact, treasury, 250)
+	if err != nil {
+		t.Fatalf("Failed to build deploy input: %v", err)
+	}
+	if !bytes.HasPrefix(input, artifact.Bytecode) || len(input) != len(artifact.Bytecode)+64 {
+		t.Fatalf("Unexpected deploy input %x", input)
+	}
+	args, err := artifact.ABI.Constructor.Inputs.Unpack(input[len(artifact.Bytecode):])
+	if err != nil {
+		t.Fatalf("Failed to unpack constructor args: %v", err)
+	}
+	if args[0].(common.Address) != treasury || args[1].(uint16) != 250 {
+		t.Errorf("Unexpected constructor args %v", args)
+	}
+
+	if _, err := deployInput(&artifact, common.Address{}, 250); err == nil {
+		t.Error("Expected error for zero treasury")
+	}
+	if _, err := deployInput(&artifact, treasury, MaxFeeBps+1); err == nil {
+		t.Error("Expected error for feeBps above 100%")
+	}
+}
+
+func TestDeployInput_EmbeddedArtifact(t *testing.T) {
+	embedded, err := EmbeddedArtifact()
+	if err != nil {
+		t.Fatalf("Failed to load embedded artifact: %v", err)
+	}
+	if len(embedded.Bytecode) == 0 {
+		t.Skip("Embedded artifact is ABI-only - copy the compiled Hardhat artifact into artifacts/RedEnvelope.json")
+	}
+
+	input, err := deployInput(embedded, testAddress0, 100)
+	if err != nil {
+		t.Fatalf("Failed to build deploy input from embedded artifact: %v", err)
+	}
+	if !bytes.HasPrefix(input, embedded.Bytecode) {
+		t.Errorf("Deploy input does not start with the embedded bytecode")
+	}
+}
@@ -0,0 +1,107 @@
+package redenvelope
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ContractError revert yang sudah di-decode dari contract
+type ContractError struct {
+	Name   string // Nama custom error, "Error" untuk require(string), "Panic" untuk panic code
+	Reason string // Pesan revert / panic reason kalau ada
+}
+
+func (e *ContractError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("execution reverted: %s: %s", e.Name, e.Reason)
+	}
+	return fmt.Sprintf("execution reverted: %s", e.Name)
+}
+
+// Custom error dari contract RedEnvelope. Bisa dicek dengan errors.Is.
+var (
+	ErrAlreadyClaimed    = &ContractError{Name: "AlreadyClaimed"}
+	ErrEnvelopeExpired   = &ContractError{Name: "EnvelopeExpired"}
+	ErrEnvelopeNotFound  = &ContractError{Name: "EnvelopeNotFound"}
+	ErrInvalidParameters = &ContractError{Name: "InvalidParameters"}
+	ErrNotEligible       = &ContractError{Name: "NotEligible"}
+	ErrTransferFailed    = &ContractError{Name: "TransferFailed"}
+	ErrUnauthorized      = &ContractError{Name: "Unauthorized"}
+)
+
+var contractErrors = map[string]*ContractError{
+	ErrAlreadyClaimed.Name:    ErrAlreadyClaimed,
+	ErrEnvelopeExpired.Name:   ErrEnvelopeExpired,
+	ErrEnvelopeNotFound.Name:  ErrEnvelopeNotFound,
+	ErrInvalidParameters.Name: ErrInvalidParameters,
+	ErrNotEligible.Name:       ErrNotEligible,
+	ErrTransferFailed.Name:    ErrTransferFailed,
+	ErrUnauthorized.Name:      ErrUnauthorized,
+}
+
+// DecodeRevert mengubah error dari eth_call / estimateGas menjadi
+// *ContractError kalau error tersebut membawa revert data. Error lain
+// dikembalikan apa adanya.
+func (s *RedEnvelopeService) DecodeRevert(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return err
+	}
+
+	var data []byte
+	switch v := dataErr.ErrorData().(type) {
+	case string:
+		decoded, decodeErr := hexutil.Decode(v)
+		if decodeErr != nil {
+			return err
+		}
+		data = decoded
+	case []byte:
+		data = v
+	default:
+		return err
+	}
+
+	if contractErr := s.decodeRevertData(data); contractErr != nil {
+		return contractErr
+	}
+	return err
+}
+
+// decodeRevertData decode revert data mentah (selector + argumen)
+func (s *RedEnvelopeService) decodeRevertData(data []byte) *ContractError {
+	if len(data) < 4 {
+		return nil
+	}
+
+	for name, abiErr := range s.ABI.Errors {
+		if !bytes.Equal(abiErr.ID[:4], data[:4]) {
+			continue
+		}
+		if known, ok := contractErrors[name]; ok {
+			return known
+		}
+		return &ContractError{Name: name}
+	}
+
+	reason, unpackErr := abi.UnpackRevert(data)
+	if unpackErr != nil {
+		return nil
+	}
+	if bytes.Equal(data[:4], panicSelector) {
+		return &ContractError{Name: "Panic", Reason: reason}
+	}
+	return &ContractError{Name: "Error", Reason: reason}
+}
+
+// panicSelector selector Panic(uint256)
+var panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
@@ -0,0 +1,70 @@
+package redenvelope
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// revertError meniru error eth_call dari node yang membawa revert data
+type revertError struct {
+	data string
+}
+
+func (e *revertError) Error() string          { return "execution reverted" }
+func (e *revertError) ErrorCode() int         { return 3 }
+func (e *revertError) ErrorData() interface{} { return e.data }
+
+func newABIOnlyService(t *testing.T) *RedEnvelopeService {
+	parsedABI, err := abi.JSON(strings.NewReader(RedEnvelopeABI))
+	if err != nil {
+		t.Fatalf("Failed to parse ABI: %v", err)
+	}
+	return &RedEnvelopeService{ABI: parsedABI}
+}
+
+func TestDecodeRevert_CustomErrors(t *testing.T) {
+	service := newABIOnlyService(t)
+
+	for name, expected := range contractErrors {
+		selector := service.ABI.Errors[name].ID.Bytes()[:4]
+		err := service.DecodeRevert(fmt.Errorf("call failed: %w", &revertError{data: hexutil.Encode(selector)}))
+
+		if !errors.Is(err, expected) {
+			t.Errorf("Expected %s, got %v", name, err)
+		}
+	}
+}
+
+func TestDecodeRevert_ReasonString(t *testing.T) {
+	service := newABIOnlyService(t)
+
+	// Error(string) dengan pesan "boom"
+	data := "0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000004" +
+		"626f6f6d00000000000000000000000000000000000000000000000000000000"
+
+	err := service.DecodeRevert(&revertError{data: data})
+
+	var contractErr *ContractError
+	if !errors.As(err, &contractErr) {
+		t.Fatalf("Expected ContractError, got %v", err)
+	}
+	if contractErr.Name != "Error" || contractErr.Reason != "boom" {
+		t.Errorf("Unexpected decoded error: %+v", contractErr)
+	}
+}
+
+func TestDecodeRevert_PassesThroughOtherErrors(t *testing.T) {
+	service := newABIOnlyService(t)
+
+	original := errors.New("connection refused")
+	if err := service.DecodeRevert(original); err != original {
+		t.Errorf("Expected original error, got %v", err)
+	}
+}
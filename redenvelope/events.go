@@ -0,0 +1,101 @@
+package redenvelope
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// EnvelopeCreatedEvent event EnvelopeCreated dari contract
+type EnvelopeCreatedEvent struct {
+	EnvelopeId  *big.Int
+	Creator     common.Address
+	Kind        uint8
+	Token       common.Address
+	NetPot      *big.Int
+	TotalClaims uint32
+	Expiry      uint64
+	FeeAmount   *big.Int
+	RoomIdHash  [32]byte
+	Recipient   common.Address
+}
+
+// EnvelopeClaimedEvent event EnvelopeClaimed dari contract
+type EnvelopeClaimedEvent struct {
+	EnvelopeId *big.Int
+	Claimer    common.Address
+	Payout     *big.Int
+	ClaimIndex uint32
+}
+
+// EnvelopeRefundedEvent event EnvelopeRefunded dari contract
+type EnvelopeRefundedEvent struct {
+	EnvelopeId   *big.Int
+	RefundAmount *big.Int
+}
+
+// ParseEnvelopeCreated decode log EnvelopeCreated
+func (s *RedEnvelopeService) ParseEnvelopeCreated(log *types.Log) (*EnvelopeCreatedEvent, error) {
+	event, err := redEnvelope.UnpackEnvelopeCreatedEvent(log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack EnvelopeCreated: %v", err)
+	}
+	return &EnvelopeCreatedEvent{
+		EnvelopeId:  event.EnvelopeId,
+		Creator:     event.Creator,
+		Kind:        event.Kind,
+		Token:       event.Token,
+		NetPot:      event.NetPot,
+		TotalClaims: event.TotalClaims,
+		Expiry:      event.Expiry,
+		FeeAmount:   event.FeeAmount,
+		RoomIdHash:  event.RoomIdHash,
+		Recipient:   event.Recipient,
+	}, nil
+}
+
+// ParseEnvelopeClaimed decode log EnvelopeClaimed
+func (s *RedEnvelopeService) ParseEnvelopeClaimed(log *types.Log) (*EnvelopeClaimedEvent, error) {
+	event, err := redEnvelope.UnpackEnvelopeClaimedEvent(log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack EnvelopeClaimed: %v", err)
+	}
+	return &EnvelopeClaimedEvent{
+		EnvelopeId: event.EnvelopeId,
+		Claimer:    event.Claimer,
+		Payout:     event.Payout,
+		ClaimIndex: event.ClaimIndex,
+	}, nil
+}
+
+// ParseEnvelopeRefunded decode log EnvelopeRefunded
+func (s *RedEnvelopeService) ParseEnvelopeRefunded(log *types.Log) (*EnvelopeRefundedEvent, error) {
+	event, err := redEnvelope.UnpackEnvelopeRefundedEvent(log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack EnvelopeRefunded: %v", err)
+	}
+	return &EnvelopeRefundedEvent{
+		EnvelopeId:   event.EnvelopeId,
+		RefundAmount: event.RefundAmount,
+	}, nil
+}
+
+// EnvelopeIDFromReceipt mengambil envelope ID dari event EnvelopeCreated di receipt
+func (s *RedEnvelopeService) EnvelopeIDFromReceipt(receipt *types.Receipt) (*big.Int, error) {
+	for _, log := range receipt.Logs {
+		if log.Address != s.ContractAddress {
+			continue
+		}
+		if len(log.Topics) == 0 || log.Topics[0] != s.ABI.Events["EnvelopeCreated"].ID {
+			continue
+		}
+		event, err := s.ParseEnvelopeCreated(log)
+		if err != nil {
+			return nil, err
+		}
+		return event.EnvelopeId, nil
+	}
+	return nil, fmt.Errorf("no EnvelopeCreated event in receipt %s", receipt.TxHash.Hex())
+}
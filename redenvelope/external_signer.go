@@ -0,0 +1,184 @@
+package redenvelope
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// ErrSignerRejected dikembalikan kalau external signer (atau operatornya)
+// menolak request tanda tangan
+var ErrSignerRejected = errors.New("signing request rejected by external signer")
+
+// externalSignerTimeout batas waktu satu request ke external signer. Clef
+// menunggu approval manual, jadi dibuat cukup panjang.
+const externalSignerTimeout = 2 * time.Minute
+
+// ExternalSigner Signer yang meminta tanda tangan ke external signer
+// Clef-compatible lewat JSON-RPC (account_list, account_signTransaction,
+// account_signTypedData). Key tidak pernah masuk ke proses ini.
+type ExternalSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// signTransactionResult response account_signTransaction
+type signTransactionResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+// NewExternalSigner connect ke external signer di endpoint. Kalau address
+// zero, akun pertama dari account_list yang dipakai.
+func NewExternalSigner(endpoint string, address common.Address) (*ExternalSigner, error) {
+	client, err := rpc.DialOptions(context.Background(), endpoint, rpc.WithHTTPClient(&http.Client{Timeout: externalSignerTimeout}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to external signer: %v", err)
+	}
+
+	var accounts []common.Address
+	ctx, cancel := context.WithTimeout(context.Background(), externalSignerTimeout)
+	defer cancel()
+	if err := client.CallContext(ctx, &accounts, "account_list"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to list external signer accounts: %w", wrapSignerError(err))
+	}
+	if len(accounts) == 0 {
+		client.Close()
+		return nil, fmt.Errorf("external signer has no accounts")
+	}
+
+	if address == (common.Address{}) {
+		address = accounts[0]
+	} else {
+		found := false
+		for _, account := range accounts {
+			if account == address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			client.Close()
+			return nil, fmt.Errorf("account %s is not managed by external signer", address.Hex())
+		}
+	}
+
+	return &ExternalSigner{client: client, address: address}, nil
+}
+
+// Address akun yang dipakai untuk tanda tangan
+func (e *ExternalSigner) Address() common.Address {
+	return e.address
+}
+
+// SignTx meminta external signer menandatangani tx, lalu memastikan tx
+// yang dikembalikan sama dengan yang diminta
+func (e *ExternalSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := sendTxArgs(e.address, tx, chainID)
+
+	var result signTransactionResult
+	ctx, cancel := context.WithTimeout(context.Background(), externalSignerTimeout)
+	defer cancel()
+	if err := e.client.CallContext(ctx, &result, "account_signTransaction", args); err != nil {
+		return nil, wrapSignerError(err)
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(result.Raw); err != nil {
+		return nil, fmt.Errorf("failed to decode signed tx: %v", err)
+	}
+
+	if err := checkSignedTx(tx, signed, e.address, chainID); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// SignTypedData meminta external signer menandatangani data EIP-712
+func (e *ExternalSigner) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	var signature hexutil.Bytes
+	ctx, cancel := context.WithTimeout(context.Background(), externalSignerTimeout)
+	defer cancel()
+	err := e.client.CallContext(ctx, &signature, "account_signTypedData", common.NewMixedcaseAddress(e.address), typedData)
+	if err != nil {
+		return nil, wrapSignerError(err)
+	}
+	return signature, nil
+}
+
+// Close menutup koneksi ke external signer
+func (e *ExternalSigner) Close() {
+	e.client.Close()
+}
+
+// sendTxArgs mengubah tx unsigned menjadi argumen account_signTransaction
+func sendTxArgs(from common.Address, tx *types.Transaction, chainID *big.Int) apitypes.SendTxArgs {
+	data := hexutil.Bytes(tx.Data())
+	args := apitypes.SendTxArgs{
+		From:    common.NewMixedcaseAddress(from),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Input:   &data,
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.To() != nil {
+		to := common.NewMixedcaseAddress(*tx.To())
+		args.To = &to
+	}
+	if tx.Type() == types.LegacyTxType {
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	} else {
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	}
+	return args
+}
+
+// checkSignedTx memastikan signer tidak mengubah isi tx dan tanda tangannya
+// berasal dari akun yang benar
+func checkSignedTx(requested, signed *types.Transaction, from common.Address, chainID *big.Int) error {
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return fmt.Errorf("failed to recover signed tx sender: %v", err)
+	}
+	if sender != from {
+		return fmt.Errorf("signed tx sender %s does not match %s", sender.Hex(), from.Hex())
+	}
+
+	sameTo := (requested.To() == nil && signed.To() == nil) ||
+		(requested.To() != nil && signed.To() != nil && *requested.To() == *signed.To())
+	if !sameTo ||
+		requested.Type() != signed.Type() ||
+		requested.Nonce() != signed.Nonce() ||
+		requested.Gas() != signed.Gas() ||
+		requested.Value().Cmp(signed.Value()) != 0 ||
+		requested.GasFeeCap().Cmp(signed.GasFeeCap()) != 0 ||
+		requested.GasTipCap().Cmp(signed.GasTipCap()) != 0 ||
+		string(requested.Data()) != string(signed.Data()) {
+		return fmt.Errorf("signed tx does not match the requested tx")
+	}
+	return nil
+}
+
+// wrapSignerError mengubah penolakan dari signer menjadi ErrSignerRejected
+func wrapSignerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if strings.Contains(strings.ToLower(err.Error()), "request denied") {
+		return fmt.Errorf("%w: %v", ErrSignerRejected, err)
+	}
+	return fmt.Errorf("external signer error: %v", err)
+}
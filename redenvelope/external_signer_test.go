@@ -0,0 +1,146 @@
+package redenvelope
+
+import (
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func startStandInSigner(t *testing.T, approve ApproveFunc) *httptest.Server {
+	standIn, err := NewStandInSigner(testAccount(t, 0), approve)
+	if err != nil {
+		t.Fatalf("Failed to create stand-in signer: %v", err)
+	}
+	server := httptest.NewServer(standIn)
+	t.Cleanup(func() {
+		server.Close()
+		standIn.Close()
+	})
+	return server
+}
+
+func TestExternalSigner_SignTxAndTypedData(t *testing.T) {
+	server := startStandInSigner(t, nil)
+
+	signer, err := NewExternalSigner(server.URL, common.Address{})
+	if err != nil {
+		t.Fatalf("Failed to connect external signer: %v", err)
+	}
+	defer signer.Close()
+
+	if signer.Address() != testAddress0 {
+		t.Fatalf("Expected account %s, got %s", testAddress0.Hex(), signer.Address().Hex())
+	}
+
+	chainID := big.NewInt(31337)
+	contract := common.HexToAddress(testContractAddress)
+	tx := types.NewTransaction(3, contract, big.NewInt(5), 300000, big.NewInt(2000000000), []byte{0xde, 0xad})
+
+	signed, err := signer.SignTx(tx, chainID)
+	if err != nil {
+		t.Fatalf("Failed to sign tx: %v", err)
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil || sender != testAddress0 {
+		t.Errorf("Unexpected sender %s (%v)", sender.Hex(), err)
+	}
+	if signed.Nonce() != 3 || *signed.To() != contract {
+		t.Errorf("Signed tx differs from request: nonce=%d to=%s", signed.Nonce(), signed.To().Hex())
+	}
+
+	signature, err := signer.SignTypedData(testTypedData())
+	if err != nil {
+		t.Fatalf("Failed to sign typed data: %v", err)
+	}
+	if len(signature) != 65 {
+		t.Errorf("Expected 65 byte signature, got %d", len(signature))
+	}
+}
+
+func TestExternalSigner_Rejection(t *testing.T) {
+	server := startStandInSigner(t, func(method string, request interface{}) bool {
+		return method == "account_list"
+	})
+
+	signer, err := NewExternalSigner(server.URL, testAddress0)
+	if err != nil {
+		t.Fatalf("Failed to connect external signer: %v", err)
+	}
+	defer signer.Close()
+
+	tx := types.NewTransaction(0, common.HexToAddress(testContractAddress), big.NewInt(0), 21000, big.NewInt(1), nil)
+	_, err = signer.SignTx(tx, big.NewInt(31337))
+	if !errors.Is(err, ErrSignerRejected) {
+		t.Fatalf("Expected ErrSignerRejected, got %v", err)
+	}
+}
+
+func TestExternalSigner_UnknownAccount(t *testing.T) {
+	server := startStandInSigner(t, nil)
+
+	other := testAccount(t, 1).Address()
+	if _, err := NewExternalSigner(server.URL, other); err == nil {
+		t.Fatal("Expected error for account not managed by the signer")
+	}
+}
+
+func TestCheckSignedTx(t *testing.T) {
+	chainID := big.NewInt(31337)
+	signer := testAccount(t, 0)
+	to := common.HexToAddress(testContractAddress)
+	dynamic := func(edit func(*types.DynamicFeeTx)) types.TxData {
+		tx := &types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     3,
+			GasTipCap: big.NewInt(2),
+			GasFeeCap: big.NewInt(2),
+			Gas:       100000,
+			To:        &to,
+			Value:     big.NewInt(5),
+			Data:      []byte{0x01},
+		}
+		if edit != nil {
+			edit(tx)
+		}
+		return tx
+	}
+	requested := types.NewTx(dynamic(nil))
+
+	tests := []struct {
+		name   string
+		signed types.TxData
+		ok     bool
+	}{
+		{"same", dynamic(nil), true},
+		{"nonce", dynamic(func(tx *types.DynamicFeeTx) { tx.Nonce = 4 }), false},
+		{"gas", dynamic(func(tx *types.DynamicFeeTx) { tx.Gas = 200000 }), false},
+		{"value", dynamic(func(tx *types.DynamicFeeTx) { tx.Value = big.NewInt(6) }), false},
+		{"data", dynamic(func(tx *types.DynamicFeeTx) { tx.Data = []byte{0x02} }), false},
+		{"fee cap", dynamic(func(tx *types.DynamicFeeTx) { tx.GasFeeCap = big.NewInt(3) }), false},
+		{"tip cap", dynamic(func(tx *types.DynamicFeeTx) { tx.GasTipCap = big.NewInt(1) }), false},
+		// Legacy dengan gasPrice sama: fee cap dan tip cap sama, hanya type beda
+		{"type", &types.LegacyTx{Nonce: 3, GasPrice: big.NewInt(2), Gas: 100000, To: &to, Value: big.NewInt(5), Data: []byte{0x01}}, false},
+	}
+	for _, tc := range tests {
+		signed, err := signer.SignTx(types.NewTx(tc.signed), chainID)
+		if err != nil {
+			t.Fatalf("%s: failed to sign: %v", tc.name, err)
+		}
+		err = checkSignedTx(requested, signed, signer.Address(), chainID)
+		if tc.ok && err != nil {
+			t.Errorf("%s: expected match, got %v", tc.name, err)
+		}
+		if !tc.ok && err == nil {
+			t.Errorf("%s: expected mismatch to be rejected", tc.name)
+		}
+	}
+
+	signed, _ := signer.SignTx(requested, chainID)
+	if err := checkSignedTx(requested, signed, testAccount(t, 1).Address(), chainID); err == nil {
+		t.Error("Expected wrong sender to be rejected")
+	}
+}
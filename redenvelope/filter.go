@@ -0,0 +1,110 @@
+package redenvelope
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// EventFilter filter event untuk subscriber (stream, subscription,
+// webhook). Field nil / kosong tidak memfilter; semua yang diisi harus cocok.
+type EventFilter struct {
+	Events     []string        // Nama event (EventEnvelopeCreated, ...)
+	EnvelopeID *big.Int        //
+	RoomIdHash *common.Hash    //
+	Creator    *common.Address //
+	Address    *common.Address // Creator, recipient, atau claimer
+}
+
+// Match true kalau event cocok dengan filter. envelope adalah data envelope
+// dari index (boleh nil); dipakai untuk event Claimed / Refunded yang tidak
+// membawa creator dan roomIdHash.
+func (f *EventFilter) Match(event EnvelopeEvent, envelope *IndexedEnvelope) bool {
+	if len(f.Events) > 0 && !slices.Contains(f.Events, event.Name) {
+		return false
+	}
+	if f.EnvelopeID != nil && (event.EnvelopeID == nil || f.EnvelopeID.Cmp(event.EnvelopeID) != 0) {
+		return false
+	}
+
+	var creator, recipient, claimer *common.Address
+	var roomIdHash *common.Hash
+	if event.Created != nil {
+		room := common.Hash(event.Created.RoomIdHash)
+		creator, recipient, roomIdHash = &event.Created.Creator, &event.Created.Recipient, &room
+	} else if envelope != nil {
+		room := common.Hash(envelope.RoomIdHash)
+		creator, recipient, roomIdHash = &envelope.Creator, &envelope.Recipient, &room
+	}
+	if event.Claimed != nil {
+		claimer = &event.Claimed.Claimer
+	}
+
+	if f.RoomIdHash != nil && (roomIdHash == nil || *roomIdHash != *f.RoomIdHash) {
+		return false
+	}
+	if f.Creator != nil && (creator == nil || *creator != *f.Creator) {
+		return false
+	}
+	if f.Address != nil {
+		for _, address := range []*common.Address{creator, recipient, claimer} {
+			if address != nil && *address == *f.Address {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// eventNames nama event yang bisa difilter
+var eventNames = []string{EventEnvelopeCreated, EventEnvelopeClaimed, EventEnvelopeRefunded}
+
+// EventFilterJSON EventFilter dalam schema JSON (envelopeId sebagai string
+// desimal). Dipakai subscription JSON-RPC, webhook, dan query SSE.
+type EventFilterJSON struct {
+	Events     []string        `json:"events,omitempty"` // EnvelopeCreated, EnvelopeClaimed, EnvelopeRefunded
+	EnvelopeID string          `json:"envelopeId,omitempty"`
+	RoomID     string          `json:"roomId,omitempty"` // Di-hash dengan GenerateRoomIdHash
+	RoomIdHash *common.Hash    `json:"roomIdHash,omitempty"`
+	Creator    *common.Address `json:"creator,omitempty"`
+	Address    *common.Address `json:"address,omitempty"` // Creator, recipient, atau claimer
+}
+
+// EventFilter validasi dan konversi ke EventFilter. Receiver nil berarti
+// tanpa filter.
+func (j *EventFilterJSON) EventFilter() (*EventFilter, error) {
+	filter := &EventFilter{}
+	if j == nil {
+		return filter, nil
+	}
+
+	for _, name := range j.Events {
+		if !slices.Contains(eventNames, name) {
+			return nil, fmt.Errorf("unknown event %q", name)
+		}
+	}
+	filter.Events = j.Events
+
+	if j.EnvelopeID != "" {
+		id, ok := new(big.Int).SetString(j.EnvelopeID, 10)
+		if !ok || id.Sign() < 0 {
+			return nil, fmt.Errorf("invalid envelope ID %q", j.EnvelopeID)
+		}
+		filter.EnvelopeID = id
+	}
+	switch {
+	case j.RoomIdHash != nil && j.RoomID != "":
+		return nil, fmt.Errorf("roomId and roomIdHash are mutually exclusive")
+	case j.RoomIdHash != nil:
+		filter.RoomIdHash = j.RoomIdHash
+	case j.RoomID != "":
+		hash := common.Hash(GenerateRoomIdHash(j.RoomID))
+		filter.RoomIdHash = &hash
+	}
+	filter.Creator = j.Creator
+	filter.Address = j.Address
+	return filter, nil
+}
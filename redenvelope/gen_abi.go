@@ -0,0 +1,38 @@
+//go:build ignore
+
+// gen_abi menulis RedEnvelope.abi dari artifacts/RedEnvelope.json supaya
+// abigen dan artifact memakai ABI yang sama. Dijalankan lewat
+// `go generate ./redenvelope`.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+)
+
+func main() {
+	data, err := os.ReadFile("artifacts/RedEnvelope.json")
+	if err != nil {
+		log.Fatalf("failed to read artifact: %v", err)
+	}
+	var artifact struct {
+		ABI json.RawMessage `json:"abi"`
+	}
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		log.Fatalf("failed to parse artifact: %v", err)
+	}
+	if len(artifact.ABI) == 0 {
+		log.Fatal("artifact has no abi")
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, artifact.ABI, "", "  "); err != nil {
+		log.Fatalf("failed to format abi: %v", err)
+	}
+	out.WriteByte('\n')
+	if err := os.WriteFile("RedEnvelope.abi", out.Bytes(), 0o644); err != nil {
+		log.Fatalf("failed to write RedEnvelope.abi: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package redenvelope
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// HardhatMnemonic mnemonic default Hardhat / Anvil untuk akun test
+const HardhatMnemonic = "test test test test test test test test test test test junk"
+
+// hardenedOffset index BIP-32 mulai dari sini adalah hardened (')
+const hardenedOffset = 0x80000000
+
+// DerivationPath path BIP-44 Ethereum untuk akun ke-index: m/44'/60'/0'/0/index
+func DerivationPath(index uint32) string {
+	return fmt.Sprintf("m/44'/60'/0'/0/%d", index)
+}
+
+// DeriveKey menurunkan private key dari mnemonic BIP-39 dan path BIP-32.
+// Hanya mnemonic wordlist English yang didukung; kata dan checksum
+// divalidasi sebelum seed diturunkan.
+func DeriveKey(mnemonic string, passphrase string, path string) (*ecdsa.PrivateKey, error) {
+	words := strings.Fields(mnemonic)
+	switch len(words) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return nil, fmt.Errorf("invalid mnemonic: expected 12-24 words, got %d", len(words))
+	}
+	if err := checkMnemonic(words); err != nil {
+		return nil, err
+	}
+	normalized := strings.Join(words, " ")
+	for _, r := range normalized + passphrase {
+		if r > 0x7f {
+			return nil, fmt.Errorf("invalid mnemonic: only ASCII mnemonics and passphrases are supported")
+		}
+	}
+
+	derivationPath, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid derivation path: %v", err)
+	}
+
+	// BIP-39: seed = PBKDF2-HMAC-SHA512(mnemonic, "mnemonic"+passphrase, 2048)
+	seed, err := pbkdf2.Key(sha512.New, normalized, []byte("mnemonic"+passphrase), 2048, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive seed: %v", err)
+	}
+
+	// BIP-32 master key
+	master := hmacSHA512([]byte("Bitcoin seed"), seed)
+	key, chainCode := new(big.Int).SetBytes(master[:32]), master[32:]
+	curveN := crypto.S256().Params().N
+	if key.Sign() == 0 || key.Cmp(curveN) >= 0 {
+		return nil, fmt.Errorf("invalid master key")
+	}
+
+	for _, index := range derivationPath {
+		key, chainCode, err = deriveChild(key, chainCode, index)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return crypto.ToECDSA(ser256(key))
+}
+
+// NewMnemonicSigner membuat Signer untuk akun ke-index dari mnemonic
+func NewMnemonicSigner(mnemonic string, passphrase string, index uint32) (*KeySigner, error) {
+	privateKey, err := DeriveKey(mnemonic, passphrase, DerivationPath(index))
+	if err != nil {
+		return nil, err
+	}
+	return NewKeySignerFromECDSA(privateKey), nil
+}
+
+// HardhatAccount Signer untuk akun default Hardhat ke-index ("Account #index")
+func HardhatAccount(index uint32) (*KeySigner, error) {
+	return NewMnemonicSigner(HardhatMnemonic, "", index)
+}
+
+// deriveChild child key derivation BIP-32 (private parent -> private child)
+func deriveChild(key *big.Int, chainCode []byte, index uint32) (*big.Int, []byte, error) {
+	var data []byte
+	if index >= hardenedOffset {
+		// Hardened: 0x00 || ser256(k) || ser32(i)
+		data = append([]byte{0x00}, ser256(key)...)
+	} else {
+		// Normal: serP(point(k)) || ser32(i)
+		privateKey, err := crypto.ToECDSA(ser256(key))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid parent key: %v", err)
+		}
+		data = crypto.CompressPubkey(&privateKey.PublicKey)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	sum := hmacSHA512(chainCode, data)
+	curveN := crypto.S256().Params().N
+
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(curveN) >= 0 {
+		return nil, nil, fmt.Errorf("invalid child key at index %d", index)
+	}
+	child := new(big.Int).Add(tweak, key)
+	child.Mod(child, curveN)
+	if child.Sign() == 0 {
+		return nil, nil, fmt.Errorf("invalid child key at index %d", index)
+	}
+	return child, sum[32:], nil
+}
+
+func hmacSHA512(key, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// ser256 serialisasi scalar ke 32 byte big-endian
+func ser256(n *big.Int) []byte {
+	return n.FillBytes(make([]byte, 32))
+}
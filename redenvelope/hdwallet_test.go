@@ -0,0 +1,64 @@
+package redenvelope
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestHardhatAccount_MatchesHardhatDefaults(t *testing.T) {
+	expected := map[uint32]string{
+		0: "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+		1: "0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		2: "0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC",
+	}
+
+	for index, address := range expected {
+		signer, err := HardhatAccount(index)
+		if err != nil {
+			t.Fatalf("Failed to derive account #%d: %v", index, err)
+		}
+		if signer.Address().Hex() != address {
+			t.Errorf("Account #%d: expected %s, got %s", index, address, signer.Address().Hex())
+		}
+	}
+
+	signer, err := HardhatAccount(0)
+	if err != nil {
+		t.Fatalf("Failed to derive account #0: %v", err)
+	}
+	keyHex := hex.EncodeToString(crypto.FromECDSA(signer.key))
+	if keyHex != "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80" {
+		t.Errorf("Unexpected private key for account #0: %s", keyHex)
+	}
+}
+
+func TestDeriveKey_RejectsInvalidInput(t *testing.T) {
+	if _, err := DeriveKey("test test junk", "", DerivationPath(0)); err == nil {
+		t.Error("Expected error for short mnemonic")
+	}
+	if _, err := DeriveKey(HardhatMnemonic, "", "m/44'/60'/x"); err == nil {
+		t.Error("Expected error for invalid derivation path")
+	}
+	if _, err := DeriveKey("test test test test test test test test test test test junkx", "", DerivationPath(0)); err == nil {
+		t.Error("Expected error for word outside the BIP-39 wordlist")
+	}
+	if _, err := DeriveKey("test test test test test test test test test test test test", "", DerivationPath(0)); err == nil {
+		t.Error("Expected error for mnemonic with bad checksum")
+	}
+}
+
+func TestDeriveKey_PassphraseChangesAccount(t *testing.T) {
+	plain, err := NewMnemonicSigner(HardhatMnemonic, "", 0)
+	if err != nil {
+		t.Fatalf("Failed to derive: %v", err)
+	}
+	withPassphrase, err := NewMnemonicSigner(HardhatMnemonic, "extra", 0)
+	if err != nil {
+		t.Fatalf("Failed to derive with passphrase: %v", err)
+	}
+	if plain.Address() == withPassphrase.Address() {
+		t.Error("BIP-39 passphrase should derive a different account")
+	}
+}
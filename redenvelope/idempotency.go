@@ -0,0 +1,338 @@
+package redenvelope
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrIdempotencyConflict dikembalikan kalau idempotency key dipakai ulang
+// dengan parameter yang berbeda
+var ErrIdempotencyConflict = errors.New("idempotency key already used with different parameters")
+
+// IdempotencyRecord hasil CreateEnvelope yang tersimpan untuk satu key
+type IdempotencyRecord struct {
+	Key        string        `json:"key"`
+	ParamsHash common.Hash   `json:"paramsHash"`
+	TxHash     common.Hash   `json:"txHash"`
+	RawTx      hexutil.Bytes `json:"rawTx,omitempty"` // Untuk broadcast ulang kalau node belum kenal tx-nya
+	EnvelopeID *big.Int      `json:"envelopeId,omitempty"`
+	CreatedAt  time.Time     `json:"createdAt"`
+}
+
+// IdempotencyStore menyimpan idempotency key ke file JSON
+type IdempotencyStore struct {
+	path    string
+	mu      sync.Mutex
+	records map[string]*IdempotencyRecord
+
+	// createMu men-serialisasi CreateEnvelopeIdempotent supaya dua request
+	// dengan key yang sama tidak lolos bersamaan
+	createMu sync.Mutex
+}
+
+// OpenIdempotencyStore membuka (atau membuat) store di path yang diberikan
+func OpenIdempotencyStore(path string) (*IdempotencyStore, error) {
+	store := &IdempotencyStore{
+		path:    path,
+		records: make(map[string]*IdempotencyRecord),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return nil, fmt.Errorf("failed to read idempotency store: %v", err)
+	}
+	if len(data) == 0 {
+		return store, nil
+	}
+
+	var records []*IdempotencyRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse idempotency store: %v", err)
+	}
+	for _, record := range records {
+		store.records[record.Key] = record
+	}
+	return store, nil
+}
+
+// Get mengembalikan salinan record untuk key
+func (st *IdempotencyStore) Get(key string) (IdempotencyRecord, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	record, ok := st.records[key]
+	if !ok {
+		return IdempotencyRecord{}, false
+	}
+	return *record, true
+}
+
+// Put menyimpan record baru untuk key
+func (st *IdempotencyStore) Put(record IdempotencyRecord) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.records[record.Key] = &record
+	return st.save()
+}
+
+// Delete menghapus record untuk key
+func (st *IdempotencyStore) Delete(key string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	delete(st.records, key)
+	return st.save()
+}
+
+// SetEnvelopeID menyimpan envelope ID setelah tx create terkonfirmasi
+func (st *IdempotencyStore) SetEnvelopeID(key string, envelopeId *big.Int) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	record, ok := st.records[key]
+	if !ok {
+		return fmt.Errorf("idempotency key %q not found", key)
+	}
+	record.EnvelopeID = new(big.Int).Set(envelopeId)
+	return st.save()
+}
+
+func (st *IdempotencyStore) save() error {
+	records := make([]*IdempotencyRecord, 0, len(st.records))
+	for _, record := range st.records {
+		records = append(records, record)
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode idempotency store: %v", err)
+	}
+	return writeFileAtomic(st.path, data)
+}
+
+// WithIdempotencyStore mengaktifkan CreateEnvelopeIdempotent
+func WithIdempotencyStore(store *IdempotencyStore) Option {
+	return func(s *RedEnvelopeService) error {
+		s.Idempotency = store
+		return nil
+	}
+}
+
+// CreateResult hasil CreateEnvelopeIdempotent
+type CreateResult struct {
+	TxHash      common.Hash
+	EnvelopeID  *big.Int           // nil sampai tx terkonfirmasi
+	Transaction *types.Transaction // nil kalau tx lama sudah tidak bisa diambil dari node
+	Replayed    bool               // true kalau hasil berasal dari request sebelumnya
+}
+
+// CreateEnvelopeIdempotent sama seperti CreateEnvelope, tapi request dengan
+// key yang sama dan parameter identik mengembalikan hasil pertama tanpa
+// membuat envelope baru. Key yang sama dengan parameter berbeda ditolak
+// dengan ErrIdempotencyConflict.
+//
+// expiryDuration ikut dihitung ke parameter (bukan expiry absolut), jadi
+// retry dengan durasi yang sama tetap dianggap identik.
+func (s *RedEnvelopeService) CreateEnvelopeIdempotent(
+	key string,
+	kind uint8,
+	token common.Address,
+	totalClaims uint32,
+	amount *big.Int,
+	expiryDuration time.Duration,
+	roomIdHash [32]byte,
+	recipient common.Address,
+) (*CreateResult, error) {
+	if s.Idempotency == nil {
+		return nil, fmt.Errorf("idempotency store is not configured")
+	}
+	if key == "" {
+		return nil, fmt.Errorf("idempotency key cannot be empty")
+	}
+	if err := checkAmount(amount); err != nil {
+		return nil, err
+	}
+
+	paramsHash := createParamsHash(kind, token, totalClaims, amount, expiryDuration, roomIdHash, recipient)
+
+	s.Idempotency.createMu.Lock()
+	defer s.Idempotency.createMu.Unlock()
+
+	if record, ok := s.Idempotency.Get(key); ok {
+		if record.ParamsHash != paramsHash {
+			return nil, ErrIdempotencyConflict
+		}
+		return s.replayIdempotent(record)
+	}
+
+	expiry := uint64(time.Now().Add(expiryDuration).Unix())
+	call := createEnvelopeCall(kind, token, totalClaims, amount, expiry, roomIdHash, recipient)
+	// Key disimpan sebelum broadcast: kalau proses mati setelah ini, retry
+	// tetap mengembalikan tx yang sama (dan broadcast ulang raw tx-nya)
+	stored := false
+	call.onSigned = func(tx *types.Transaction) error {
+		raw, err := tx.MarshalBinary()
+		if err != nil {
+			return fmt.Errorf("failed to encode tx: %v", err)
+		}
+		err = s.Idempotency.Put(IdempotencyRecord{
+			Key:        key,
+			ParamsHash: paramsHash,
+			TxHash:     tx.Hash(),
+			RawTx:      raw,
+			CreatedAt:  time.Now().UTC(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to store idempotency key: %v", err)
+		}
+		stored = true
+		return nil
+	}
+
+	tx, err := s.sendTransaction(call)
+	if err != nil {
+		// Tx ditolak node -> key dilepas supaya retry membuat tx baru. Error
+		// transport tetap menyimpan key; retry mem-broadcast ulang raw tx.
+		if stored && rejectedByNode(err) {
+			return nil, s.releaseIdempotent(key, fmt.Errorf("failed to create envelope: %w", err))
+		}
+		return nil, fmt.Errorf("failed to create envelope: %w", err)
+	}
+
+	return &CreateResult{
+		TxHash:      tx.Hash(),
+		Transaction: tx,
+	}, nil
+}
+
+// ResolveIdempotencyKey mengembalikan hasil untuk key dan mengisi envelope ID
+// kalau tx-nya sudah terkonfirmasi
+func (s *RedEnvelopeService) ResolveIdempotencyKey(key string) (*CreateResult, error) {
+	if s.Idempotency == nil {
+		return nil, fmt.Errorf("idempotency store is not configured")
+	}
+	record, ok := s.Idempotency.Get(key)
+	if !ok {
+		return nil, fmt.Errorf("idempotency key %q not found", key)
+	}
+	return s.replayIdempotent(record)
+}
+
+func (s *RedEnvelopeService) replayIdempotent(record IdempotencyRecord) (*CreateResult, error) {
+	result := &CreateResult{
+		TxHash:     record.TxHash,
+		EnvelopeID: record.EnvelopeID,
+		Replayed:   true,
+	}
+
+	tx, _, err := s.Client.TransactionByHash(context.Background(), record.TxHash)
+	switch {
+	case err == nil:
+		result.Transaction = tx
+	case !errors.Is(err, ethereum.NotFound):
+		return nil, fmt.Errorf("failed to get create envelope tx %s: %v", record.TxHash.Hex(), err)
+	case result.EnvelopeID != nil:
+		// Tx lama sudah tidak disimpan node, envelope-nya sudah tercatat
+	case len(record.RawTx) > 0:
+		tx, err := s.rebroadcastIdempotent(record)
+		if err != nil {
+			return nil, err
+		}
+		result.Transaction = tx
+		return result, nil
+	default:
+		// Tx di-drop node dan tidak bisa dikirim ulang
+		return nil, s.releaseIdempotent(record.Key, fmt.Errorf("create envelope tx %s was dropped", record.TxHash.Hex()))
+	}
+
+	if result.EnvelopeID != nil {
+		return result, nil
+	}
+
+	receipt, err := s.Client.TransactionReceipt(context.Background(), record.TxHash)
+	if err == ethereum.NotFound {
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipt: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, s.releaseIdempotent(record.Key, fmt.Errorf("create envelope tx %s reverted", record.TxHash.Hex()))
+	}
+
+	envelopeId, err := s.EnvelopeIDFromReceipt(receipt)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Idempotency.SetEnvelopeID(record.Key, envelopeId); err != nil {
+		return nil, err
+	}
+	result.EnvelopeID = envelopeId
+	return result, nil
+}
+
+// rebroadcastIdempotent mengirim ulang raw tx yang node belum kenal, misalnya
+// karena broadcast pertama gagal di transport. Kalau node menolaknya, key
+// dilepas supaya retry berikutnya membuat tx baru.
+func (s *RedEnvelopeService) rebroadcastIdempotent(record IdempotencyRecord) (*types.Transaction, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(record.RawTx); err != nil {
+		return nil, fmt.Errorf("failed to decode stored tx: %v", err)
+	}
+
+	err := s.Client.SendTransaction(context.Background(), tx)
+	s.Metrics.submitted("createEnvelope", err)
+	s.logSent(intentAttrs("createEnvelope", map[string]string{"idempotencyKey": record.Key}), tx, err)
+	if err == nil {
+		return tx, nil
+	}
+	if rejectedByNode(err) {
+		return nil, s.releaseIdempotent(record.Key, fmt.Errorf("failed to rebroadcast tx: %w", err))
+	}
+	return nil, fmt.Errorf("failed to rebroadcast tx: %w", err)
+}
+
+// releaseIdempotent melepas key yang tx-nya tidak akan pernah membuat
+// envelope (ditolak, revert, atau di-drop), supaya retry dengan key yang
+// sama membuat tx baru. Mengembalikan cause, ditambah error Delete kalau ada.
+func (s *RedEnvelopeService) releaseIdempotent(key string, cause error) error {
+	if err := s.Idempotency.Delete(key); err != nil {
+		return errors.Join(cause, err)
+	}
+	return cause
+}
+
+// createParamsHash fingerprint parameter CreateEnvelope untuk deteksi konflik
+func createParamsHash(
+	kind uint8,
+	token common.Address,
+	totalClaims uint32,
+	amount *big.Int,
+	expiryDuration time.Duration,
+	roomIdHash [32]byte,
+	recipient common.Address,
+) common.Hash {
+	amountStr := "<nil>"
+	if amount != nil {
+		amountStr = amount.String()
+	}
+	fingerprint := fmt.Sprintf("%d|%s|%d|%s|%d|%x|%s",
+		kind, token.Hex(), totalClaims, amountStr, int64(expiryDuration), roomIdHash, recipient.Hex())
+	return crypto.Keccak256Hash([]byte(fingerprint))
+}
@@ -0,0 +1,177 @@
+package redenvelope
+
+import (
+	"errors"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestIdempotencyStore_PersistsRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idempotency.json")
+
+	store, err := OpenIdempotencyStore(path)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+
+	txHash := common.HexToHash("0x1234")
+	paramsHash := createParamsHash(GROUP_FIXED, common.Address{}, 5, big.NewInt(100), time.Hour, EmptyRoomIdHash, common.Address{})
+	if err := store.Put(IdempotencyRecord{Key: "req-1", ParamsHash: paramsHash, TxHash: txHash}); err != nil {
+		t.Fatalf("Failed to put record: %v", err)
+	}
+	if err := store.SetEnvelopeID("req-1", big.NewInt(9)); err != nil {
+		t.Fatalf("Failed to set envelope ID: %v", err)
+	}
+
+	reopened, err := OpenIdempotencyStore(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	record, ok := reopened.Get("req-1")
+	if !ok {
+		t.Fatal("Record not found after reopen")
+	}
+	if record.TxHash != txHash || record.ParamsHash != paramsHash {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+	if record.EnvelopeID == nil || record.EnvelopeID.Int64() != 9 {
+		t.Errorf("Expected envelope ID 9, got %v", record.EnvelopeID)
+	}
+}
+
+func TestCreateParamsHash_DetectsDifferentParameters(t *testing.T) {
+	base := createParamsHash(GROUP_FIXED, common.Address{}, 5, big.NewInt(100), time.Hour, EmptyRoomIdHash, common.Address{})
+
+	same := createParamsHash(GROUP_FIXED, common.Address{}, 5, big.NewInt(100), time.Hour, EmptyRoomIdHash, common.Address{})
+	if base != same {
+		t.Error("Identical parameters should produce the same hash")
+	}
+
+	variants := map[string]common.Hash{
+		"kind":        createParamsHash(GROUP_RANDOM, common.Address{}, 5, big.NewInt(100), time.Hour, EmptyRoomIdHash, common.Address{}),
+		"totalClaims": createParamsHash(GROUP_FIXED, common.Address{}, 6, big.NewInt(100), time.Hour, EmptyRoomIdHash, common.Address{}),
+		"amount":      createParamsHash(GROUP_FIXED, common.Address{}, 5, big.NewInt(101), time.Hour, EmptyRoomIdHash, common.Address{}),
+		"expiry":      createParamsHash(GROUP_FIXED, common.Address{}, 5, big.NewInt(100), 2*time.Hour, EmptyRoomIdHash, common.Address{}),
+		"roomIdHash":  createParamsHash(GROUP_FIXED, common.Address{}, 5, big.NewInt(100), time.Hour, TestRoomIdHash, common.Address{}),
+	}
+	for name, hash := range variants {
+		if hash == base {
+			t.Errorf("Changing %s should change the params hash", name)
+		}
+	}
+}
+
+func TestCreateEnvelopeIdempotent_FailedBroadcastReleasesKey(t *testing.T) {
+	api := &fakeEthAPI{balance: big.NewInt(1e18), sendErr: errors.New("insufficient funds for gas * price + value")}
+	service := newFakeSenderService(t, api)
+	store, err := OpenIdempotencyStore(filepath.Join(t.TempDir(), "idempotency.json"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	service.Idempotency = store
+
+	create := func() (*CreateResult, error) {
+		return service.CreateEnvelopeIdempotent("req-1", GROUP_FIXED, common.Address{}, 2, big.NewInt(100), time.Hour, EmptyRoomIdHash, common.Address{})
+	}
+	if _, err := create(); err == nil || !strings.Contains(err.Error(), api.sendErr.Error()) {
+		t.Fatalf("Expected broadcast error, got %v", err)
+	}
+	if _, ok := store.Get("req-1"); ok {
+		t.Fatal("Rejected tx should not keep the idempotency key")
+	}
+
+	api.sendErr = nil
+	result, err := create()
+	if err != nil {
+		t.Fatalf("Retry after rejected broadcast failed: %v", err)
+	}
+	if result.Replayed || len(api.sentTxs()) != 1 {
+		t.Errorf("Expected a fresh tx on retry, got %+v (sent %d)", result, len(api.sentTxs()))
+	}
+}
+
+func TestCreateEnvelopeIdempotent_ReplayRebroadcastsUnknownTx(t *testing.T) {
+	api := &fakeEthAPI{}
+	service := newFakeSenderService(t, api)
+	store, err := OpenIdempotencyStore(filepath.Join(t.TempDir(), "idempotency.json"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	service.Idempotency = store
+
+	// Seperti broadcast yang gagal di transport: key tersimpan, node tidak
+	// kenal tx-nya
+	tx, err := service.Signer.SignTx(types.NewTx(&types.LegacyTx{Nonce: 0, Gas: 21000, GasPrice: big.NewInt(1)}), service.ChainID)
+	if err != nil {
+		t.Fatalf("Failed to sign tx: %v", err)
+	}
+	raw, _ := tx.MarshalBinary()
+	if err := store.Put(IdempotencyRecord{Key: "req-1", TxHash: tx.Hash(), RawTx: raw}); err != nil {
+		t.Fatalf("Failed to put record: %v", err)
+	}
+
+	result, err := service.ResolveIdempotencyKey("req-1")
+	if err != nil {
+		t.Fatalf("Failed to resolve key: %v", err)
+	}
+	sent := api.sentTxs()
+	if len(sent) != 1 || sent[0].Hash() != tx.Hash() || result.TxHash != tx.Hash() {
+		t.Errorf("Expected stored tx to be rebroadcast, sent %d, result %+v", len(sent), result)
+	}
+}
+
+func TestCreateEnvelopeIdempotent_ReplayReleasesFailedTx(t *testing.T) {
+	api := &fakeEthAPI{}
+	service := newFakeSenderService(t, api)
+	store, err := OpenIdempotencyStore(filepath.Join(t.TempDir(), "idempotency.json"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	service.Idempotency = store
+
+	tx, err := service.Signer.SignTx(types.NewTx(&types.LegacyTx{Nonce: 0, Gas: 21000, GasPrice: big.NewInt(1)}), service.ChainID)
+	if err != nil {
+		t.Fatalf("Failed to sign tx: %v", err)
+	}
+	put := func() {
+		if err := store.Put(IdempotencyRecord{Key: "req-1", TxHash: tx.Hash()}); err != nil {
+			t.Fatalf("Failed to put record: %v", err)
+		}
+	}
+
+	// Error node selain NotFound tidak dianggap tx belum terkirim
+	put()
+	api.txErr = errors.New("upstream unavailable")
+	if _, err := service.ResolveIdempotencyKey("req-1"); err == nil || !strings.Contains(err.Error(), "upstream unavailable") {
+		t.Errorf("Expected lookup error, got %v", err)
+	}
+	if _, ok := store.Get("req-1"); !ok {
+		t.Error("Lookup error should keep the idempotency key")
+	}
+
+	// Tx di-drop node tanpa raw tx untuk dikirim ulang
+	api.txErr = nil
+	if _, err := service.ResolveIdempotencyKey("req-1"); err == nil || !strings.Contains(err.Error(), "dropped") {
+		t.Errorf("Expected dropped tx error, got %v", err)
+	}
+	if _, ok := store.Get("req-1"); ok {
+		t.Error("Dropped tx should release the idempotency key")
+	}
+
+	// Tx revert
+	put()
+	api.tx = tx
+	api.receipt = &types.Receipt{Status: types.ReceiptStatusFailed, TxHash: tx.Hash(), Logs: []*types.Log{}}
+	if _, err := service.ResolveIdempotencyKey("req-1"); err == nil || !strings.Contains(err.Error(), "reverted") {
+		t.Errorf("Expected reverted tx error, got %v", err)
+	}
+	if _, ok := store.Get("req-1"); ok {
+		t.Error("Reverted tx should release the idempotency key")
+	}
+}
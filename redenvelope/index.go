@@ -0,0 +1,285 @@
+package redenvelope
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Status envelope di index
+const (
+	EnvelopeStatusActive   = "active"
+	EnvelopeStatusEmpty    = "empty" // Semua klaim sudah diambil
+	EnvelopeStatusExpired  = "expired"
+	EnvelopeStatusRefunded = "refunded"
+)
+
+// IndexedEnvelope envelope yang dibangun dari event, tanpa eth_call
+type IndexedEnvelope struct {
+	ID            *big.Int
+	Creator       common.Address
+	Kind          uint8
+	Token         common.Address
+	NetPot        *big.Int
+	FeeAmount     *big.Int
+	TotalClaims   uint32
+	ClaimCount    uint32
+	ClaimedAmount *big.Int
+	Expiry        uint64
+	RoomIdHash    [32]byte
+	Recipient     common.Address
+	Refunded      bool
+	RefundAmount  *big.Int
+	CreatedBlock  uint64
+	CreatedTx     common.Hash
+}
+
+// Status status envelope pada waktu now
+func (e *IndexedEnvelope) Status(now time.Time) string {
+	switch {
+	case e.Refunded:
+		return EnvelopeStatusRefunded
+	case e.ClaimCount >= e.TotalClaims:
+		return EnvelopeStatusEmpty
+	case uint64(now.Unix()) >= e.Expiry:
+		return EnvelopeStatusExpired
+	default:
+		return EnvelopeStatusActive
+	}
+}
+
+// IndexFilter filter untuk Indexer.List. Field nil / kosong tidak memfilter.
+type IndexFilter struct {
+	Creator    *common.Address
+	Token      *common.Address
+	RoomIdHash *common.Hash
+	Status     string // Salah satu EnvelopeStatus*
+	Offset     int
+	Limit      int // 0 berarti DefaultIndexLimit
+}
+
+// DefaultIndexLimit jumlah envelope per halaman kalau Limit tidak diisi
+const DefaultIndexLimit = 50
+
+// Indexer index envelope di memori dari event EnvelopeCreated, Claimed dan
+// Refunded. Dipakai untuk list envelope tanpa query per ID ke node.
+type Indexer struct {
+	service *RedEnvelopeService
+
+	mu        sync.RWMutex
+	envelopes map[string]*IndexedEnvelope
+	order     []*IndexedEnvelope // Urutan dibuat
+	seen      map[logPosition]bool
+	lastBlock uint64
+	hasSynced bool
+
+	feed event.Feed // EnvelopeEvent baru, lihat SubscribeEvents
+}
+
+// NewIndexer membuat Indexer kosong untuk service
+func NewIndexer(service *RedEnvelopeService) *Indexer {
+	return &Indexer{
+		service:   service,
+		envelopes: make(map[string]*IndexedEnvelope),
+		seen:      make(map[logPosition]bool),
+	}
+}
+
+// logPosition identitas satu log untuk membuang event duplikat
+type logPosition struct {
+	tx    common.Hash
+	index uint
+}
+
+// Run mengisi index mulai opts.FromBlock (default block deploy network)
+// lalu terus mengikuti event baru sampai ctx selesai
+func (idx *Indexer) Run(ctx context.Context, opts WatchOptions) error {
+	if opts.FromBlock == 0 && idx.service.Network != nil {
+		opts.FromBlock = idx.service.Network.DeploymentBlock
+	}
+	opts.OnSynced = idx.synced
+	return idx.service.WatchEvents(ctx, opts, func(event EnvelopeEvent) error {
+		idx.Apply(event)
+		return nil
+	})
+}
+
+// synced mencatat block terakhir yang sudah dibaca, walau tanpa event
+func (idx *Indexer) synced(block uint64) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.lastBlock = max(idx.lastBlock, block)
+	idx.hasSynced = true
+	idx.service.Metrics.observeIndexed(idx.lastBlock)
+}
+
+// Apply memasukkan satu event ke index. Dipakai Run; event dari sumber lain
+// (misalnya receipt) juga bisa dimasukkan langsung, duplikat diabaikan.
+func (idx *Indexer) Apply(event EnvelopeEvent) {
+	if idx.apply(event) {
+		idx.feed.Send(event)
+	}
+}
+
+// apply memperbarui index, false kalau event sudah pernah masuk
+func (idx *Indexer) apply(event EnvelopeEvent) bool {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	position := logPosition{tx: event.TxHash, index: event.LogIndex}
+	if idx.seen[position] {
+		return false
+	}
+	idx.seen[position] = true
+	idx.lastBlock = max(idx.lastBlock, event.BlockNumber)
+
+	key := event.EnvelopeID.String()
+	switch {
+	case event.Created != nil:
+		if _, exists := idx.envelopes[key]; exists {
+			return true
+		}
+		created := event.Created
+		envelope := &IndexedEnvelope{
+			ID:            created.EnvelopeId,
+			Creator:       created.Creator,
+			Kind:          created.Kind,
+			Token:         created.Token,
+			NetPot:        created.NetPot,
+			FeeAmount:     created.FeeAmount,
+			TotalClaims:   created.TotalClaims,
+			ClaimedAmount: new(big.Int),
+			Expiry:        created.Expiry,
+			RoomIdHash:    created.RoomIdHash,
+			Recipient:     created.Recipient,
+			CreatedBlock:  event.BlockNumber,
+			CreatedTx:     event.TxHash,
+		}
+		idx.envelopes[key] = envelope
+		idx.order = append(idx.order, envelope)
+	case event.Claimed != nil:
+		if envelope, ok := idx.envelopes[key]; ok {
+			envelope.ClaimCount++
+			envelope.ClaimedAmount = new(big.Int).Add(envelope.ClaimedAmount, event.Claimed.Payout)
+		}
+	case event.Refunded != nil:
+		if envelope, ok := idx.envelopes[key]; ok {
+			envelope.Refunded = true
+			envelope.RefundAmount = event.Refunded.RefundAmount
+		}
+	}
+	return true
+}
+
+// SubscribeEvents mengirim setiap event baru yang masuk index ke ch, setelah
+// index diperbarui. Channel harus dibaca terus (atau di-buffer), karena
+// pengiriman menunggu semua subscriber.
+func (idx *Indexer) SubscribeEvents(ch chan<- EnvelopeEvent) event.Subscription {
+	return idx.feed.Subscribe(ch)
+}
+
+// SubscribeEventsBuffered seperti SubscribeEvents, tapi tidak pernah menahan
+// index: event ditampung di buffer sebesar size, dan channel ditutup kalau
+// buffer penuh. Penutupan channel berarti subscriber tertinggal dan ada event
+// yang hilang; subscribe ulang dan baca yang terlewat dengan Replay.
+func (idx *Indexer) SubscribeEventsBuffered(size int) (<-chan EnvelopeEvent, event.Subscription) {
+	in := make(chan EnvelopeEvent)
+	out := make(chan EnvelopeEvent, size)
+	sub := idx.feed.Subscribe(in)
+	go func() {
+		defer close(out)
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case next := <-in:
+				select {
+				case out <- next:
+				default:
+					return
+				}
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return out, sub
+}
+
+// Replay memanggil handle untuk setiap event di block from sampai block
+// terakhir index, berurutan. Log dibaca per window maxLogRange sehingga
+// event tidak dikumpulkan di memory. Tidak melakukan apa-apa kalau from
+// melewati block terakhir.
+func (idx *Indexer) Replay(ctx context.Context, from uint64, handle func(EnvelopeEvent) error) error {
+	to, _ := idx.LastBlock()
+	if from > to {
+		return nil
+	}
+	return idx.service.ForEachEvent(ctx, from, to, handle)
+}
+
+// Match EventFilter.Match dengan data envelope dari index
+func (idx *Indexer) Match(filter *EventFilter, event EnvelopeEvent) bool {
+	if envelope, ok := idx.Get(event.EnvelopeID); ok {
+		return filter.Match(event, &envelope)
+	}
+	return filter.Match(event, nil)
+}
+
+// Get envelope dari index
+func (idx *Indexer) Get(id *big.Int) (IndexedEnvelope, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	envelope, ok := idx.envelopes[id.String()]
+	if !ok {
+		return IndexedEnvelope{}, false
+	}
+	return *envelope, true
+}
+
+// List envelope yang cocok dengan filter, terbaru dulu, beserta jumlah
+// total yang cocok sebelum Offset / Limit
+func (idx *Indexer) List(filter IndexFilter) ([]IndexedEnvelope, int) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	now := time.Now()
+	var matched []IndexedEnvelope
+	for i := len(idx.order) - 1; i >= 0; i-- {
+		envelope := idx.order[i]
+		if filter.Creator != nil && envelope.Creator != *filter.Creator {
+			continue
+		}
+		if filter.Token != nil && envelope.Token != *filter.Token {
+			continue
+		}
+		if filter.RoomIdHash != nil && common.Hash(envelope.RoomIdHash) != *filter.RoomIdHash {
+			continue
+		}
+		if filter.Status != "" && envelope.Status(now) != filter.Status {
+			continue
+		}
+		matched = append(matched, *envelope)
+	}
+
+	total := len(matched)
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultIndexLimit
+	}
+	start := min(max(filter.Offset, 0), total)
+	end := min(start+limit, total)
+	return matched[start:end], total
+}
+
+// LastBlock block terakhir yang sudah dibaca index, false kalau polling
+// pertama belum selesai
+func (idx *Indexer) LastBlock() (uint64, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.lastBlock, idx.hasSynced
+}
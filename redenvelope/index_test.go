@@ -0,0 +1,168 @@
+package redenvelope
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func testCreatedEvent(id int64, creator common.Address, totalClaims uint32, expiry time.Time, block uint64) EnvelopeEvent {
+	return EnvelopeEvent{
+		Name:        EventEnvelopeCreated,
+		EnvelopeID:  big.NewInt(id),
+		BlockNumber: block,
+		TxHash:      common.BigToHash(big.NewInt(id)),
+		Created: &EnvelopeCreatedEvent{
+			EnvelopeId:  big.NewInt(id),
+			Creator:     creator,
+			Kind:        GROUP_FIXED,
+			NetPot:      big.NewInt(900),
+			TotalClaims: totalClaims,
+			Expiry:      uint64(expiry.Unix()),
+			FeeAmount:   big.NewInt(100),
+			RoomIdHash:  GenerateRoomIdHash("room-" + big.NewInt(id).String()),
+		},
+	}
+}
+
+func TestIndexer_ApplyAndList(t *testing.T) {
+	idx := NewIndexer(&RedEnvelopeService{})
+	alice, bob := testAddress0, common.HexToAddress("0x00000000000000000000000000000000000000b0")
+	future := time.Now().Add(time.Hour)
+
+	idx.Apply(testCreatedEvent(1, alice, 2, future, 10))
+	idx.Apply(testCreatedEvent(2, bob, 1, future, 11))
+	idx.Apply(testCreatedEvent(3, alice, 3, time.Now().Add(-time.Hour), 12))
+
+	claim := EnvelopeEvent{
+		Name:        EventEnvelopeClaimed,
+		EnvelopeID:  big.NewInt(2),
+		BlockNumber: 13,
+		TxHash:      common.HexToHash("0xc1"),
+		Claimed:     &EnvelopeClaimedEvent{EnvelopeId: big.NewInt(2), Claimer: alice, Payout: big.NewInt(900), ClaimIndex: 1},
+	}
+	idx.Apply(claim)
+	idx.Apply(claim) // Duplikat diabaikan
+	idx.Apply(EnvelopeEvent{
+		Name:        EventEnvelopeRefunded,
+		EnvelopeID:  big.NewInt(3),
+		BlockNumber: 14,
+		TxHash:      common.HexToHash("0xd1"),
+		Refunded:    &EnvelopeRefundedEvent{EnvelopeId: big.NewInt(3), RefundAmount: big.NewInt(900)},
+	})
+
+	envelope, ok := idx.Get(big.NewInt(2))
+	if !ok || envelope.ClaimCount != 1 || envelope.ClaimedAmount.Int64() != 900 || envelope.Status(time.Now()) != EnvelopeStatusEmpty {
+		t.Fatalf("Unexpected envelope 2: %+v", envelope)
+	}
+
+	all, total := idx.List(IndexFilter{})
+	if total != 3 || all[0].ID.Int64() != 3 || all[2].ID.Int64() != 1 {
+		t.Fatalf("Expected newest first, got %d envelopes", total)
+	}
+
+	byAlice, total := idx.List(IndexFilter{Creator: &alice})
+	if total != 2 || byAlice[0].Status(time.Now()) != EnvelopeStatusRefunded {
+		t.Errorf("Unexpected creator filter result %+v", byAlice)
+	}
+
+	room := common.Hash(GenerateRoomIdHash("room-1"))
+	byRoom, total := idx.List(IndexFilter{RoomIdHash: &room})
+	if total != 1 || byRoom[0].ID.Int64() != 1 {
+		t.Errorf("Unexpected room filter result %+v", byRoom)
+	}
+
+	active, total := idx.List(IndexFilter{Status: EnvelopeStatusActive})
+	if total != 1 || active[0].ID.Int64() != 1 {
+		t.Errorf("Unexpected status filter result %+v", active)
+	}
+
+	page, total := idx.List(IndexFilter{Offset: 1, Limit: 1})
+	if total != 3 || len(page) != 1 || page[0].ID.Int64() != 2 {
+		t.Errorf("Unexpected page %+v", page)
+	}
+
+	if block, _ := idx.LastBlock(); block != 14 {
+		t.Errorf("Expected last block 14, got %d", block)
+	}
+}
+
+func TestIndexer_SubscribeAndMatch(t *testing.T) {
+	idx := NewIndexer(&RedEnvelopeService{})
+	creator := testAddress0
+	claimer := common.HexToAddress("0x00000000000000000000000000000000000000c2")
+
+	ch := make(chan EnvelopeEvent, 4)
+	sub := idx.SubscribeEvents(ch)
+	defer sub.Unsubscribe()
+
+	created := testCreatedEvent(7, creator, 2, time.Now().Add(time.Hour), 20)
+	claimed := EnvelopeEvent{
+		Name:        EventEnvelopeClaimed,
+		EnvelopeID:  big.NewInt(7),
+		BlockNumber: 21,
+		TxHash:      common.HexToHash("0xc7"),
+		Claimed:     &EnvelopeClaimedEvent{EnvelopeId: big.NewInt(7), Claimer: claimer, Payout: big.NewInt(1), ClaimIndex: 1},
+	}
+	idx.Apply(created)
+	idx.Apply(claimed)
+	idx.Apply(claimed)
+
+	if len(ch) != 2 {
+		t.Fatalf("Expected 2 events on the feed, got %d", len(ch))
+	}
+	<-ch
+	got := <-ch
+
+	room := common.Hash(GenerateRoomIdHash("room-7"))
+	otherRoom := common.Hash(GenerateRoomIdHash("room-8"))
+	tests := []struct {
+		name   string
+		filter EventFilter
+		want   bool
+	}{
+		{"empty", EventFilter{}, true},
+		{"event name", EventFilter{Events: []string{EventEnvelopeRefunded}}, false},
+		{"envelope ID", EventFilter{EnvelopeID: big.NewInt(7)}, true},
+		{"other envelope", EventFilter{EnvelopeID: big.NewInt(8)}, false},
+		{"room from index", EventFilter{RoomIdHash: &room}, true},
+		{"other room", EventFilter{RoomIdHash: &otherRoom}, false},
+		{"creator from index", EventFilter{Creator: &creator}, true},
+		{"claimer address", EventFilter{Address: &claimer}, true},
+		{"claimer is not creator", EventFilter{Creator: &claimer}, false},
+	}
+	for _, tt := range tests {
+		if match := idx.Match(&tt.filter, got); match != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, match, tt.want)
+		}
+	}
+}
+
+func TestIndexer_SubscribeEventsBufferedDropsSlowSubscriber(t *testing.T) {
+	idx := NewIndexer(&RedEnvelopeService{})
+	events, sub := idx.SubscribeEventsBuffered(2)
+	defer sub.Unsubscribe()
+
+	applied := make(chan struct{})
+	go func() {
+		defer close(applied)
+		for i := int64(1); i <= 5; i++ {
+			idx.Apply(testCreatedEvent(i, testAddress0, 1, time.Now().Add(time.Hour), uint64(i)))
+		}
+	}()
+	select {
+	case <-applied:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Apply blocked on a subscriber that never reads")
+	}
+
+	var received int
+	for range events {
+		received++
+	}
+	if received != 2 {
+		t.Errorf("Expected the 2 buffered events before the channel closed, got %d", received)
+	}
+}
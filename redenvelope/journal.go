@@ -0,0 +1,321 @@
+package redenvelope
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Status entry journal
+const (
+	JournalSigned    = "signed"    // Sudah ditandatangani, belum tentu terkirim ke node
+	JournalPending   = "pending"   // Sudah diterima node, menunggu di-mine
+	JournalConfirmed = "confirmed" // Receipt ada dengan status sukses
+	JournalReverted  = "reverted"  // Receipt ada tapi transaksi revert
+	JournalDropped   = "dropped"   // Nonce sudah dipakai transaksi lain
+)
+
+// JournalEntry satu transaksi write yang dikirim oleh RedEnvelopeService
+type JournalEntry struct {
+	Hash        common.Hash       `json:"hash"`
+	Method      string            `json:"method"`
+	Args        map[string]string `json:"args"`
+	From        common.Address    `json:"from"`
+	Nonce       uint64            `json:"nonce"`
+	RawTx       hexutil.Bytes     `json:"rawTx"`
+	Status      string            `json:"status"`
+	BlockNumber uint64            `json:"blockNumber,omitempty"`
+	LastError   string            `json:"lastError,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+}
+
+// Open mengembalikan true kalau entry belum mencapai status final
+func (e *JournalEntry) Open() bool {
+	return e.Status == JournalSigned || e.Status == JournalPending
+}
+
+// Transaction decode raw signed tx yang tersimpan di entry
+func (e *JournalEntry) Transaction() (*types.Transaction, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(e.RawTx); err != nil {
+		return nil, fmt.Errorf("failed to decode raw tx %s: %v", e.Hash.Hex(), err)
+	}
+	return tx, nil
+}
+
+// Journal menyimpan semua transaksi write ke file JSON supaya bisa
+// dilacak ulang setelah proses crash
+type Journal struct {
+	path    string
+	mu      sync.Mutex
+	entries map[common.Hash]*JournalEntry
+}
+
+// OpenJournal membuka (atau membuat) journal di path yang diberikan
+func OpenJournal(path string) (*Journal, error) {
+	j := &Journal{
+		path:    path,
+		entries: make(map[common.Hash]*JournalEntry),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return j, nil
+		}
+		return nil, fmt.Errorf("failed to read journal: %v", err)
+	}
+	if len(data) == 0 {
+		return j, nil
+	}
+
+	var entries []*JournalEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse journal: %v", err)
+	}
+	for _, entry := range entries {
+		j.entries[entry.Hash] = entry
+	}
+	return j, nil
+}
+
+// Path lokasi file journal
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Record mencatat transaksi yang sudah ditandatangani sebelum dikirim ke node
+func (j *Journal) Record(method string, args map[string]string, from common.Address, tx *types.Transaction) error {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to encode tx: %v", err)
+	}
+
+	now := time.Now().UTC()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.entries[tx.Hash()] = &JournalEntry{
+		Hash:      tx.Hash(),
+		Method:    method,
+		Args:      args,
+		From:      from,
+		Nonce:     tx.Nonce(),
+		RawTx:     raw,
+		Status:    JournalSigned,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	return j.save()
+}
+
+// Update mengubah status entry dan menyimpan journal
+func (j *Journal) Update(hash common.Hash, status string, blockNumber uint64, lastErr error) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entry, ok := j.entries[hash]
+	if !ok {
+		return fmt.Errorf("journal entry %s not found", hash.Hex())
+	}
+	entry.Status = status
+	entry.BlockNumber = blockNumber
+	entry.LastError = ""
+	if lastErr != nil {
+		entry.LastError = lastErr.Error()
+	}
+	entry.UpdatedAt = time.Now().UTC()
+	return j.save()
+}
+
+// Get mengembalikan salinan entry berdasarkan tx hash
+func (j *Journal) Get(hash common.Hash) (JournalEntry, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entry, ok := j.entries[hash]
+	if !ok {
+		return JournalEntry{}, false
+	}
+	return *entry, true
+}
+
+// Entries mengembalikan salinan semua entry, urut berdasarkan waktu dibuat
+func (j *Journal) Entries() []JournalEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entries := make([]JournalEntry, 0, len(j.entries))
+	for _, entry := range j.entries {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(a, b int) bool {
+		if entries[a].CreatedAt.Equal(entries[b].CreatedAt) {
+			return entries[a].Nonce < entries[b].Nonce
+		}
+		return entries[a].CreatedAt.Before(entries[b].CreatedAt)
+	})
+	return entries
+}
+
+// Pending mengembalikan entry yang belum final
+func (j *Journal) Pending() []JournalEntry {
+	var open []JournalEntry
+	for _, entry := range j.Entries() {
+		if entry.Open() {
+			open = append(open, entry)
+		}
+	}
+	return open
+}
+
+// save menulis journal secara atomic (tmp file + rename). Caller harus pegang lock.
+func (j *Journal) save() error {
+	entries := make([]*JournalEntry, 0, len(j.entries))
+	for _, entry := range j.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(a, b int) bool {
+		return entries[a].CreatedAt.Before(entries[b].CreatedAt)
+	})
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode journal: %v", err)
+	}
+	return writeFileAtomic(j.path, data)
+}
+
+// writeFileAtomic menulis file lewat tmp file + fsync + rename
+func writeFileAtomic(path string, data []byte) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return fmt.Errorf("failed to create directory: %v", err)
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temp file: %v", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync temp file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %v", path, err)
+	}
+	return nil
+}
+
+// intentArgs format argumen method contract menjadi map nama -> string
+// supaya intent transaksi bisa dibaca langsung dari journal
+func intentArgs(method abi.Method, args []interface{}) map[string]string {
+	result := make(map[string]string, len(args))
+	for i, arg := range args {
+		name := fmt.Sprintf("arg%d", i)
+		if i < len(method.Inputs) && method.Inputs[i].Name != "" {
+			name = method.Inputs[i].Name
+		}
+		result[name] = formatArg(arg)
+	}
+	return result
+}
+
+// formatArg format satu argumen ABI menjadi string
+func formatArg(arg interface{}) string {
+	switch v := arg.(type) {
+	case *big.Int:
+		if v == nil {
+			return "0"
+		}
+		return v.String()
+	case common.Address:
+		return v.Hex()
+	case [32]byte:
+		return hexutil.Encode(v[:])
+	case common.Hash:
+		return v.Hex()
+	case []byte:
+		return hexutil.Encode(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// ReconcileJournal mencocokkan semua entry yang belum final dengan chain:
+// update status dari receipt, broadcast ulang tx yang hilang dari mempool,
+// dan tandai dropped kalau nonce-nya sudah dipakai transaksi lain. Entry
+// yang gagal tidak menghentikan entry lain; semua error digabung.
+func (s *RedEnvelopeService) ReconcileJournal(ctx context.Context) error {
+	if s.Journal == nil {
+		return fmt.Errorf("journal is not configured")
+	}
+
+	var errs []error
+	for _, entry := range s.Journal.Pending() {
+		if err := s.reconcileEntry(ctx, entry); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *RedEnvelopeService) reconcileEntry(ctx context.Context, entry JournalEntry) error {
+	receipt, err := s.Client.TransactionReceipt(ctx, entry.Hash)
+	if err == nil {
+		status := JournalConfirmed
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			status = JournalReverted
+		}
+		return s.Journal.Update(entry.Hash, status, receipt.BlockNumber.Uint64(), nil)
+	}
+	if err != ethereum.NotFound {
+		return fmt.Errorf("failed to get receipt for %s: %v", entry.Hash.Hex(), err)
+	}
+
+	// Masih ada di mempool node
+	if _, _, err := s.Client.TransactionByHash(ctx, entry.Hash); err == nil {
+		return s.Journal.Update(entry.Hash, JournalPending, 0, nil)
+	}
+
+	nonce, err := s.Client.NonceAt(ctx, entry.From, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get nonce: %v", err)
+	}
+	if nonce > entry.Nonce {
+		return s.Journal.Update(entry.Hash, JournalDropped, 0, fmt.Errorf("nonce %d already used", entry.Nonce))
+	}
+
+	tx, err := entry.Transaction()
+	if err != nil {
+		return err
+	}
+	if err := s.Client.SendTransaction(ctx, tx); err != nil {
+		return s.Journal.Update(entry.Hash, entry.Status, 0, err)
+	}
+	return s.Journal.Update(entry.Hash, JournalPending, 0, nil)
+}
@@ -0,0 +1,185 @@
+package redenvelope
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func signedTestTx(t *testing.T, nonce uint64) *types.Transaction {
+	tx := types.NewTransaction(nonce, common.HexToAddress(testContractAddress), big.NewInt(0), 300000, big.NewInt(1000000000), []byte{0x01})
+	signed, err := testAccount(t, 0).SignTx(tx, big.NewInt(31337))
+	if err != nil {
+		t.Fatalf("Failed to sign tx: %v", err)
+	}
+	return signed
+}
+
+func TestJournal_RecordAndReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.json")
+
+	journal, err := OpenJournal(path)
+	if err != nil {
+		t.Fatalf("Failed to open journal: %v", err)
+	}
+
+	from := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	tx1 := signedTestTx(t, 7)
+	tx2 := signedTestTx(t, 8)
+
+	if err := journal.Record("claimEnvelope", map[string]string{"envelopeId": "1"}, from, tx1); err != nil {
+		t.Fatalf("Failed to record tx1: %v", err)
+	}
+	if err := journal.Record("refundEnvelope", map[string]string{"envelopeId": "2"}, from, tx2); err != nil {
+		t.Fatalf("Failed to record tx2: %v", err)
+	}
+	if err := journal.Update(tx1.Hash(), JournalConfirmed, 42, nil); err != nil {
+		t.Fatalf("Failed to update tx1: %v", err)
+	}
+
+	// Simulasi restart: buka ulang dari file
+	reopened, err := OpenJournal(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen journal: %v", err)
+	}
+
+	if len(reopened.Entries()) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(reopened.Entries()))
+	}
+
+	entry, ok := reopened.Get(tx1.Hash())
+	if !ok {
+		t.Fatal("tx1 not found after reopen")
+	}
+	if entry.Status != JournalConfirmed || entry.BlockNumber != 42 {
+		t.Errorf("Unexpected tx1 entry: status=%s block=%d", entry.Status, entry.BlockNumber)
+	}
+
+	pending := reopened.Pending()
+	if len(pending) != 1 || pending[0].Hash != tx2.Hash() {
+		t.Fatalf("Expected only tx2 pending, got %+v", pending)
+	}
+	if pending[0].Nonce != 8 || pending[0].From != from || pending[0].Args["envelopeId"] != "2" {
+		t.Errorf("Unexpected pending entry: %+v", pending[0])
+	}
+
+	decoded, err := pending[0].Transaction()
+	if err != nil {
+		t.Fatalf("Failed to decode raw tx: %v", err)
+	}
+	if decoded.Hash() != tx2.Hash() {
+		t.Errorf("Raw tx hash mismatch: %s != %s", decoded.Hash().Hex(), tx2.Hash().Hex())
+	}
+}
+
+func TestIntentArgs_UsesABINames(t *testing.T) {
+	parsedABI, err := abi.JSON(strings.NewReader(RedEnvelopeABI))
+	if err != nil {
+		t.Fatalf("Failed to parse ABI: %v", err)
+	}
+
+	recipient := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	args := intentArgs(parsedABI.Methods["createEnvelope"], []interface{}{
+		uint8(GROUP_FIXED), common.Address{}, uint32(5), big.NewInt(100), uint64(1700000000), TestRoomIdHash, recipient,
+	})
+
+	if args["kind"] != "1" || args["totalClaims"] != "5" || args["amountPerClaimOrPot"] != "100" {
+		t.Errorf("Unexpected args: %v", args)
+	}
+	if args["recipient"] != recipient.Hex() {
+		t.Errorf("Expected recipient %s, got %s", recipient.Hex(), args["recipient"])
+	}
+	if !strings.HasPrefix(args["roomIdHash"], "0x") || len(args["roomIdHash"]) != 66 {
+		t.Errorf("Unexpected roomIdHash format: %s", args["roomIdHash"])
+	}
+}
+
+func TestReconcileJournal_ContinuesAfterFailedEntry(t *testing.T) {
+	api := &fakeEthAPI{}
+	service := newFakeSenderService(t, api)
+	journal, err := OpenJournal(filepath.Join(t.TempDir(), "journal.json"))
+	if err != nil {
+		t.Fatalf("Failed to open journal: %v", err)
+	}
+	service.Journal = journal
+
+	broken, good := signedTestTx(t, 0), signedTestTx(t, 1)
+	for _, tx := range []*types.Transaction{broken, good} {
+		if err := journal.Record("claimEnvelope", nil, service.Address, tx); err != nil {
+			t.Fatalf("Failed to record tx: %v", err)
+		}
+	}
+	journal.entries[broken.Hash()].RawTx = []byte{0x01}
+
+	err = service.ReconcileJournal(context.Background())
+	if err == nil || !strings.Contains(err.Error(), broken.Hash().Hex()) {
+		t.Fatalf("Expected error for broken entry, got %v", err)
+	}
+	if entry, _ := journal.Get(good.Hash()); entry.Status != JournalPending {
+		t.Errorf("Expected good entry to be rebroadcast and pending, got %s", entry.Status)
+	}
+	if sent := api.sentTxs(); len(sent) != 1 || sent[0].Hash() != good.Hash() {
+		t.Errorf("Expected only the good tx to be rebroadcast, got %d", len(sent))
+	}
+}
+
+func TestSendTransaction_ReportsJournalUpdateFailure(t *testing.T) {
+	api := &fakeEthAPI{balance: big.NewInt(1e18), sendErr: errors.New("nonce too low")}
+	service := newFakeSenderService(t, api)
+	dir := filepath.Join(t.TempDir(), "journal")
+	journal, err := OpenJournal(filepath.Join(dir, "journal.json"))
+	if err != nil {
+		t.Fatalf("Failed to open journal: %v", err)
+	}
+	service.Journal = journal
+
+	// Setelah Record, direktori journal diganti file biasa supaya Update gagal
+	call := claimEnvelopeCall(big.NewInt(1))
+	call.onSigned = func(*types.Transaction) error {
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+		return os.WriteFile(dir, nil, 0o600)
+	}
+	_, err = service.sendTransaction(call)
+	if err == nil || !strings.Contains(err.Error(), "nonce too low") || !strings.Contains(err.Error(), "failed to update journal") {
+		t.Fatalf("Expected broadcast and journal errors, got %v", err)
+	}
+	if !rejectedByNode(err) {
+		t.Error("Broadcast error should stay inspectable")
+	}
+}
+
+func TestSendTransaction_JournalFailureAfterBroadcastReturnsTx(t *testing.T) {
+	api := &fakeEthAPI{balance: big.NewInt(1e18)}
+	service := newFakeSenderService(t, api)
+	dir := filepath.Join(t.TempDir(), "journal")
+	journal, err := OpenJournal(filepath.Join(dir, "journal.json"))
+	if err != nil {
+		t.Fatalf("Failed to open journal: %v", err)
+	}
+	service.Journal = journal
+
+	call := claimEnvelopeCall(big.NewInt(1))
+	call.onSigned = func(*types.Transaction) error {
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+		return os.WriteFile(dir, nil, 0o600)
+	}
+	tx, err := service.sendTransaction(call)
+	if err != nil {
+		t.Fatalf("Broadcast tx should be returned despite the journal error, got %v", err)
+	}
+	if sent := api.sentTxs(); len(sent) != 1 || sent[0].Hash() != tx.Hash() {
+		t.Errorf("Expected the returned tx to be the broadcast one, sent %v", sent)
+	}
+}
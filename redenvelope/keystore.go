@@ -0,0 +1,91 @@
+package redenvelope
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Environment variable default untuk passphrase keystore
+const PassphraseEnv = "REDENVELOPE_PASSWORD"
+
+// NewKeystoreSigner membuat Signer dari file keystore V3 (format geth)
+func NewKeystoreSigner(keyfile string, passphrase string) (*KeySigner, error) {
+	keyJSON, err := os.ReadFile(keyfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore file: %v", err)
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: %v", err)
+	}
+
+	return NewKeySignerFromECDSA(key.PrivateKey), nil
+}
+
+// NewRedEnvelopeServiceFromKeystore membuat service dengan key dari file keystore V3
+func NewRedEnvelopeServiceFromKeystore(rpcURL string, contractAddress string, keyfile string, passphrase string, opts ...Option) (*RedEnvelopeService, error) {
+	signer, err := NewKeystoreSigner(keyfile, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRedEnvelopeServiceWithSigner(rpcURL, contractAddress, signer, opts...)
+}
+
+// ResolvePassphrase mengambil passphrase dari environment variable envVar,
+// atau dari file passwordFile kalau env kosong. Newline di akhir file dibuang.
+func ResolvePassphrase(envVar string, passwordFile string) (string, error) {
+	if envVar != "" {
+		if passphrase, ok := os.LookupEnv(envVar); ok {
+			return passphrase, nil
+		}
+	}
+
+	if passwordFile != "" {
+		data, err := os.ReadFile(passwordFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read password file: %v", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+
+	if envVar == "" {
+		return "", fmt.Errorf("no passphrase: provide a password file")
+	}
+	return "", fmt.Errorf("no passphrase: set %s or provide a password file", envVar)
+}
+
+// ImportKeystoreKey mengenkripsi private key hex ke keystore directory
+func ImportKeystoreKey(keystoreDir string, privateKeyHex string, passphrase string) (accounts.Account, error) {
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(privateKeyHex), "0x"))
+	if err != nil {
+		return accounts.Account{}, fmt.Errorf("failed to load private key: %v", err)
+	}
+
+	ks := keystore.NewKeyStore(keystoreDir, keystore.StandardScryptN, keystore.StandardScryptP)
+	account, err := ks.ImportECDSA(privateKey, passphrase)
+	if err != nil {
+		return accounts.Account{}, fmt.Errorf("failed to import key: %v", err)
+	}
+	return account, nil
+}
+
+// ListKeystoreAccounts mendapatkan semua akun di keystore directory
+func ListKeystoreAccounts(keystoreDir string) ([]accounts.Account, error) {
+	info, err := os.Stat(keystoreDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open keystore directory: %v", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", keystoreDir)
+	}
+
+	ks := keystore.NewKeyStore(keystoreDir, keystore.StandardScryptN, keystore.StandardScryptP)
+	return ks.Accounts(), nil
+}
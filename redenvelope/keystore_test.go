@@ -0,0 +1,66 @@
+package redenvelope
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+func TestKeystoreSigner_ImportListAndLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	// Scrypt ringan supaya test cepat
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.ImportECDSA(testAccount(t, 0).key, "secret")
+	if err != nil {
+		t.Fatalf("Failed to import key: %v", err)
+	}
+
+	accounts, err := ListKeystoreAccounts(dir)
+	if err != nil {
+		t.Fatalf("Failed to list accounts: %v", err)
+	}
+	if len(accounts) != 1 || accounts[0].Address != testAddress0 {
+		t.Fatalf("Unexpected accounts: %+v", accounts)
+	}
+
+	signer, err := NewKeystoreSigner(account.URL.Path, "secret")
+	if err != nil {
+		t.Fatalf("Failed to load keystore signer: %v", err)
+	}
+	if signer.Address() != testAddress0 {
+		t.Errorf("Expected %s, got %s", testAddress0.Hex(), signer.Address().Hex())
+	}
+
+	if _, err := NewKeystoreSigner(account.URL.Path, "wrong"); err == nil {
+		t.Error("Expected error for wrong passphrase")
+	}
+}
+
+func TestResolvePassphrase(t *testing.T) {
+	t.Setenv("TEST_REDENVELOPE_PASSWORD", "from-env")
+
+	passphrase, err := ResolvePassphrase("TEST_REDENVELOPE_PASSWORD", "")
+	if err != nil || passphrase != "from-env" {
+		t.Errorf("Expected passphrase from env, got %q (%v)", passphrase, err)
+	}
+
+	file := filepath.Join(t.TempDir(), "password.txt")
+	if err := os.WriteFile(file, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write password file: %v", err)
+	}
+	passphrase, err = ResolvePassphrase("TEST_REDENVELOPE_PASSWORD_UNSET", file)
+	if err != nil || passphrase != "from-file" {
+		t.Errorf("Expected passphrase from file, got %q (%v)", passphrase, err)
+	}
+
+	if _, err := ResolvePassphrase("TEST_REDENVELOPE_PASSWORD_UNSET", ""); err == nil {
+		t.Error("Expected error when no passphrase source is available")
+	}
+	if _, err := ResolvePassphrase("", ""); err == nil || strings.Contains(err.Error(), "set  or") {
+		t.Errorf("Expected error without empty env var name, got %v", err)
+	}
+}
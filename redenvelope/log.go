@@ -0,0 +1,177 @@
+package redenvelope
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// WithLogger mencatat panggilan RPC, tx yang ditandatangani dan dikirim,
+// receipt, serta event hasil decode ke logger. Tanpa option ini service
+// tidak mencatat apa pun.
+//
+// Level yang dipakai: Debug untuk setiap panggilan RPC dan event dari
+// WatchEvents, Info untuk tx dan receipt, Warn untuk revert dan broadcast
+// yang gagal.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *RedEnvelopeService) error {
+		s.Logger = logger
+		if s.transport != nil {
+			s.transport.logger.Store(logger)
+		}
+		return nil
+	}
+}
+
+// discardLogger dipakai kalau Logger nil
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// log logger service, tidak pernah nil
+func (s *RedEnvelopeService) log() *slog.Logger {
+	if s.Logger == nil {
+		return discardLogger
+	}
+	return s.Logger
+}
+
+// RedactedValue pengganti nilai attribute yang disensor RedactAttr
+const RedactedValue = "[REDACTED]"
+
+// sensitiveKeys potongan nama attribute yang isinya tidak boleh masuk log
+var sensitiveKeys = []string{"privatekey", "passphrase", "password", "mnemonic", "secret", "seed", "authorization", "sessiontoken", "accesstoken"}
+
+// RedactAttr untuk slog.HandlerOptions.ReplaceAttr: menyensor attribute yang
+// namanya mengandung privateKey, passphrase, password, mnemonic, secret,
+// seed, authorization, sessionToken atau accessToken (tidak peduli huruf
+// besar kecil, "_" dan "-" diabaikan). Signer aman dicatat langsung karena
+// LogValue-nya hanya address.
+func RedactAttr(groups []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(a.Key))
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return slog.String(a.Key, RedactedValue)
+		}
+	}
+	return a
+}
+
+// LogValue hanya address; private key tidak pernah ikut ke log
+func (k *KeySigner) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("type", "key"), slog.String("address", k.address.Hex()))
+}
+
+// LogValue address external signer, tanpa URL yang bisa berisi kredensial
+func (e *ExternalSigner) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("type", "external"), slog.String("address", e.address.Hex()))
+}
+
+// txAttrs hash, nonce, gas, dan fee tx
+func txAttrs(tx *types.Transaction) []any {
+	attrs := []any{
+		slog.String("tx", tx.Hash().Hex()),
+		slog.Uint64("nonce", tx.Nonce()),
+		slog.Uint64("gas", tx.Gas()),
+	}
+	if tx.Type() == types.LegacyTxType {
+		attrs = append(attrs, slog.String("gasPrice", tx.GasPrice().String()))
+	} else {
+		attrs = append(attrs,
+			slog.String("maxFeePerGas", tx.GasFeeCap().String()),
+			slog.String("maxPriorityFeePerGas", tx.GasTipCap().String()))
+	}
+	if value := tx.Value(); value.Sign() > 0 {
+		attrs = append(attrs, slog.String("value", value.String()))
+	}
+	return attrs
+}
+
+// intentAttrs method dan argumen tx (envelopeId, recipient, ...) sebagai
+// attribute, supaya log bisa dicari per envelope
+func intentAttrs(method string, args map[string]string) []any {
+	attrs := []any{slog.String("method", method)}
+	for _, name := range slices.Sorted(maps.Keys(args)) {
+		attrs = append(attrs, slog.String(name, args[name]))
+	}
+	return attrs
+}
+
+// callAttrs attribute writeCall: method, from, dan argumennya
+func (s *RedEnvelopeService) callAttrs(call *writeCall) []any {
+	attrs := intentAttrs(call.method, intentArgs(s.ABI.Methods[call.method], call.args))
+	return append(attrs, slog.String("from", s.Address.Hex()))
+}
+
+// logSent mencatat hasil broadcast; nonce ikut supaya error nonce bisa
+// dilacak ke envelope-nya
+func (s *RedEnvelopeService) logSent(intent []any, tx *types.Transaction, err error) {
+	attrs := append(intent, txAttrs(tx)...)
+	if err != nil {
+		s.log().Warn("failed to send tx", append(attrs, slog.Any("error", err))...)
+		return
+	}
+	s.log().Info("sent tx", attrs...)
+}
+
+// logReceipt mencatat receipt dan event RedEnvelope di dalamnya
+func (s *RedEnvelopeService) logReceipt(ctx context.Context, method string, receipt *types.Receipt) {
+	logger := s.log()
+	attrs := []any{
+		slog.String("method", method),
+		slog.String("tx", receipt.TxHash.Hex()),
+		slog.Uint64("block", receipt.BlockNumber.Uint64()),
+		slog.Uint64("gasUsed", receipt.GasUsed),
+	}
+	if receipt.EffectiveGasPrice != nil {
+		attrs = append(attrs, slog.String("effectiveGasPrice", receipt.EffectiveGasPrice.String()))
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		logger.WarnContext(ctx, "tx reverted", attrs...)
+		return
+	}
+	logger.InfoContext(ctx, "tx mined", attrs...)
+
+	events, err := s.EventsFromReceipt(receipt)
+	if err != nil {
+		logger.WarnContext(ctx, "failed to decode receipt events", slog.String("tx", receipt.TxHash.Hex()), slog.Any("error", err))
+		return
+	}
+	for _, event := range events {
+		s.logEvent(ctx, slog.LevelInfo, event)
+	}
+}
+
+// logEvent mencatat satu event hasil decode
+func (s *RedEnvelopeService) logEvent(ctx context.Context, level slog.Level, event EnvelopeEvent) {
+	logger := s.log()
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := []any{
+		slog.String("event", event.Name),
+		slog.String("envelopeId", event.EnvelopeID.String()),
+		slog.String("tx", event.TxHash.Hex()),
+		slog.Uint64("block", event.BlockNumber),
+		slog.Uint64("logIndex", uint64(event.LogIndex)),
+	}
+	switch {
+	case event.Created != nil:
+		attrs = append(attrs,
+			slog.String("creator", event.Created.Creator.Hex()),
+			slog.String("kind", KindName(event.Created.Kind)),
+			slog.String("token", event.Created.Token.Hex()),
+			slog.String("netPot", event.Created.NetPot.String()),
+			slog.Uint64("totalClaims", uint64(event.Created.TotalClaims)))
+	case event.Claimed != nil:
+		attrs = append(attrs,
+			slog.String("claimer", event.Claimed.Claimer.Hex()),
+			slog.String("payout", event.Claimed.Payout.String()),
+			slog.Uint64("claimIndex", uint64(event.Claimed.ClaimIndex)))
+	case event.Refunded != nil:
+		attrs = append(attrs, slog.String("refundAmount", event.Refunded.RefundAmount.String()))
+	}
+	logger.Log(ctx, level, "envelope event", attrs...)
+}
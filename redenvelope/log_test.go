@@ -0,0 +1,131 @@
+package redenvelope
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Private key Account #0 Hardhat, hanya untuk test
+const testPrivateKey = "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+// newTestLogger logger JSON level Debug dengan RedactAttr ke buf
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: RedactAttr}))
+}
+
+// logLines decode setiap baris log JSON
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var decoded map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &decoded); err != nil {
+			t.Fatalf("Log line is not JSON: %s", line)
+		}
+		lines = append(lines, decoded)
+	}
+	return lines
+}
+
+func TestLogger_RedactsKeyMaterial(t *testing.T) {
+	signer, err := NewKeySigner(testPrivateKey)
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+
+	var buf bytes.Buffer
+	newTestLogger(&buf).Info("loaded",
+		slog.Any("signer", signer),
+		slog.String("privateKey", testPrivateKey),
+		slog.String("keystore_passphrase", "hunter2"),
+		slog.String("webhookSecret", "s3cret"),
+		slog.String("token", "0x00000000000000000000000000000000000000aa"))
+
+	out := buf.String()
+	for _, leaked := range []string{testPrivateKey, "hunter2", "s3cret"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("Log leaks %q: %s", leaked, out)
+		}
+	}
+	line := logLines(t, &buf)[0]
+	if line["privateKey"] != RedactedValue || line["keystore_passphrase"] != RedactedValue {
+		t.Errorf("Expected redacted values: %v", line)
+	}
+	if line["signer"].(map[string]interface{})["address"] != signer.Address().Hex() {
+		t.Errorf("Expected signer address in log: %v", line)
+	}
+	// token di sini address ERC-20, bukan kredensial
+	if line["token"] == RedactedValue {
+		t.Errorf("ERC-20 token address should not be redacted: %v", line)
+	}
+}
+
+func TestLogger_SentTxHasEnvelopeAndNonce(t *testing.T) {
+	service := newABIOnlyService(t)
+	var buf bytes.Buffer
+	service.Logger = newTestLogger(&buf)
+
+	tx := types.NewTx(&types.LegacyTx{Nonce: 42, Gas: 200000, GasPrice: big.NewInt(7)})
+	call := claimEnvelopeCall(big.NewInt(9))
+	service.logSent(intentAttrs(call.method, intentArgs(service.ABI.Methods[call.method], call.args)), tx, errors.New("nonce too low"))
+
+	line := logLines(t, &buf)[0]
+	want := map[string]interface{}{
+		"level":      "WARN",
+		"msg":        "failed to send tx",
+		"method":     "claimEnvelope",
+		"envelopeId": "9",
+		"nonce":      float64(42),
+		"gasPrice":   "7",
+		"tx":         tx.Hash().Hex(),
+		"error":      "nonce too low",
+	}
+	for key, value := range want {
+		if line[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, line[key])
+		}
+	}
+}
+
+func TestLogger_RPCCalls(t *testing.T) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", fakeGasAPI{}); err != nil {
+		t.Fatalf("Failed to register fake eth API: %v", err)
+	}
+	node := httptest.NewServer(server)
+	defer node.Close()
+
+	service, err := dialService(node.URL, common.Address{}, NewWatchOnlySigner(common.Address{}))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer service.Client.Close()
+
+	var buf bytes.Buffer
+	if err := service.apply([]Option{WithLogger(newTestLogger(&buf))}); err != nil {
+		t.Fatalf("Failed to apply logger: %v", err)
+	}
+	service.Client.ChainID(context.Background())
+	service.Client.SuggestGasPrice(context.Background())
+
+	lines := logLines(t, &buf)
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %d: %s", len(lines), buf.String())
+	}
+	if lines[0]["msg"] != "rpc call" || lines[0]["rpcMethod"] != "eth_chainId" || lines[0]["level"] != "DEBUG" {
+		t.Errorf("Unexpected chain ID log: %v", lines[0])
+	}
+	if lines[1]["msg"] != "rpc call failed" || lines[1]["rpcMethod"] != "eth_gasPrice" || lines[1]["error"] != "gas oracle down" {
+		t.Errorf("Unexpected gas price log: %v", lines[1])
+	}
+}
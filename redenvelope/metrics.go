@@ -0,0 +1,175 @@
+package redenvelope
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"rpcsol/metrics"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Hasil tx di label result redenvelope_tx_submissions_total
+const (
+	txResultSent   = "sent"
+	txResultFailed = "failed"
+)
+
+// revertUnknown label error untuk revert yang tidak bisa di-decode, misalnya
+// receipt dengan status gagal
+const revertUnknown = "Unknown"
+
+// Metrics metric Prometheus RedEnvelopeService. Semua method aman dipanggil
+// pada *Metrics nil, jadi service tanpa WithMetrics tidak mencatat apa pun.
+type Metrics struct {
+	rpcDuration  *metrics.HistogramVec
+	rpcErrors    *metrics.CounterVec
+	submissions  *metrics.CounterVec
+	reverts      *metrics.CounterVec
+	gasUsed      *metrics.HistogramVec
+	receiptWait  *metrics.HistogramVec
+	awaitingTxs  *metrics.Gauge
+	chainHead    *metrics.Gauge
+	indexedBlock *metrics.Gauge
+	indexerLag   *metrics.Gauge
+
+	mu      sync.Mutex
+	head    uint64
+	indexed uint64
+}
+
+// NewMetrics mendaftarkan metric RedEnvelope di reg
+func NewMetrics(reg *metrics.Registry) *Metrics {
+	return &Metrics{
+		rpcDuration: reg.Histogram("redenvelope_rpc_duration_seconds",
+			"Latency of JSON-RPC calls to the node by method (HTTP endpoints only)", nil, "method"),
+		rpcErrors: reg.Counter("redenvelope_rpc_errors_total",
+			"JSON-RPC calls to the node that failed, by method", "method"),
+		submissions: reg.Counter("redenvelope_tx_submissions_total",
+			"Transactions broadcast to the node by contract method and result (sent, failed)", "method", "result"),
+		reverts: reg.Counter("redenvelope_tx_reverts_total",
+			"Reverted simulations and mined transactions by contract method and decoded error", "method", "error"),
+		gasUsed: reg.Histogram("redenvelope_tx_gas_used",
+			"Gas used by mined transactions by contract method", metrics.ExponentialBuckets(25000, 2, 8), "method"),
+		receiptWait: reg.Histogram("redenvelope_tx_receipt_wait_seconds",
+			"Time from WaitMined until the receipt (and confirmations) arrived, by contract method",
+			metrics.ExponentialBuckets(0.5, 2, 10), "method"),
+		awaitingTxs: reg.Gauge("redenvelope_tx_awaiting_receipt",
+			"Transactions currently awaited by WaitMined (receipt or confirmations not yet arrived)").With(),
+		chainHead: reg.Gauge("redenvelope_chain_head_block",
+			"Latest block number seen while watching events").With(),
+		indexedBlock: reg.Gauge("redenvelope_indexer_block",
+			"Last block read into the envelope index").With(),
+		indexerLag: reg.Gauge("redenvelope_indexer_lag_blocks",
+			"Blocks between the chain head and the envelope index, including confirmations").With(),
+	}
+}
+
+// WithMetrics mencatat latency RPC, tx, revert, gas, dan lag indexer ke m
+func WithMetrics(m *Metrics) Option {
+	return func(s *RedEnvelopeService) error {
+		s.Metrics = m
+		if s.transport != nil {
+			s.transport.metrics.Store(m)
+		}
+		return nil
+	}
+}
+
+// rpcCall mencatat satu panggilan JSON-RPC
+func (m *Metrics) rpcCall(method string, elapsed time.Duration, failed bool) {
+	if m == nil {
+		return
+	}
+	m.rpcDuration.With(method).Observe(elapsed.Seconds())
+	if failed {
+		m.rpcErrors.With(method).Inc()
+	}
+}
+
+// submitted mencatat hasil broadcast tx
+func (m *Metrics) submitted(method string, err error) {
+	if m == nil {
+		return
+	}
+	if err != nil {
+		m.submissions.With(method, txResultFailed).Inc()
+		return
+	}
+	m.submissions.With(method, txResultSent).Inc()
+}
+
+// awaiting mencatat tx yang sedang ditunggu WaitMined; fungsi hasilnya
+// dipanggil saat WaitMined selesai, berhasil atau tidak
+func (m *Metrics) awaiting() func() {
+	if m == nil {
+		return func() {}
+	}
+	m.awaitingTxs.Add(1)
+	return func() { m.awaitingTxs.Add(-1) }
+}
+
+// reverted mencatat revert kalau err adalah *ContractError
+func (m *Metrics) reverted(method string, err error) {
+	var contractErr *ContractError
+	if m == nil || !errors.As(err, &contractErr) {
+		return
+	}
+	m.reverts.With(method, contractErr.Name).Inc()
+}
+
+// mined mencatat receipt tx yang ditunggu WaitMined
+func (m *Metrics) mined(method string, receipt *types.Receipt, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	m.receiptWait.With(method).Observe(elapsed.Seconds())
+	m.gasUsed.With(method).Observe(float64(receipt.GasUsed))
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		m.reverts.With(method, revertUnknown).Inc()
+	}
+}
+
+// observeHead mencatat head chain dari WatchEvents
+func (m *Metrics) observeHead(head uint64) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.head = max(m.head, head)
+	m.chainHead.Set(float64(m.head))
+	m.updateLag()
+}
+
+// observeIndexed mencatat block terakhir yang masuk index
+func (m *Metrics) observeIndexed(block uint64) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.indexed = max(m.indexed, block)
+	m.indexedBlock.Set(float64(m.indexed))
+	m.updateLag()
+}
+
+// updateLag caller harus pegang lock
+func (m *Metrics) updateLag() {
+	if m.indexed == 0 || m.head < m.indexed {
+		return
+	}
+	m.indexerLag.Set(float64(m.head - m.indexed))
+}
+
+// methodOf nama method contract dari calldata tx, "unknown" kalau bukan
+// method RedEnvelope
+func (s *RedEnvelopeService) methodOf(tx *types.Transaction) string {
+	if data := tx.Data(); len(data) >= 4 {
+		if method, err := s.ABI.MethodById(data[:4]); err == nil {
+			return method.Name
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,119 @@
+package redenvelope
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"rpcsol/metrics"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// fakeGasAPI node palsu yang gagal di eth_gasPrice
+type fakeGasAPI struct{}
+
+func (fakeGasAPI) ChainId() *hexutil.Big { return (*hexutil.Big)(big.NewInt(31337)) }
+
+func (fakeGasAPI) GasPrice() (*hexutil.Big, error) { return nil, errors.New("gas oracle down") }
+
+// scrape output /metrics dari reg
+func scrape(t *testing.T, reg *metrics.Registry) string {
+	var b strings.Builder
+	if _, err := reg.WriteTo(&b); err != nil {
+		t.Fatalf("Failed to write metrics: %v", err)
+	}
+	return b.String()
+}
+
+func TestMetrics_RPCLatencyAndErrorsByMethod(t *testing.T) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", fakeGasAPI{}); err != nil {
+		t.Fatalf("Failed to register fake eth API: %v", err)
+	}
+	node := httptest.NewServer(server)
+	defer node.Close()
+
+	service, err := dialService(node.URL, common.Address{}, NewWatchOnlySigner(common.Address{}))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer service.Client.Close()
+
+	reg := metrics.NewRegistry()
+	if err := service.apply([]Option{WithMetrics(NewMetrics(reg))}); err != nil {
+		t.Fatalf("Failed to apply metrics: %v", err)
+	}
+
+	if _, err := service.Client.SuggestGasPrice(context.Background()); err == nil {
+		t.Fatal("Expected gas price error")
+	}
+	if _, err := service.Client.ChainID(context.Background()); err != nil {
+		t.Fatalf("Failed to get chain ID: %v", err)
+	}
+
+	out := scrape(t, reg)
+	for _, want := range []string{
+		`redenvelope_rpc_errors_total{method="eth_gasPrice"} 1`,
+		`redenvelope_rpc_duration_seconds_count{method="eth_gasPrice"} 1`,
+		`redenvelope_rpc_duration_seconds_count{method="eth_chainId"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `redenvelope_rpc_errors_total{method="eth_chainId"}`) {
+		t.Errorf("eth_chainId should not count as an error:\n%s", out)
+	}
+}
+
+func TestMetrics_TxLifecycleAndIndexerLag(t *testing.T) {
+	reg := metrics.NewRegistry()
+	m := NewMetrics(reg)
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1})
+
+	m.submitted("claimEnvelope", nil)
+	m.submitted("claimEnvelope", errors.New("nonce too low"))
+	m.reverted("claimEnvelope", ErrAlreadyClaimed)
+	m.reverted("claimEnvelope", errors.New("not a contract error"))
+	if out := scrape(t, reg); !strings.Contains(out, "redenvelope_tx_awaiting_receipt 0\n") {
+		t.Errorf("Broadcast alone should not count as awaited:\n%s", out)
+	}
+
+	done := m.awaiting()
+	if out := scrape(t, reg); !strings.Contains(out, "redenvelope_tx_awaiting_receipt 1\n") {
+		t.Errorf("Expected one awaited tx:\n%s", out)
+	}
+	done()
+
+	m.mined("claimEnvelope", &types.Receipt{TxHash: tx.Hash(), GasUsed: 60000, Status: types.ReceiptStatusFailed}, 0)
+	m.observeHead(120)
+	m.observeIndexed(100)
+
+	out := scrape(t, reg)
+	for _, want := range []string{
+		`redenvelope_tx_submissions_total{method="claimEnvelope",result="sent"} 1`,
+		`redenvelope_tx_submissions_total{method="claimEnvelope",result="failed"} 1`,
+		`redenvelope_tx_reverts_total{method="claimEnvelope",error="AlreadyClaimed"} 1`,
+		`redenvelope_tx_reverts_total{method="claimEnvelope",error="Unknown"} 1`,
+		`redenvelope_tx_gas_used_sum{method="claimEnvelope"} 60000`,
+		"redenvelope_tx_awaiting_receipt 0\n",
+		"redenvelope_indexer_lag_blocks 20\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Missing %q in:\n%s", want, out)
+		}
+	}
+
+	// Service tanpa WithMetrics tidak panic
+	var none *Metrics
+	none.submitted("claimEnvelope", nil)
+	none.awaiting()()
+	none.observeHead(1)
+}
@@ -0,0 +1,145 @@
+package redenvelope
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ChainIDMismatchError node berada di chain yang berbeda dari config
+type ChainIDMismatchError struct {
+	Expected uint64
+	Actual   *big.Int
+}
+
+func (e *ChainIDMismatchError) Error() string {
+	return fmt.Sprintf("node is on chain %s, expected chain %d", e.Actual, e.Expected)
+}
+
+// ErrGasPriceTooHigh gas price melewati GasPolicy.MaxGasPrice
+var ErrGasPriceTooHigh = errors.New("gas price exceeds configured maximum")
+
+// NewRedEnvelopeServiceForNetwork membuat service dari NetworkConfig. RPC URL
+// dicoba berurutan; service menolak start kalau ChainID node tidak sama
+// dengan network.ChainID.
+func NewRedEnvelopeServiceForNetwork(network *NetworkConfig, signer Signer, opts ...Option) (*RedEnvelopeService, error) {
+	if network.ContractAddress == (common.Address{}) {
+		return nil, fmt.Errorf("network %q has no contractAddress, deploy first", network.Name)
+	}
+
+	var failures []string
+	for _, url := range network.RPCURLs {
+		service, err := dialService(url, network.ContractAddress, signer)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", url, err))
+			continue
+		}
+
+		networkOpts := []Option{WithNetwork(network)}
+		if err := service.apply(append(networkOpts, opts...)); err != nil {
+			return nil, err
+		}
+		return service, nil
+	}
+	return nil, fmt.Errorf("no reachable RPC endpoint for network %q: %s", network.Name, strings.Join(failures, "; "))
+}
+
+// WithNetwork memastikan ChainID node sama dengan network, lalu memakai
+// gas policy dan confirmation depth network tersebut
+func WithNetwork(network *NetworkConfig) Option {
+	return func(s *RedEnvelopeService) error {
+		if err := WithExpectedChainID(network.ChainID)(s); err != nil {
+			return err
+		}
+		s.Network = network
+		s.GasPolicy = network.Gas
+		s.Confirmations = network.Confirmations
+		return nil
+	}
+}
+
+// WithExpectedChainID menolak node yang ChainID-nya berbeda
+func WithExpectedChainID(chainID uint64) Option {
+	return func(s *RedEnvelopeService) error {
+		if !s.ChainID.IsUint64() || s.ChainID.Uint64() != chainID {
+			return &ChainIDMismatchError{Expected: chainID, Actual: s.ChainID}
+		}
+		return nil
+	}
+}
+
+// gasPrice gas price dari node setelah GasPolicy diterapkan
+func (s *RedEnvelopeService) gasPrice(ctx context.Context) (*big.Int, error) {
+	suggested, err := s.Client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %v", err)
+	}
+	return s.GasPolicy.apply(suggested)
+}
+
+func (p GasPolicy) apply(suggested *big.Int) (*big.Int, error) {
+	price := new(big.Int).Set(suggested)
+	if p.PriceMultiplier > 0 && p.PriceMultiplier != 1 {
+		scaled, _ := new(big.Float).Mul(new(big.Float).SetInt(price), big.NewFloat(p.PriceMultiplier)).Int(nil)
+		price = scaled
+	}
+	if p.MaxGasPrice != nil && price.Cmp(p.MaxGasPrice) > 0 {
+		return nil, fmt.Errorf("%w: %s wei > %s wei", ErrGasPriceTooHigh, price, p.MaxGasPrice)
+	}
+	return price, nil
+}
+
+// WaitMined menunggu receipt tx lalu menunggu sampai Confirmations block
+// di atasnya
+func (s *RedEnvelopeService) WaitMined(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	done := s.Metrics.awaiting()
+	defer done()
+
+	start := time.Now()
+	receipt, err := s.waitMined(ctx, tx)
+	if err != nil {
+		s.log().Warn("failed to wait for receipt", slog.String("tx", tx.Hash().Hex()), slog.Any("error", err))
+		return nil, err
+	}
+	method := s.methodOf(tx)
+	s.Metrics.mined(method, receipt, time.Since(start))
+	s.logReceipt(ctx, method, receipt)
+	return receipt, nil
+}
+
+func (s *RedEnvelopeService) waitMined(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	receipt, err := bind.WaitMined(ctx, s.Client, tx.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for %s: %v", tx.Hash().Hex(), err)
+	}
+	if s.Confirmations == 0 {
+		return receipt, nil
+	}
+
+	target := receipt.BlockNumber.Uint64() + s.Confirmations
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		head, err := s.Client.BlockNumber(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get block number: %v", err)
+		}
+		if head >= target {
+			// Receipt diambil ulang, tx bisa pindah block karena reorg
+			return bind.WaitMined(ctx, s.Client, tx.Hash())
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
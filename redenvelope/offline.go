@@ -0,0 +1,333 @@
+package redenvelope
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// OfflineIntent maksud transaksi dalam bentuk yang bisa dibaca manusia,
+// dicocokkan ulang dengan calldata di setiap langkah
+type OfflineIntent struct {
+	Method string            `json:"method"`
+	Args   map[string]string `json:"args"`
+}
+
+// OfflineTx transaksi portable untuk workflow build (online) -> sign
+// (air-gapped) -> broadcast (online)
+type OfflineTx struct {
+	ChainID  *hexutil.Big   `json:"chainId"`
+	From     common.Address `json:"from"`
+	To       common.Address `json:"to"`
+	Nonce    hexutil.Uint64 `json:"nonce"`
+	Gas      hexutil.Uint64 `json:"gas"`
+	GasPrice *hexutil.Big   `json:"gasPrice"`
+	Value    *hexutil.Big   `json:"value"`
+	Data     hexutil.Bytes  `json:"data"`
+	Intent   OfflineIntent  `json:"intent"`
+
+	// Diisi setelah SignOfflineTx
+	RawTx hexutil.Bytes `json:"rawTx,omitempty"`
+	Hash  *common.Hash  `json:"hash,omitempty"`
+}
+
+// Signed true kalau file sudah berisi raw signed tx
+func (o *OfflineTx) Signed() bool {
+	return len(o.RawTx) > 0
+}
+
+// unsignedTransaction membangun tx legacy dari field yang dideklarasikan
+func (o *OfflineTx) unsignedTransaction() *types.Transaction {
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    uint64(o.Nonce),
+		GasPrice: o.GasPrice.ToInt(),
+		Gas:      uint64(o.Gas),
+		To:       &o.To,
+		Value:    o.Value.ToInt(),
+		Data:     o.Data,
+	})
+}
+
+// ReadOfflineTx membaca file OfflineTx
+func ReadOfflineTx(path string) (*OfflineTx, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read offline tx: %v", err)
+	}
+	var tx OfflineTx
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return nil, fmt.Errorf("failed to parse offline tx: %v", err)
+	}
+	if tx.ChainID == nil || tx.GasPrice == nil || tx.Value == nil {
+		return nil, fmt.Errorf("offline tx is missing chainId, gasPrice or value")
+	}
+	return &tx, nil
+}
+
+// WriteOfflineTx menulis OfflineTx ke file JSON
+func WriteOfflineTx(path string, tx *OfflineTx) error {
+	data, err := json.MarshalIndent(tx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode offline tx: %v", err)
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// WatchOnlySigner Signer tanpa key, hanya membawa alamat. Dipakai di mesin
+// online untuk build transaksi yang nanti ditandatangani offline.
+type WatchOnlySigner struct {
+	address common.Address
+}
+
+// NewWatchOnlySigner membuat WatchOnlySigner untuk address
+func NewWatchOnlySigner(address common.Address) *WatchOnlySigner {
+	return &WatchOnlySigner{address: address}
+}
+
+// Address alamat akun
+func (w *WatchOnlySigner) Address() common.Address {
+	return w.address
+}
+
+// SignTx selalu gagal, akun ini watch-only
+func (w *WatchOnlySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return nil, fmt.Errorf("account %s is watch-only", w.address.Hex())
+}
+
+// SignTypedData selalu gagal, akun ini watch-only
+func (w *WatchOnlySigner) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	return nil, fmt.Errorf("account %s is watch-only", w.address.Hex())
+}
+
+// WatchOnly salinan service untuk address lain dengan WatchOnlySigner,
+// memakai client yang sama. Dipakai untuk BuildOffline* atas nama user.
+// Journal dan idempotency tidak ikut, keduanya milik signer asli.
+func (s *RedEnvelopeService) WatchOnly(address common.Address) *RedEnvelopeService {
+	copied := *s
+	copied.Signer = NewWatchOnlySigner(address)
+	copied.Address = address
+	copied.Journal = nil
+	copied.Idempotency = nil
+	return &copied
+}
+
+// BuildOfflineCreate membangun tx createEnvelope unsigned untuk s.Address
+func (s *RedEnvelopeService) BuildOfflineCreate(
+	kind uint8,
+	token common.Address,
+	totalClaims uint32,
+	amount *big.Int,
+	expiryDuration time.Duration,
+	roomIdHash [32]byte,
+	recipient common.Address,
+) (*OfflineTx, error) {
+	if err := checkAmount(amount); err != nil {
+		return nil, err
+	}
+	expiry := uint64(time.Now().Add(expiryDuration).Unix())
+	return s.buildOffline(createEnvelopeCall(kind, token, totalClaims, amount, expiry, roomIdHash, recipient))
+}
+
+// BuildOfflineClaim membangun tx claimEnvelope unsigned untuk s.Address
+func (s *RedEnvelopeService) BuildOfflineClaim(envelopeId *big.Int) (*OfflineTx, error) {
+	return s.buildOffline(claimEnvelopeCall(envelopeId))
+}
+
+// BuildOfflineRefund membangun tx refundEnvelope unsigned untuk s.Address
+func (s *RedEnvelopeService) BuildOfflineRefund(envelopeId *big.Int) (*OfflineTx, error) {
+	return s.buildOffline(refundEnvelopeCall(envelopeId))
+}
+
+// BuildOfflineUpdateFeeBps membangun tx updateFeeBps unsigned untuk s.Address
+func (s *RedEnvelopeService) BuildOfflineUpdateFeeBps(feeBps uint16) (*OfflineTx, error) {
+	if feeBps > MaxFeeBps {
+		return nil, fmt.Errorf("feeBps %d exceeds %d", feeBps, MaxFeeBps)
+	}
+	return s.buildOffline(updateFeeBpsCall(feeBps))
+}
+
+// BuildOfflineUpdateTreasury membangun tx updateTreasury unsigned untuk s.Address
+func (s *RedEnvelopeService) BuildOfflineUpdateTreasury(treasury common.Address) (*OfflineTx, error) {
+	if treasury == (common.Address{}) {
+		return nil, fmt.Errorf("treasury must not be the zero address")
+	}
+	return s.buildOffline(updateTreasuryCall(treasury))
+}
+
+func (s *RedEnvelopeService) buildOffline(call *writeCall) (*OfflineTx, error) {
+	data, err := call.calldata()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := s.Client.PendingNonceAt(context.Background(), s.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %v", err)
+	}
+
+	gasPrice, err := s.gasPrice(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return &OfflineTx{
+		ChainID:  (*hexutil.Big)(new(big.Int).Set(s.ChainID)),
+		From:     s.Address,
+		To:       s.ContractAddress,
+		Nonce:    hexutil.Uint64(nonce),
+		Gas:      hexutil.Uint64(call.gasLimit),
+		GasPrice: (*hexutil.Big)(gasPrice),
+		Value:    (*hexutil.Big)(new(big.Int).Set(call.value)),
+		Data:     data,
+		Intent: OfflineIntent{
+			Method: call.method,
+			Args:   intentArgs(s.ABI.Methods[call.method], call.args),
+		},
+	}, nil
+}
+
+// VerifyOfflineTx memastikan calldata dan value sesuai intent yang
+// dideklarasikan, dan (kalau sudah signed) raw tx sesuai field-nya
+func VerifyOfflineTx(tx *OfflineTx) error {
+	parsedABI, err := contractABI()
+	if err != nil {
+		return err
+	}
+
+	if len(tx.Data) < 4 {
+		return fmt.Errorf("calldata too short")
+	}
+	method, err := parsedABI.MethodById(tx.Data[:4])
+	if err != nil {
+		return fmt.Errorf("unknown method selector %x", tx.Data[:4])
+	}
+	if method.Name != tx.Intent.Method {
+		return fmt.Errorf("calldata calls %s but intent declares %s", method.Name, tx.Intent.Method)
+	}
+
+	args, err := method.Inputs.Unpack(tx.Data[4:])
+	if err != nil {
+		return fmt.Errorf("failed to decode calldata: %v", err)
+	}
+	decoded := intentArgs(*method, args)
+	if len(decoded) != len(tx.Intent.Args) {
+		return fmt.Errorf("intent declares %d args, calldata has %d", len(tx.Intent.Args), len(decoded))
+	}
+	for name, value := range decoded {
+		if tx.Intent.Args[name] != value {
+			return fmt.Errorf("calldata arg %s=%s does not match intent %s", name, value, tx.Intent.Args[name])
+		}
+	}
+
+	expectedValue := big.NewInt(0)
+	if method.Name == "createEnvelope" {
+		call := createEnvelopeCall(args[0].(uint8), args[1].(common.Address), args[2].(uint32), args[3].(*big.Int),
+			args[4].(uint64), args[5].([32]byte), args[6].(common.Address))
+		expectedValue = call.value
+	}
+	if tx.Value.ToInt().Cmp(expectedValue) != 0 {
+		return fmt.Errorf("value %s does not match expected %s for %s", tx.Value.ToInt(), expectedValue, method.Name)
+	}
+
+	if !tx.Signed() {
+		return nil
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(tx.RawTx); err != nil {
+		return fmt.Errorf("failed to decode raw tx: %v", err)
+	}
+	if tx.Hash != nil && signed.Hash() != *tx.Hash {
+		return fmt.Errorf("raw tx hash %s does not match declared %s", signed.Hash().Hex(), tx.Hash.Hex())
+	}
+	if signed.ChainId().Cmp(tx.ChainID.ToInt()) != 0 {
+		return fmt.Errorf("raw tx chain ID %s does not match declared %s", signed.ChainId(), tx.ChainID.ToInt())
+	}
+	return checkSignedTx(tx.unsignedTransaction(), signed, tx.From, tx.ChainID.ToInt())
+}
+
+// SignOfflineTx menandatangani OfflineTx di mesin offline. expectedChainID
+// harus diisi operator secara terpisah, supaya file yang salah network ditolak.
+func SignOfflineTx(tx *OfflineTx, signer Signer, expectedChainID *big.Int) error {
+	if tx.ChainID.ToInt().Cmp(expectedChainID) != 0 {
+		return fmt.Errorf("offline tx is for chain %s, expected %s", tx.ChainID.ToInt(), expectedChainID)
+	}
+	if tx.From != signer.Address() {
+		return fmt.Errorf("offline tx is from %s but signer is %s", tx.From.Hex(), signer.Address().Hex())
+	}
+	if tx.Signed() {
+		return fmt.Errorf("offline tx is already signed")
+	}
+	if err := VerifyOfflineTx(tx); err != nil {
+		return err
+	}
+
+	signed, err := signer.SignTx(tx.unsignedTransaction(), expectedChainID)
+	if err != nil {
+		return fmt.Errorf("failed to sign tx: %w", err)
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to encode signed tx: %v", err)
+	}
+
+	hash := signed.Hash()
+	tx.RawTx = raw
+	tx.Hash = &hash
+	return nil
+}
+
+// BroadcastOfflineTx mengirim OfflineTx yang sudah signed ke node, setelah
+// cek chain ID node, intent, dan isi raw tx
+func (s *RedEnvelopeService) BroadcastOfflineTx(tx *OfflineTx) (*types.Transaction, error) {
+	if !tx.Signed() {
+		return nil, fmt.Errorf("offline tx is not signed")
+	}
+	if tx.ChainID.ToInt().Cmp(s.ChainID) != 0 {
+		return nil, fmt.Errorf("offline tx is for chain %s but node is on chain %s", tx.ChainID.ToInt(), s.ChainID)
+	}
+	if tx.To != s.ContractAddress {
+		return nil, fmt.Errorf("offline tx targets %s, expected contract %s", tx.To.Hex(), s.ContractAddress.Hex())
+	}
+	if err := VerifyOfflineTx(tx); err != nil {
+		return nil, err
+	}
+
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(tx.RawTx); err != nil {
+		return nil, fmt.Errorf("failed to decode raw tx: %v", err)
+	}
+
+	if s.Journal != nil {
+		if err := s.Journal.Record(tx.Intent.Method, tx.Intent.Args, tx.From, signed); err != nil {
+			return nil, fmt.Errorf("failed to record journal: %v", err)
+		}
+	}
+
+	err := s.Client.SendTransaction(context.Background(), signed)
+	s.Metrics.submitted(tx.Intent.Method, err)
+	s.logSent(intentAttrs(tx.Intent.Method, tx.Intent.Args), signed, err)
+	if err != nil {
+		broadcastErr := fmt.Errorf("failed to broadcast tx: %w", err)
+		if updateErr := s.journalBroadcastFailed(signed.Hash(), err); updateErr != nil {
+			return nil, errors.Join(broadcastErr, updateErr)
+		}
+		return nil, broadcastErr
+	}
+
+	if s.Journal != nil {
+		if err := s.Journal.Update(signed.Hash(), JournalPending, 0, nil); err != nil {
+			return nil, fmt.Errorf("failed to update journal: %v", err)
+		}
+	}
+	return signed, nil
+}
@@ -0,0 +1,127 @@
+package redenvelope
+
+import (
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// testOfflineCreate membangun OfflineTx createEnvelope tanpa node
+func testOfflineCreate(t *testing.T) *OfflineTx {
+	service := newABIOnlyService(t)
+	service.ContractAddress = common.HexToAddress(testContractAddress)
+
+	expiry := uint64(time.Now().Add(time.Hour).Unix())
+	call := createEnvelopeCall(GROUP_FIXED, common.Address{}, 5, big.NewInt(1000), expiry, TestRoomIdHash, common.Address{})
+	data, err := call.calldata()
+	if err != nil {
+		t.Fatalf("Failed to pack: %v", err)
+	}
+
+	return &OfflineTx{
+		ChainID:  (*hexutil.Big)(big.NewInt(31337)),
+		From:     testAddress0,
+		To:       service.ContractAddress,
+		Nonce:    4,
+		Gas:      hexutil.Uint64(call.gasLimit),
+		GasPrice: (*hexutil.Big)(big.NewInt(1000000000)),
+		Value:    (*hexutil.Big)(call.value),
+		Data:     data,
+		Intent: OfflineIntent{
+			Method: call.method,
+			Args:   intentArgs(service.ABI.Methods[call.method], call.args),
+		},
+	}
+}
+
+func TestOfflineTx_SignRoundTrip(t *testing.T) {
+	tx := testOfflineCreate(t)
+	if tx.Value.ToInt().Int64() != 5000 {
+		t.Fatalf("Expected value 5000 (amount × totalClaims), got %s", tx.Value.ToInt())
+	}
+
+	path := filepath.Join(t.TempDir(), "create.json")
+	if err := WriteOfflineTx(path, tx); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	loaded, err := ReadOfflineTx(path)
+	if err != nil {
+		t.Fatalf("Failed to read: %v", err)
+	}
+
+	if err := SignOfflineTx(loaded, testAccount(t, 0), big.NewInt(31337)); err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	if !loaded.Signed() || loaded.Hash == nil {
+		t.Fatal("Expected signed tx with hash")
+	}
+	if err := VerifyOfflineTx(loaded); err != nil {
+		t.Fatalf("Signed tx failed verification: %v", err)
+	}
+}
+
+func TestOfflineTx_RejectsMismatches(t *testing.T) {
+	signer := testAccount(t, 0)
+
+	tx := testOfflineCreate(t)
+	if err := SignOfflineTx(tx, signer, big.NewInt(1)); err == nil {
+		t.Error("Expected chain ID mismatch to be rejected")
+	}
+
+	tx = testOfflineCreate(t)
+	if err := SignOfflineTx(tx, testAccount(t, 1), big.NewInt(31337)); err == nil {
+		t.Error("Expected signer/from mismatch to be rejected")
+	}
+
+	tx = testOfflineCreate(t)
+	tx.Intent.Args["totalClaims"] = "50"
+	if err := VerifyOfflineTx(tx); err == nil {
+		t.Error("Expected intent mismatch to be rejected")
+	}
+
+	tx = testOfflineCreate(t)
+	tx.Value = (*hexutil.Big)(big.NewInt(1))
+	if err := VerifyOfflineTx(tx); err == nil {
+		t.Error("Expected wrong value to be rejected")
+	}
+
+	tx = testOfflineCreate(t)
+	if err := SignOfflineTx(tx, signer, big.NewInt(31337)); err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	tx.Nonce++
+	if err := VerifyOfflineTx(tx); err == nil {
+		t.Error("Expected raw tx / declared nonce mismatch to be rejected")
+	}
+}
+
+func TestBroadcastOfflineTx_FailureUpdatesJournal(t *testing.T) {
+	api := &fakeEthAPI{sendErr: errors.New("replacement transaction underpriced")}
+	service := newFakeSenderService(t, api)
+	journal, err := OpenJournal(filepath.Join(t.TempDir(), "journal.json"))
+	if err != nil {
+		t.Fatalf("Failed to open journal: %v", err)
+	}
+	service.Journal = journal
+
+	tx := testOfflineCreate(t)
+	if err := SignOfflineTx(tx, testAccount(t, 0), big.NewInt(31337)); err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	if _, err := service.BroadcastOfflineTx(tx); err == nil || !rejectedByNode(err) {
+		t.Fatalf("Expected rejected broadcast, got %v", err)
+	}
+
+	entry, ok := journal.Get(*tx.Hash)
+	if !ok {
+		t.Fatal("Offline tx was not recorded in the journal")
+	}
+	if entry.Status != JournalDropped || entry.LastError != api.sendErr.Error() {
+		t.Errorf("Expected dropped entry with broadcast error, got status=%s lastError=%q", entry.Status, entry.LastError)
+	}
+}
@@ -0,0 +1,313 @@
+package redenvelope
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Schema JSON untuk envelope, transaksi, receipt, event dan error. Dipakai
+// CLI (--output json) dan API lain, jadi field hanya boleh ditambah, tidak
+// diubah. Amount wei selalu string desimal supaya aman dibaca JavaScript.
+
+// EnvelopeJSON envelope dalam schema JSON
+type EnvelopeJSON struct {
+	ID              string         `json:"id"`
+	Creator         common.Address `json:"creator"`
+	Token           common.Address `json:"token"` // Zero address untuk native token
+	Kind            string         `json:"kind"`
+	AmountPerClaim  string         `json:"amountPerClaim"`
+	RemainingAmount string         `json:"remainingAmount"`
+	TotalClaims     uint32         `json:"totalClaims"`
+	RemainingClaims uint32         `json:"remainingClaims"`
+	ClaimIndex      uint32         `json:"claimIndex"`
+	Expiry          uint64         `json:"expiry"` // Unix detik
+	RoomIdHash      common.Hash    `json:"roomIdHash"`
+	Recipient       common.Address `json:"recipient"`
+}
+
+// IndexedEnvelopeJSON envelope dari Indexer, termasuk status dan total klaim
+type IndexedEnvelopeJSON struct {
+	ID            string         `json:"id"`
+	Status        string         `json:"status"`
+	Creator       common.Address `json:"creator"`
+	Kind          string         `json:"kind"`
+	Token         common.Address `json:"token"`
+	NetPot        string         `json:"netPot"`
+	FeeAmount     string         `json:"feeAmount"`
+	TotalClaims   uint32         `json:"totalClaims"`
+	ClaimCount    uint32         `json:"claimCount"`
+	ClaimedAmount string         `json:"claimedAmount"`
+	Expiry        uint64         `json:"expiry"`
+	RoomIdHash    common.Hash    `json:"roomIdHash"`
+	Recipient     common.Address `json:"recipient"`
+	RefundAmount  string         `json:"refundAmount,omitempty"`
+	CreatedBlock  uint64         `json:"createdBlock"`
+	CreatedTx     common.Hash    `json:"createdTx"`
+}
+
+// TxJSON transaksi yang sudah ditandatangani
+type TxJSON struct {
+	Hash     common.Hash     `json:"hash"`
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Nonce    uint64          `json:"nonce"`
+	Value    string          `json:"value"`
+	Gas      uint64          `json:"gas"`
+	GasPrice string          `json:"gasPrice"`
+	ChainID  string          `json:"chainId"`
+	Input    hexutil.Bytes   `json:"input"`
+}
+
+// ReceiptJSON receipt beserta event RedEnvelope di dalamnya
+type ReceiptJSON struct {
+	TxHash            common.Hash     `json:"txHash"`
+	Status            string          `json:"status"` // TxStatusSuccess atau TxStatusReverted
+	BlockNumber       uint64          `json:"blockNumber"`
+	BlockHash         common.Hash     `json:"blockHash"`
+	GasUsed           uint64          `json:"gasUsed"`
+	EffectiveGasPrice string          `json:"effectiveGasPrice"`
+	ContractAddress   *common.Address `json:"contractAddress,omitempty"`
+	Events            []EventJSON     `json:"events"`
+}
+
+// EventJSON event RedEnvelope. Tepat satu dari created, claimed atau
+// refunded terisi sesuai name.
+type EventJSON struct {
+	Name        string      `json:"name"`
+	EnvelopeID  string      `json:"envelopeId"`
+	BlockNumber uint64      `json:"blockNumber"`
+	BlockHash   common.Hash `json:"blockHash"`
+	TxHash      common.Hash `json:"txHash"`
+	LogIndex    uint        `json:"logIndex"`
+
+	Created  *CreatedJSON  `json:"created,omitempty"`
+	Claimed  *ClaimedJSON  `json:"claimed,omitempty"`
+	Refunded *RefundedJSON `json:"refunded,omitempty"`
+}
+
+// CreatedJSON isi event EnvelopeCreated
+type CreatedJSON struct {
+	Creator     common.Address `json:"creator"`
+	Kind        string         `json:"kind"`
+	Token       common.Address `json:"token"`
+	NetPot      string         `json:"netPot"`
+	TotalClaims uint32         `json:"totalClaims"`
+	Expiry      uint64         `json:"expiry"`
+	FeeAmount   string         `json:"feeAmount"`
+	RoomIdHash  common.Hash    `json:"roomIdHash"`
+	Recipient   common.Address `json:"recipient"`
+}
+
+// ClaimedJSON isi event EnvelopeClaimed
+type ClaimedJSON struct {
+	Claimer    common.Address `json:"claimer"`
+	Payout     string         `json:"payout"`
+	ClaimIndex uint32         `json:"claimIndex"`
+}
+
+// RefundedJSON isi event EnvelopeRefunded
+type RefundedJSON struct {
+	RefundAmount string `json:"refundAmount"`
+}
+
+// FeeQuoteJSON FeeQuote dalam schema JSON
+type FeeQuoteJSON struct {
+	FeeBps   uint16 `json:"feeBps"`
+	GrossPot string `json:"grossPot"`
+	Fee      string `json:"fee"`
+	NetPot   string `json:"netPot"`
+}
+
+// ErrorJSON error dengan code yang stabil, lihat ErrorCode
+type ErrorJSON struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Code ErrorJSON untuk error yang bukan dari contract
+const (
+	ErrorCodeReverted             = "Reverted" // require(string), panic, atau custom error yang tidak dikenal
+	ErrorCodeInsufficientFunds    = "InsufficientFunds"
+	ErrorCodeChainIDMismatch      = "ChainIDMismatch"
+	ErrorCodeGasPriceTooHigh      = "GasPriceTooHigh"
+	ErrorCodeIncompatibleContract = "IncompatibleContract"
+	ErrorCodeInvalidArgument      = "InvalidArgument"     // Input dari caller (flag / request) tidak valid
+	ErrorCodeNotFound             = "NotFound"            // Resource server (webhook, delivery) tidak ada
+	ErrorCodeUnauthenticated      = "Unauthenticated"     // Butuh session login yang valid
+	ErrorCodeForbidden            = "Forbidden"           // Address session tidak punya izin
+	ErrorCodeTooManyRequests      = "TooManyRequests"     // Batas resource server (mis. nonce login) tercapai
+	ErrorCodeSubscriptionDropped  = "SubscriptionDropped" // Subscriber event tertinggal dan diputus
+	ErrorCodeInternal             = "Internal"
+)
+
+// NewEnvelopeJSON envelope dengan ID-nya
+func NewEnvelopeJSON(id *big.Int, envelope *Envelope) *EnvelopeJSON {
+	return &EnvelopeJSON{
+		ID:              id.String(),
+		Creator:         envelope.Creator,
+		Token:           envelope.Token,
+		Kind:            KindName(envelope.Kind),
+		AmountPerClaim:  weiString(envelope.AmountPerClaim),
+		RemainingAmount: weiString(envelope.RemainingAmount),
+		TotalClaims:     envelope.TotalClaims,
+		RemainingClaims: envelope.RemainingClaims,
+		ClaimIndex:      envelope.ClaimIndex,
+		Expiry:          envelope.Expiry,
+		RoomIdHash:      envelope.RoomIdHash,
+		Recipient:       envelope.Recipient,
+	}
+}
+
+// NewIndexedEnvelopeJSON envelope dari index dengan status pada waktu now
+func NewIndexedEnvelopeJSON(envelope IndexedEnvelope, now time.Time) *IndexedEnvelopeJSON {
+	out := &IndexedEnvelopeJSON{
+		ID:            envelope.ID.String(),
+		Status:        envelope.Status(now),
+		Creator:       envelope.Creator,
+		Kind:          KindName(envelope.Kind),
+		Token:         envelope.Token,
+		NetPot:        weiString(envelope.NetPot),
+		FeeAmount:     weiString(envelope.FeeAmount),
+		TotalClaims:   envelope.TotalClaims,
+		ClaimCount:    envelope.ClaimCount,
+		ClaimedAmount: weiString(envelope.ClaimedAmount),
+		Expiry:        envelope.Expiry,
+		RoomIdHash:    envelope.RoomIdHash,
+		Recipient:     envelope.Recipient,
+		CreatedBlock:  envelope.CreatedBlock,
+		CreatedTx:     envelope.CreatedTx,
+	}
+	if envelope.Refunded {
+		out.RefundAmount = weiString(envelope.RefundAmount)
+	}
+	return out
+}
+
+// NewTxJSON transaksi dengan sender from
+func NewTxJSON(tx *types.Transaction, from common.Address) *TxJSON {
+	return &TxJSON{
+		Hash:     tx.Hash(),
+		From:     from,
+		To:       tx.To(),
+		Nonce:    tx.Nonce(),
+		Value:    weiString(tx.Value()),
+		Gas:      tx.Gas(),
+		GasPrice: weiString(tx.GasPrice()),
+		ChainID:  weiString(tx.ChainId()),
+		Input:    tx.Data(),
+	}
+}
+
+// NewReceiptJSON receipt dengan event RedEnvelope yang sudah di-decode
+func NewReceiptJSON(receipt *types.Receipt, events []EnvelopeEvent) *ReceiptJSON {
+	out := &ReceiptJSON{
+		TxHash:            receipt.TxHash,
+		Status:            TxStatusSuccess,
+		BlockNumber:       receipt.BlockNumber.Uint64(),
+		BlockHash:         receipt.BlockHash,
+		GasUsed:           receipt.GasUsed,
+		EffectiveGasPrice: weiString(receipt.EffectiveGasPrice),
+		Events:            make([]EventJSON, 0, len(events)),
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		out.Status = TxStatusReverted
+	}
+	if receipt.ContractAddress != (common.Address{}) {
+		address := receipt.ContractAddress
+		out.ContractAddress = &address
+	}
+	for _, event := range events {
+		out.Events = append(out.Events, *NewEventJSON(event))
+	}
+	return out
+}
+
+// NewEventJSON event dalam schema JSON
+func NewEventJSON(event EnvelopeEvent) *EventJSON {
+	out := &EventJSON{
+		Name:        event.Name,
+		EnvelopeID:  weiString(event.EnvelopeID),
+		BlockNumber: event.BlockNumber,
+		BlockHash:   event.BlockHash,
+		TxHash:      event.TxHash,
+		LogIndex:    event.LogIndex,
+	}
+	switch {
+	case event.Created != nil:
+		out.Created = &CreatedJSON{
+			Creator:     event.Created.Creator,
+			Kind:        KindName(event.Created.Kind),
+			Token:       event.Created.Token,
+			NetPot:      weiString(event.Created.NetPot),
+			TotalClaims: event.Created.TotalClaims,
+			Expiry:      event.Created.Expiry,
+			FeeAmount:   weiString(event.Created.FeeAmount),
+			RoomIdHash:  event.Created.RoomIdHash,
+			Recipient:   event.Created.Recipient,
+		}
+	case event.Claimed != nil:
+		out.Claimed = &ClaimedJSON{
+			Claimer:    event.Claimed.Claimer,
+			Payout:     weiString(event.Claimed.Payout),
+			ClaimIndex: event.Claimed.ClaimIndex,
+		}
+	case event.Refunded != nil:
+		out.Refunded = &RefundedJSON{RefundAmount: weiString(event.Refunded.RefundAmount)}
+	}
+	return out
+}
+
+// NewFeeQuoteJSON FeeQuote dalam schema JSON
+func NewFeeQuoteJSON(quote *FeeQuote) *FeeQuoteJSON {
+	return &FeeQuoteJSON{
+		FeeBps:   quote.FeeBps,
+		GrossPot: weiString(quote.GrossPot),
+		Fee:      weiString(quote.Fee),
+		NetPot:   weiString(quote.NetPot),
+	}
+}
+
+// NewErrorJSON error dengan code dari ErrorCode dan pesan dari ExplainError
+func NewErrorJSON(err error) *ErrorJSON {
+	return &ErrorJSON{Code: ErrorCode(err), Message: ExplainError(err)}
+}
+
+// ErrorCode code stabil untuk error: nama custom error contract
+// (AlreadyClaimed, EnvelopeExpired, ...) atau salah satu ErrorCode*
+func ErrorCode(err error) string {
+	var contractErr *ContractError
+	if errors.As(err, &contractErr) {
+		if _, known := contractErrors[contractErr.Name]; known {
+			return contractErr.Name
+		}
+		return ErrorCodeReverted
+	}
+
+	var fundsErr *InsufficientFundsError
+	var chainErr *ChainIDMismatchError
+	var compatErr *IncompatibleContractError
+	switch {
+	case errors.As(err, &fundsErr):
+		return ErrorCodeInsufficientFunds
+	case errors.As(err, &chainErr):
+		return ErrorCodeChainIDMismatch
+	case errors.Is(err, ErrGasPriceTooHigh):
+		return ErrorCodeGasPriceTooHigh
+	case errors.As(err, &compatErr):
+		return ErrorCodeIncompatibleContract
+	}
+	return ErrorCodeInternal
+}
+
+// weiString *big.Int sebagai string desimal, nil menjadi "0"
+func weiString(value *big.Int) string {
+	if value == nil {
+		return "0"
+	}
+	return value.String()
+}
@@ -0,0 +1,67 @@
+package redenvelope
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestEventJSON_WeiAsStrings(t *testing.T) {
+	payout, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	event := EnvelopeEvent{
+		Name:        EventEnvelopeClaimed,
+		EnvelopeID:  big.NewInt(4),
+		BlockNumber: 10,
+		LogIndex:    1,
+		Claimed:     &EnvelopeClaimedEvent{EnvelopeId: big.NewInt(4), Claimer: testAddress0, Payout: payout, ClaimIndex: 2},
+	}
+
+	data, err := json.Marshal(NewEventJSON(event))
+	if err != nil {
+		t.Fatalf("Failed to marshal event: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal event: %v", err)
+	}
+	if decoded["name"] != EventEnvelopeClaimed || decoded["envelopeId"] != "4" {
+		t.Errorf("Unexpected event JSON %s", data)
+	}
+	claimed, ok := decoded["claimed"].(map[string]interface{})
+	if !ok || claimed["payout"] != payout.String() || claimed["claimer"] != "0x"+common.Bytes2Hex(testAddress0[:]) {
+		t.Errorf("Unexpected claimed JSON %s", data)
+	}
+	if _, ok := decoded["created"]; ok {
+		t.Errorf("Expected created to be omitted: %s", data)
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		code string
+	}{
+		{fmt.Errorf("failed to claim envelope: %w", ErrAlreadyClaimed), "AlreadyClaimed"},
+		{fmt.Errorf("tx reverted: %w", ErrEnvelopeExpired), "EnvelopeExpired"},
+		{&ContractError{Name: "Error", Reason: "boom"}, ErrorCodeReverted},
+		{&InsufficientFundsError{Asset: NativeAsset, Required: big.NewInt(2), Available: big.NewInt(1), Missing: big.NewInt(1)}, ErrorCodeInsufficientFunds},
+		{&ChainIDMismatchError{Expected: 1, Actual: big.NewInt(5)}, ErrorCodeChainIDMismatch},
+		{fmt.Errorf("%w: too much", ErrGasPriceTooHigh), ErrorCodeGasPriceTooHigh},
+		{errors.New("connection refused"), ErrorCodeInternal},
+	}
+	for _, tt := range tests {
+		if got := ErrorCode(tt.err); got != tt.code {
+			t.Errorf("ErrorCode(%v) = %s, want %s", tt.err, got, tt.code)
+		}
+	}
+
+	out := NewErrorJSON(ErrAlreadyClaimed)
+	if out.Code != "AlreadyClaimed" || out.Message == "" {
+		t.Errorf("Unexpected error JSON %+v", out)
+	}
+}
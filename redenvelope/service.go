@@ -2,28 +2,59 @@ package redenvelope
 
 import (
 	"context"
-	"crypto/ecdsa"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math/big"
-	"reflect"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/v2"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 type RedEnvelopeService struct {
 	Client          *ethclient.Client
 	ContractAddress common.Address
-	PrivateKey      *ecdsa.PrivateKey
+	Signer          Signer
 	Address         common.Address
 	ChainID         *big.Int
 	ABI             abi.ABI
+	Journal         *Journal
+	Idempotency     *IdempotencyStore
+	SimulateWrites  bool
+	Artifact        *Artifact
+	Network         *NetworkConfig
+	GasPolicy       GasPolicy
+	Confirmations   uint64
+	Metrics         *Metrics     // nil kalau tanpa WithMetrics
+	Logger          *slog.Logger // nil kalau tanpa WithLogger
+
+	// transport HTTP ke node, diukur dan dicatat setelah WithMetrics /
+	// WithLogger
+	transport *rpcTransport
+}
+
+// Option konfigurasi tambahan untuk RedEnvelopeService, dijalankan setelah
+// koneksi ke node dan ABI siap
+type Option func(*RedEnvelopeService) error
+
+// WithJournal mencatat semua transaksi write ke journal dan langsung
+// me-reconcile entry yang belum final dari proses sebelumnya
+func WithJournal(journal *Journal) Option {
+	return func(s *RedEnvelopeService) error {
+		s.Journal = journal
+		if err := s.ReconcileJournal(context.Background()); err != nil {
+			return fmt.Errorf("failed to reconcile journal: %v", err)
+		}
+		return nil
+	}
 }
 
 // Envelope struct sesuai dengan contract
@@ -42,44 +73,73 @@ type Envelope struct {
 }
 
 // NewRedEnvelopeService membuat instance baru RedEnvelope service
-func NewRedEnvelopeService(rpcURL string, contractAddress string, privateKeyHex string) (*RedEnvelopeService, error) {
-	client, err := ethclient.Dial(rpcURL)
+func NewRedEnvelopeService(rpcURL string, contractAddress string, privateKeyHex string, opts ...Option) (*RedEnvelopeService, error) {
+	signer, err := NewKeySigner(privateKeyHex)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to ethereum node: %v", err)
+		return nil, err
 	}
 
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	return NewRedEnvelopeServiceWithSigner(rpcURL, contractAddress, signer, opts...)
+}
+
+// NewRedEnvelopeServiceWithSigner membuat instance RedEnvelope service
+// dengan Signer apa saja (key di memory, keystore, external signer, ...)
+func NewRedEnvelopeServiceWithSigner(rpcURL string, contractAddress string, signer Signer, opts ...Option) (*RedEnvelopeService, error) {
+	service, err := dialService(rpcURL, common.HexToAddress(contractAddress), signer)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load private key: %v", err)
+		return nil, err
+	}
+
+	if err := service.apply(opts); err != nil {
+		return nil, err
 	}
 
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return nil, fmt.Errorf("failed to cast public key to ECDSA")
+	return service, nil
+}
+
+// dialService koneksi ke node dan parse ABI, tanpa menjalankan Option
+func dialService(rpcURL string, contractAddress common.Address, signer Signer) (*RedEnvelopeService, error) {
+	transport := &rpcTransport{base: http.DefaultTransport}
+	rpcClient, err := rpc.DialOptions(context.Background(), rpcURL, rpc.WithHTTPClient(&http.Client{Transport: transport}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ethereum node: %v", err)
 	}
-	address := crypto.PubkeyToAddress(*publicKeyECDSA)
+	client := ethclient.NewClient(rpcClient)
 
 	chainID, err := client.ChainID(context.Background())
 	if err != nil {
+		client.Close()
 		return nil, fmt.Errorf("failed to get chain ID: %v", err)
 	}
 
 	parsedABI, err := abi.JSON(strings.NewReader(RedEnvelopeABI))
 	if err != nil {
+		client.Close()
 		return nil, fmt.Errorf("failed to parse ABI: %v", err)
 	}
 
 	return &RedEnvelopeService{
 		Client:          client,
-		ContractAddress: common.HexToAddress(contractAddress),
-		PrivateKey:      privateKey,
-		Address:         address,
+		ContractAddress: contractAddress,
+		Signer:          signer,
+		Address:         signer.Address(),
 		ChainID:         chainID,
 		ABI:             parsedABI,
+		transport:       transport,
 	}, nil
 }
 
+// apply menjalankan Option berurutan, client ditutup kalau ada yang gagal
+func (s *RedEnvelopeService) apply(opts []Option) error {
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			s.Client.Close()
+			return err
+		}
+	}
+	return nil
+}
+
 // CreateEnvelope membuat envelope baru
 // Parameter amount:
 //   - DIRECT_FIXED: amount untuk penerima
@@ -101,183 +161,333 @@ func (s *RedEnvelopeService) CreateEnvelope(
 	roomIdHash [32]byte,
 	recipient common.Address,
 ) (*types.Transaction, error) {
+	if err := checkAmount(amount); err != nil {
+		return nil, err
+	}
 	expiry := uint64(time.Now().Add(expiryDuration).Unix())
 
-	nonce, err := s.Client.PendingNonceAt(context.Background(), s.Address)
+	tx, err := s.sendTransaction(createEnvelopeCall(kind, token, totalClaims, amount, expiry, roomIdHash, recipient))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get nonce: %v", err)
+		return nil, fmt.Errorf("failed to create envelope: %w", err)
 	}
 
-	gasPrice, err := s.Client.SuggestGasPrice(context.Background())
+	return tx, nil
+}
+
+// createEnvelopeCall menyiapkan writeCall createEnvelope termasuk msg.value
+func createEnvelopeCall(
+	kind uint8,
+	token common.Address,
+	totalClaims uint32,
+	amount *big.Int,
+	expiry uint64,
+	roomIdHash [32]byte,
+	recipient common.Address,
+) *writeCall {
+	// grossPot = amount yang harus dikirim sebagai msg.value (lihat GrossPot)
+	grossPot := GrossPot(kind, totalClaims, amount)
+
+	call := &writeCall{
+		method:   "createEnvelope",
+		value:    big.NewInt(0),
+		gasLimit: 500000,
+		args:     []interface{}{kind, token, totalClaims, amount, expiry, roomIdHash, recipient},
+		pack: func() ([]byte, error) {
+			return redEnvelope.TryPackCreateEnvelope(kind, token, totalClaims, amount, expiry, roomIdHash, recipient)
+		},
+	}
+	if token == (common.Address{}) {
+		call.value = grossPot
+	} else {
+		call.token = token
+		call.tokenAmount = grossPot
+	}
+	return call
+}
+
+// ClaimEnvelope klaim envelope
+func (s *RedEnvelopeService) ClaimEnvelope(envelopeId *big.Int) (*types.Transaction, error) {
+	tx, err := s.sendTransaction(claimEnvelopeCall(envelopeId))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get gas price: %v", err)
+		return nil, fmt.Errorf("failed to claim envelope: %w", err)
+	}
+
+	return tx, nil
+}
+
+// GetEnvelope mendapatkan informasi envelope
+func (s *RedEnvelopeService) GetEnvelope(envelopeId *big.Int) (*Envelope, error) {
+	if envelopeId == nil {
+		return nil, fmt.Errorf("envelopeId cannot be nil")
 	}
 
-	auth, err := bind.NewKeyedTransactorWithChainID(s.PrivateKey, s.ChainID)
+	data, err := redEnvelope.TryPackGetEnvelope(envelopeId)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create transactor: %v", err)
+		return nil, fmt.Errorf("failed to pack getEnvelope: %v", err)
+	}
+	envelope, err := callContract(s, data, redEnvelope.UnpackGetEnvelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get envelope: %w", err)
 	}
-	auth.Nonce = big.NewInt(int64(nonce))
-	auth.GasLimit = uint64(500000)
-	auth.GasPrice = gasPrice
 
-	// Calculate grossPot (amount yang harus dikirim sebagai msg.value)
-	var grossPot *big.Int
-	if kind == GROUP_FIXED {
-		// GROUP_FIXED: grossPot = amount × totalClaims
-		grossPot = new(big.Int).Mul(amount, big.NewInt(int64(totalClaims)))
-	} else {
-		// DIRECT_FIXED & GROUP_RANDOM: grossPot = amount
-		grossPot = amount
+	return &Envelope{
+		Creator:         envelope.Creator,
+		Token:           envelope.Token,
+		Kind:            envelope.Kind,
+		AmountPerClaim:  envelope.AmountPerClaim,
+		RemainingAmount: envelope.RemainingAmount,
+		TotalClaims:     envelope.TotalClaims,
+		RemainingClaims: envelope.RemainingClaims,
+		ClaimIndex:      envelope.ClaimIndex,
+		Expiry:          envelope.Expiry,
+		RoomIdHash:      envelope.RoomIdHash,
+		Recipient:       envelope.Recipient,
+	}, nil
+}
+
+// HasClaimed cek apakah user sudah klaim
+func (s *RedEnvelopeService) HasClaimed(envelopeId *big.Int, user common.Address) (bool, error) {
+	if envelopeId == nil {
+		return false, fmt.Errorf("envelopeId cannot be nil")
 	}
 
-	if token == (common.Address{}) {
-		auth.Value = grossPot
-	} else {
-		auth.Value = big.NewInt(0)
+	data, err := redEnvelope.TryPackHasUserClaimed(envelopeId, user)
+	if err != nil {
+		return false, fmt.Errorf("failed to pack hasUserClaimed: %v", err)
+	}
+	claimed, err := callContract(s, data, redEnvelope.UnpackHasUserClaimed)
+	if err != nil {
+		return false, fmt.Errorf("failed to check claim status: %w", err)
 	}
 
-	boundContract := bind.NewBoundContract(s.ContractAddress, s.ABI, s.Client, s.Client, s.Client)
+	return claimed, nil
+}
 
-	tx, err := boundContract.Transact(auth, "createEnvelope", kind, token, totalClaims, amount, expiry, roomIdHash, recipient)
+// RefundEnvelope refund envelope setelah expiry
+func (s *RedEnvelopeService) RefundEnvelope(envelopeId *big.Int) (*types.Transaction, error) {
+	tx, err := s.sendTransaction(refundEnvelopeCall(envelopeId))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create envelope: %v", err)
+		return nil, fmt.Errorf("failed to refund envelope: %w", err)
 	}
 
 	return tx, nil
 }
 
-// ClaimEnvelope klaim envelope
-func (s *RedEnvelopeService) ClaimEnvelope(envelopeId *big.Int) (*types.Transaction, error) {
-	nonce, err := s.Client.PendingNonceAt(context.Background(), s.Address)
+// UpdateFeeBps mengubah fee contract. Hanya owner contract yang bisa;
+// feeBps maksimum MaxFeeBps.
+func (s *RedEnvelopeService) UpdateFeeBps(feeBps uint16) (*types.Transaction, error) {
+	if feeBps > MaxFeeBps {
+		return nil, fmt.Errorf("feeBps %d exceeds %d", feeBps, MaxFeeBps)
+	}
+	tx, err := s.sendTransaction(updateFeeBpsCall(feeBps))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get nonce: %v", err)
+		return nil, fmt.Errorf("failed to update fee: %w", err)
 	}
 
-	gasPrice, err := s.Client.SuggestGasPrice(context.Background())
+	return tx, nil
+}
+
+// UpdateTreasury mengubah address penerima fee. Hanya owner contract yang bisa.
+func (s *RedEnvelopeService) UpdateTreasury(treasury common.Address) (*types.Transaction, error) {
+	if treasury == (common.Address{}) {
+		return nil, fmt.Errorf("treasury must not be the zero address")
+	}
+	tx, err := s.sendTransaction(updateTreasuryCall(treasury))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get gas price: %v", err)
+		return nil, fmt.Errorf("failed to update treasury: %w", err)
 	}
 
-	auth, err := bind.NewKeyedTransactorWithChainID(s.PrivateKey, s.ChainID)
+	return tx, nil
+}
+
+// GetNextEnvelopeId mendapatkan next envelope ID
+func (s *RedEnvelopeService) GetNextEnvelopeId() (*big.Int, error) {
+	data, err := redEnvelope.TryPackNextEnvelopeId()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create transactor: %v", err)
+		return nil, fmt.Errorf("failed to pack nextEnvelopeId: %v", err)
+	}
+	nextId, err := callContract(s, data, redEnvelope.UnpackNextEnvelopeId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next envelope ID: %w", err)
 	}
-	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = big.NewInt(0)
-	auth.GasLimit = uint64(300000)
-	auth.GasPrice = gasPrice
 
-	boundContract := bind.NewBoundContract(s.ContractAddress, s.ABI, s.Client, s.Client, s.Client)
+	return nextId, nil
+}
 
-	tx, err := boundContract.Transact(auth, "claimEnvelope", envelopeId)
+// GetFeeBps fee dalam basis point yang diambil dari setiap envelope
+func (s *RedEnvelopeService) GetFeeBps() (uint16, error) {
+	data, err := redEnvelope.TryPackFeeBps()
+	if err != nil {
+		return 0, fmt.Errorf("failed to pack feeBps: %v", err)
+	}
+	feeBps, err := callContract(s, data, redEnvelope.UnpackFeeBps)
 	if err != nil {
-		return nil, fmt.Errorf("failed to claim envelope: %v", err)
+		return 0, fmt.Errorf("failed to get fee: %w", err)
 	}
 
-	return tx, nil
+	return feeBps, nil
 }
 
-// GetEnvelope mendapatkan informasi envelope
-func (s *RedEnvelopeService) GetEnvelope(envelopeId *big.Int) (*Envelope, error) {
-	boundContract := bind.NewBoundContract(s.ContractAddress, s.ABI, s.Client, s.Client, s.Client)
-
-	var result []interface{}
-	err := boundContract.Call(&bind.CallOpts{}, &result, "getEnvelope", envelopeId)
+// GetOwner owner contract
+func (s *RedEnvelopeService) GetOwner() (common.Address, error) {
+	data, err := redEnvelope.TryPackOwner()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get envelope: %v", err)
+		return common.Address{}, fmt.Errorf("failed to pack owner: %v", err)
+	}
+	owner, err := callContract(s, data, redEnvelope.UnpackOwner)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to get owner: %w", err)
 	}
 
-	// Gunakan reflection untuk handle struct dengan atau tanpa JSON tags
-	envelopeData := result[0]
-
-	envelope := &Envelope{}
+	return owner, nil
+}
 
-	// Extract data menggunakan reflection
-	val := reflect.ValueOf(envelopeData)
+// GetTreasury address penerima fee
+func (s *RedEnvelopeService) GetTreasury() (common.Address, error) {
+	data, err := redEnvelope.TryPackTreasury()
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to pack treasury: %v", err)
+	}
+	treasury, err := callContract(s, data, redEnvelope.UnpackTreasury)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to get treasury: %w", err)
+	}
 
-	envelope.Creator = val.Field(0).Interface().(common.Address)
-	envelope.Token = val.Field(1).Interface().(common.Address)
-	envelope.Kind = val.Field(2).Interface().(uint8)
-	envelope.AmountPerClaim = val.Field(3).Interface().(*big.Int)
-	envelope.RemainingAmount = val.Field(4).Interface().(*big.Int)
-	envelope.TotalClaims = val.Field(5).Interface().(uint32)
-	envelope.RemainingClaims = val.Field(6).Interface().(uint32)
-	envelope.ClaimIndex = val.Field(7).Interface().(uint32)
-	envelope.Expiry = val.Field(8).Interface().(uint64)
-	envelope.RoomIdHash = val.Field(9).Interface().([32]byte)
-	envelope.Recipient = val.Field(10).Interface().(common.Address)
+	return treasury, nil
+}
 
-	return envelope, nil
+// rejectedByNode true kalau node menjawab broadcast dengan error JSON-RPC
+// (tx pasti tidak masuk mempool), bukan error transport
+func rejectedByNode(err error) bool {
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr)
 }
 
-// HasClaimed cek apakah user sudah klaim
-func (s *RedEnvelopeService) HasClaimed(envelopeId *big.Int, user common.Address) (bool, error) {
-	if envelopeId == nil {
-		return false, fmt.Errorf("envelopeId cannot be nil")
+// journalBroadcastFailed mencatat broadcast yang gagal ke journal (kalau
+// ada). Node menolak tx secara eksplisit -> dropped. Error transport
+// dibiarkan signed supaya di-reconcile ulang nanti.
+func (s *RedEnvelopeService) journalBroadcastFailed(hash common.Hash, err error) error {
+	if s.Journal == nil {
+		return nil
+	}
+	status := JournalSigned
+	if rejectedByNode(err) {
+		status = JournalDropped
+	}
+	if updateErr := s.Journal.Update(hash, status, 0, err); updateErr != nil {
+		return fmt.Errorf("failed to update journal: %v", updateErr)
 	}
+	return nil
+}
+
+// writeCall satu pemanggilan method write ke contract
+type writeCall struct {
+	method   string
+	value    *big.Int
+	gasLimit uint64
+	args     []interface{} // Argumen untuk journal / intent, calldata dari pack
+
+	// pack membangun calldata lewat binding yang typed
+	pack func() ([]byte, error)
+
+	// token & tokenAmount diisi kalau transaksi menarik ERC-20 dari sender
+	token       common.Address
+	tokenAmount *big.Int
 
-	boundContract := bind.NewBoundContract(s.ContractAddress, s.ABI, s.Client, s.Client, s.Client)
+	// onSigned dipanggil setelah tx ditandatangani, sebelum broadcast
+	onSigned func(tx *types.Transaction) error
+}
 
-	var result []interface{}
-	err := boundContract.Call(&bind.CallOpts{}, &result, "hasUserClaimed", envelopeId, user)
+// calldata hasil pack binding untuk call
+func (call *writeCall) calldata() ([]byte, error) {
+	data, err := call.pack()
 	if err != nil {
-		return false, fmt.Errorf("failed to check claim status: %v", err)
+		return nil, fmt.Errorf("failed to pack %s: %v", call.method, err)
 	}
+	return data, nil
+}
 
-	if len(result) == 0 {
-		return false, fmt.Errorf("no result returned from contract")
+// sendTransaction menandatangani transaksi write, mencatatnya ke journal
+// (kalau ada), lalu baru broadcast ke node
+func (s *RedEnvelopeService) sendTransaction(call *writeCall) (*types.Transaction, error) {
+	if s.SimulateWrites {
+		if _, err := s.simulate(call); err != nil {
+			s.Metrics.reverted(call.method, err)
+			s.log().Warn("simulation reverted", append(s.callAttrs(call), slog.Any("error", err))...)
+			return nil, fmt.Errorf("simulation failed: %w", err)
+		}
 	}
 
-	return result[0].(bool), nil
-}
+	data, err := call.calldata()
+	if err != nil {
+		return nil, err
+	}
 
-// RefundEnvelope refund envelope setelah expiry
-func (s *RedEnvelopeService) RefundEnvelope(envelopeId *big.Int) (*types.Transaction, error) {
 	nonce, err := s.Client.PendingNonceAt(context.Background(), s.Address)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get nonce: %v", err)
 	}
 
-	gasPrice, err := s.Client.SuggestGasPrice(context.Background())
+	gasPrice, err := s.gasPrice(context.Background())
 	if err != nil {
-		return nil, fmt.Errorf("failed to get gas price: %v", err)
+		return nil, err
 	}
 
-	auth, err := bind.NewKeyedTransactorWithChainID(s.PrivateKey, s.ChainID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create transactor: %v", err)
+	// Legacy tx: gasPrice adalah maxFeePerGas-nya
+	if err := s.checkNativeBalance(call.value, call.gasLimit, gasPrice); err != nil {
+		return nil, err
 	}
+	if call.tokenAmount != nil {
+		if err := s.checkTokenBalance(call.token, call.tokenAmount); err != nil {
+			return nil, err
+		}
+	}
+
+	auth := s.transactOpts()
 	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = big.NewInt(0)
-	auth.GasLimit = uint64(300000)
+	auth.Value = call.value
+	auth.GasLimit = call.gasLimit
 	auth.GasPrice = gasPrice
+	auth.NoSend = true
 
-	boundContract := bind.NewBoundContract(s.ContractAddress, s.ABI, s.Client, s.Client, s.Client)
-
-	tx, err := boundContract.Transact(auth, "refundEnvelope", envelopeId)
+	tx, err := bind.Transact(s.instance(), auth, data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to refund envelope: %v", err)
+		return nil, err
 	}
 
-	return tx, nil
-}
+	if s.Journal != nil {
+		if err := s.Journal.Record(call.method, intentArgs(s.ABI.Methods[call.method], call.args), s.Address, tx); err != nil {
+			return nil, fmt.Errorf("failed to record journal: %v", err)
+		}
+	}
 
-// GetNextEnvelopeId mendapatkan next envelope ID
-func (s *RedEnvelopeService) GetNextEnvelopeId() (*big.Int, error) {
-	boundContract := bind.NewBoundContract(s.ContractAddress, s.ABI, s.Client, s.Client, s.Client)
+	s.log().Info("signed tx", append(s.callAttrs(call), txAttrs(tx)...)...)
 
-	var result []interface{}
-	err := boundContract.Call(&bind.CallOpts{}, &result, "nextEnvelopeId")
+	if call.onSigned != nil {
+		if err := call.onSigned(tx); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.Client.SendTransaction(context.Background(), tx)
+	s.Metrics.submitted(call.method, err)
+	s.logSent(intentAttrs(call.method, intentArgs(s.ABI.Methods[call.method], call.args)), tx, err)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get next envelope ID: %v", err)
+		if updateErr := s.journalBroadcastFailed(tx.Hash(), err); updateErr != nil {
+			return nil, errors.Join(err, updateErr)
+		}
+		return nil, err
 	}
 
-	if len(result) == 0 {
-		return nil, fmt.Errorf("no result returned from contract")
+	// Tx sudah di-broadcast: error journal hanya di-log supaya caller tidak
+	// mengirim ulang; entry yang tertinggal di signed diperbaiki reconcile
+	if s.Journal != nil {
+		if err := s.Journal.Update(tx.Hash(), JournalPending, 0, nil); err != nil {
+			s.log().Error("failed to update journal", append(txAttrs(tx), slog.Any("error", err))...)
+		}
 	}
 
-	return result[0].(*big.Int), nil
+	return tx, nil
 }
 
 // GenerateRoomIdHash helper untuk generate room ID hash
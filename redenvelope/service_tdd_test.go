@@ -3,6 +3,8 @@ package redenvelope
 import (
 	"context"
 	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -10,16 +12,41 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
-const (
-	testRPCURL          = "http://127.0.0.1:8545"
-	testContractAddress = "0x5FC8d32690cc91D4c39d9d3abcBD16989F875707"
-	testPrivateKey0     = "ac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80" // Account #0
-	testPrivateKey1     = "59c6995e998f97a5a0044966f0945389dc9e86dae88c7a8412f4603b6b78690d" // Account #1
-)
+// testContractAddress address contoh untuk unit test yang tidak butuh node.
+// Integration test memakai network "localhost" dari redenvelope.json.
+const testContractAddress = "0x5FC8d32690cc91D4c39d9d3abcBD16989F875707"
+
+// testNetwork network "localhost" dari config repo, bisa di-override lewat
+// REDENVELOPE_CONFIG / REDENVELOPE_CONTRACT / REDENVELOPE_RPC_URL
+func testNetwork(t *testing.T) *NetworkConfig {
+	path := os.Getenv(EnvConfig)
+	if path == "" {
+		path = filepath.Join("..", DefaultConfigPath)
+	}
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	network, err := config.Network("localhost")
+	if err != nil {
+		t.Fatalf("Failed to load network: %v", err)
+	}
+	return network
+}
 
 // Helper functions for testing
-func setupTestService(t *testing.T, privateKey string) *RedEnvelopeService {
-	service, err := NewRedEnvelopeService(testRPCURL, testContractAddress, privateKey)
+
+// testAccount akun Hardhat ke-index, diturunkan dari HardhatMnemonic
+func testAccount(t *testing.T, index uint32) *KeySigner {
+	signer, err := HardhatAccount(index)
+	if err != nil {
+		t.Fatalf("Failed to derive account #%d: %v", index, err)
+	}
+	return signer
+}
+
+func setupTestService(t *testing.T, account uint32) *RedEnvelopeService {
+	service, err := NewRedEnvelopeServiceForNetwork(testNetwork(t), testAccount(t, account))
 	if err != nil {
 		t.Fatalf("Failed to create service: %v", err)
 	}
@@ -48,7 +75,7 @@ func getBalance(t *testing.T, service *RedEnvelopeService, address common.Addres
 // ============================================================================
 
 func TestCreateEnvelope_DirectFixed_Success(t *testing.T) {
-	service := setupTestService(t, testPrivateKey0)
+	service := setupTestService(t, 0)
 	defer service.Client.Close()
 
 	// Test: Create DIRECT_FIXED envelope successfully
@@ -98,7 +125,7 @@ func TestCreateEnvelope_DirectFixed_Success(t *testing.T) {
 }
 
 func TestCreateEnvelope_GroupFixed_Success(t *testing.T) {
-	service := setupTestService(t, testPrivateKey0)
+	service := setupTestService(t, 0)
 	defer service.Client.Close()
 
 	// Test: Create GROUP_FIXED envelope successfully
@@ -151,7 +178,7 @@ func TestCreateEnvelope_GroupFixed_Success(t *testing.T) {
 }
 
 func TestCreateEnvelope_GroupRandom_Success(t *testing.T) {
-	service := setupTestService(t, testPrivateKey0)
+	service := setupTestService(t, 0)
 	defer service.Client.Close()
 
 	// Test: Create GROUP_RANDOM envelope successfully
@@ -198,7 +225,7 @@ func TestCreateEnvelope_GroupRandom_Success(t *testing.T) {
 }
 
 func TestCreateEnvelope_WithRoomIdHash(t *testing.T) {
-	service := setupTestService(t, testPrivateKey0)
+	service := setupTestService(t, 0)
 	defer service.Client.Close()
 
 	// Test: Create envelope with room restriction
@@ -248,7 +275,7 @@ func TestCreateEnvelope_WithRoomIdHash(t *testing.T) {
 
 func TestClaimEnvelope_FirstClaim_Success(t *testing.T) {
 	// Setup: Create envelope with account #0
-	serviceCreator := setupTestService(t, testPrivateKey0)
+	serviceCreator := setupTestService(t, 0)
 	defer serviceCreator.Client.Close()
 
 	nextId, err := serviceCreator.GetNextEnvelopeId()
@@ -273,7 +300,7 @@ func TestClaimEnvelope_FirstClaim_Success(t *testing.T) {
 	waitForTransaction(t, serviceCreator, tx)
 
 	// Test: Claim with account #1
-	serviceClaimer := setupTestService(t, testPrivateKey1)
+	serviceClaimer := setupTestService(t, 1)
 	defer serviceClaimer.Client.Close()
 
 	// Verify hasn't claimed yet
@@ -330,7 +357,7 @@ func TestClaimEnvelope_FirstClaim_Success(t *testing.T) {
 
 func TestClaimEnvelope_DoubleClaim_ShouldFail(t *testing.T) {
 	// Setup: Create and claim envelope
-	serviceCreator := setupTestService(t, testPrivateKey0)
+	serviceCreator := setupTestService(t, 0)
 	defer serviceCreator.Client.Close()
 
 	nextId, _ := serviceCreator.GetNextEnvelopeId()
@@ -347,7 +374,7 @@ func TestClaimEnvelope_DoubleClaim_ShouldFail(t *testing.T) {
 	)
 	waitForTransaction(t, serviceCreator, tx)
 
-	serviceClaimer := setupTestService(t, testPrivateKey1)
+	serviceClaimer := setupTestService(t, 1)
 	defer serviceClaimer.Client.Close()
 
 	// First claim
@@ -365,7 +392,7 @@ func TestClaimEnvelope_DoubleClaim_ShouldFail(t *testing.T) {
 
 func TestClaimEnvelope_DirectFixedByRecipient_Success(t *testing.T) {
 	// Setup: Create DIRECT_FIXED envelope for account #1
-	serviceCreator := setupTestService(t, testPrivateKey0)
+	serviceCreator := setupTestService(t, 0)
 	defer serviceCreator.Client.Close()
 
 	nextId, _ := serviceCreator.GetNextEnvelopeId()
@@ -387,7 +414,7 @@ func TestClaimEnvelope_DirectFixedByRecipient_Success(t *testing.T) {
 	waitForTransaction(t, serviceCreator, tx)
 
 	// Test: Claim by designated recipient
-	serviceClaimer := setupTestService(t, testPrivateKey1)
+	serviceClaimer := setupTestService(t, 1)
 	defer serviceClaimer.Client.Close()
 
 	balanceBefore := getBalance(t, serviceClaimer, serviceClaimer.Address)
@@ -410,7 +437,7 @@ func TestClaimEnvelope_DirectFixedByRecipient_Success(t *testing.T) {
 
 func TestClaimEnvelope_AllClaims_Success(t *testing.T) {
 	// Setup: Create envelope with 2 claims
-	serviceCreator := setupTestService(t, testPrivateKey0)
+	serviceCreator := setupTestService(t, 0)
 	defer serviceCreator.Client.Close()
 
 	nextId, _ := serviceCreator.GetNextEnvelopeId()
@@ -429,7 +456,7 @@ func TestClaimEnvelope_AllClaims_Success(t *testing.T) {
 	waitForTransaction(t, serviceCreator, tx)
 
 	// Claim with account #1
-	serviceClaimer1 := setupTestService(t, testPrivateKey1)
+	serviceClaimer1 := setupTestService(t, 1)
 	defer serviceClaimer1.Client.Close()
 	claimTx1, _ := serviceClaimer1.ClaimEnvelope(nextId)
 	waitForTransaction(t, serviceClaimer1, claimTx1)
@@ -458,7 +485,7 @@ func TestRefundEnvelope_AfterExpiry_Success(t *testing.T) {
 	t.Skip("Skipping test that requires waiting for expiry - run manually if needed")
 
 	// Setup: Create envelope with short expiry
-	serviceCreator := setupTestService(t, testPrivateKey0)
+	serviceCreator := setupTestService(t, 0)
 	defer serviceCreator.Client.Close()
 
 	nextId, _ := serviceCreator.GetNextEnvelopeId()
@@ -509,7 +536,7 @@ func TestRefundEnvelope_AfterExpiry_Success(t *testing.T) {
 
 func TestRefundEnvelope_BeforeExpiry_ShouldFail(t *testing.T) {
 	// Setup: Create envelope with long expiry
-	serviceCreator := setupTestService(t, testPrivateKey0)
+	serviceCreator := setupTestService(t, 0)
 	defer serviceCreator.Client.Close()
 
 	nextId, _ := serviceCreator.GetNextEnvelopeId()
@@ -539,7 +566,7 @@ func TestRefundEnvelope_PartialClaims_Success(t *testing.T) {
 	t.Skip("Skipping test that requires waiting for expiry - run manually if needed")
 
 	// Setup: Create envelope, make 1 claim, then refund after expiry
-	serviceCreator := setupTestService(t, testPrivateKey0)
+	serviceCreator := setupTestService(t, 0)
 	defer serviceCreator.Client.Close()
 
 	nextId, _ := serviceCreator.GetNextEnvelopeId()
@@ -558,7 +585,7 @@ func TestRefundEnvelope_PartialClaims_Success(t *testing.T) {
 	waitForTransaction(t, serviceCreator, tx)
 
 	// Make 1 claim
-	serviceClaimer := setupTestService(t, testPrivateKey1)
+	serviceClaimer := setupTestService(t, 1)
 	defer serviceClaimer.Client.Close()
 
 	claimTx, _ := serviceClaimer.ClaimEnvelope(nextId)
@@ -592,7 +619,7 @@ func TestRefundEnvelope_NonCreator_ShouldFail(t *testing.T) {
 	t.Skip("Skipping test that requires waiting for expiry - run manually if needed")
 
 	// Setup: Create envelope with account #0
-	serviceCreator := setupTestService(t, testPrivateKey0)
+	serviceCreator := setupTestService(t, 0)
 	defer serviceCreator.Client.Close()
 
 	nextId, _ := serviceCreator.GetNextEnvelopeId()
@@ -613,7 +640,7 @@ func TestRefundEnvelope_NonCreator_ShouldFail(t *testing.T) {
 	time.Sleep(5 * time.Second)
 
 	// Test: Try to refund with different account (should fail)
-	serviceOther := setupTestService(t, testPrivateKey1)
+	serviceOther := setupTestService(t, 1)
 	defer serviceOther.Client.Close()
 
 	_, err := serviceOther.RefundEnvelope(nextId)
@@ -628,7 +655,7 @@ func TestRefundEnvelope_AllClaimsExhausted_ShouldFail(t *testing.T) {
 	t.Skip("Skipping test that requires waiting for expiry - run manually if needed")
 
 	// Setup: Create envelope with 1 claim, claim it, wait for expiry
-	serviceCreator := setupTestService(t, testPrivateKey0)
+	serviceCreator := setupTestService(t, 0)
 	defer serviceCreator.Client.Close()
 
 	nextId, _ := serviceCreator.GetNextEnvelopeId()
@@ -646,7 +673,7 @@ func TestRefundEnvelope_AllClaimsExhausted_ShouldFail(t *testing.T) {
 	waitForTransaction(t, serviceCreator, tx)
 
 	// Claim the only available claim
-	serviceClaimer := setupTestService(t, testPrivateKey1)
+	serviceClaimer := setupTestService(t, 1)
 	defer serviceClaimer.Client.Close()
 	claimTx, _ := serviceClaimer.ClaimEnvelope(nextId)
 	waitForTransaction(t, serviceClaimer, claimTx)
@@ -0,0 +1,92 @@
+package redenvelope
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Signer semua transaksi write RedEnvelopeService ditandatangani lewat
+// interface ini, jadi key material tidak harus ada di dalam service
+type Signer interface {
+	// Address alamat akun yang menandatangani
+	Address() common.Address
+	// SignTx menandatangani transaksi untuk chainID tertentu
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	// SignTypedData menandatangani data EIP-712, hasilnya signature 65 byte (V = 27/28)
+	SignTypedData(typedData apitypes.TypedData) ([]byte, error)
+}
+
+// KeySigner Signer dengan private key di memory
+type KeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewKeySigner membuat KeySigner dari private key hex (tanpa 0x)
+func NewKeySigner(privateKeyHex string) (*KeySigner, error) {
+	privateKey, err := crypto.HexToECDSA(privateKeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load private key: %v", err)
+	}
+	return NewKeySignerFromECDSA(privateKey), nil
+}
+
+// NewKeySignerFromECDSA membuat KeySigner dari *ecdsa.PrivateKey
+func NewKeySignerFromECDSA(privateKey *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{
+		key:     privateKey,
+		address: crypto.PubkeyToAddress(privateKey.PublicKey),
+	}
+}
+
+// Address alamat akun KeySigner
+func (k *KeySigner) Address() common.Address {
+	return k.address
+}
+
+// SignTx menandatangani transaksi dengan signer terbaru untuk chainID
+func (k *KeySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), k.key)
+}
+
+// SignTypedData menandatangani data EIP-712
+func (k *KeySigner) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %v", err)
+	}
+
+	signature, err := crypto.Sign(hash, k.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign typed data: %v", err)
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	return signature, nil
+}
+
+// String tidak pernah menampilkan private key
+func (k *KeySigner) String() string {
+	return fmt.Sprintf("KeySigner(%s)", k.address.Hex())
+}
+
+// transactOpts membuat bind.TransactOpts yang menandatangani lewat s.Signer
+func (s *RedEnvelopeService) transactOpts() *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From: s.Address,
+		Signer: func(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if from != s.Address {
+				return nil, bind.ErrNotAuthorized
+			}
+			return s.Signer.SignTx(tx, s.ChainID)
+		},
+		Context: context.Background(),
+	}
+}
@@ -0,0 +1,112 @@
+package redenvelope
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+var testAddress0 = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+
+// testKeyHex private key hex akun Hardhat ke-index
+func testKeyHex(t *testing.T, index uint32) string {
+	return hex.EncodeToString(crypto.FromECDSA(testAccount(t, index).key))
+}
+
+func testTypedData() apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+			},
+			"Claim": {
+				{Name: "envelopeId", Type: "uint256"},
+			},
+		},
+		PrimaryType: "Claim",
+		Domain: apitypes.TypedDataDomain{
+			Name:    "RedEnvelope",
+			ChainId: math.NewHexOrDecimal256(31337),
+		},
+		Message: apitypes.TypedDataMessage{
+			"envelopeId": "1",
+		},
+	}
+}
+
+func TestKeySigner_SignTx(t *testing.T) {
+	signer, err := NewKeySigner(testKeyHex(t, 0))
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+	if signer.Address() != testAddress0 {
+		t.Fatalf("Expected address %s, got %s", testAddress0.Hex(), signer.Address().Hex())
+	}
+
+	chainID := big.NewInt(31337)
+	tx := types.NewTransaction(0, common.HexToAddress(testContractAddress), big.NewInt(1), 21000, big.NewInt(1), nil)
+	signed, err := signer.SignTx(tx, chainID)
+	if err != nil {
+		t.Fatalf("Failed to sign tx: %v", err)
+	}
+
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		t.Fatalf("Failed to recover sender: %v", err)
+	}
+	if sender != testAddress0 {
+		t.Errorf("Expected sender %s, got %s", testAddress0.Hex(), sender.Hex())
+	}
+}
+
+func TestKeySigner_SignTypedData(t *testing.T) {
+	signer, err := NewKeySigner(testKeyHex(t, 0))
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+
+	typedData := testTypedData()
+	signature, err := signer.SignTypedData(typedData)
+	if err != nil {
+		t.Fatalf("Failed to sign typed data: %v", err)
+	}
+	if len(signature) != 65 || (signature[64] != 27 && signature[64] != 28) {
+		t.Fatalf("Unexpected signature format: %x", signature)
+	}
+
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		t.Fatalf("Failed to hash typed data: %v", err)
+	}
+	sig := append([]byte{}, signature...)
+	sig[64] -= 27
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		t.Fatalf("Failed to recover public key: %v", err)
+	}
+	if crypto.PubkeyToAddress(*pub) != testAddress0 {
+		t.Errorf("Recovered wrong address: %s", crypto.PubkeyToAddress(*pub).Hex())
+	}
+}
+
+func TestKeySigner_DoesNotLeakKey(t *testing.T) {
+	signer, err := NewKeySigner(testKeyHex(t, 0))
+	if err != nil {
+		t.Fatalf("Failed to create signer: %v", err)
+	}
+
+	for _, formatted := range []string{fmt.Sprint(signer), fmt.Sprintf("%v", signer), fmt.Sprintf("%+v", signer)} {
+		if strings.Contains(formatted, testKeyHex(t, 0)) {
+			t.Fatalf("Formatted signer leaks private key: %s", formatted)
+		}
+	}
+}
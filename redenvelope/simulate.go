@@ -0,0 +1,84 @@
+package redenvelope
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// WithSimulation menjalankan setiap transaksi write lewat eth_call dulu
+// sebelum ditandatangani, supaya revert tidak membuang gas
+func WithSimulation() Option {
+	return func(s *RedEnvelopeService) error {
+		s.SimulateWrites = true
+		return nil
+	}
+}
+
+// SimulateCreateEnvelope simulasi createEnvelope dan mengembalikan envelopeId
+// yang akan dibuat
+func (s *RedEnvelopeService) SimulateCreateEnvelope(
+	kind uint8,
+	token common.Address,
+	totalClaims uint32,
+	amount *big.Int,
+	expiryDuration time.Duration,
+	roomIdHash [32]byte,
+	recipient common.Address,
+) (*big.Int, error) {
+	if err := checkAmount(amount); err != nil {
+		return nil, err
+	}
+	expiry := uint64(time.Now().Add(expiryDuration).Unix())
+	return simulateCall(s, createEnvelopeCall(kind, token, totalClaims, amount, expiry, roomIdHash, recipient), redEnvelope.UnpackCreateEnvelope)
+}
+
+// SimulateClaimEnvelope simulasi claimEnvelope dan mengembalikan payout
+func (s *RedEnvelopeService) SimulateClaimEnvelope(envelopeId *big.Int) (*big.Int, error) {
+	return simulateCall(s, claimEnvelopeCall(envelopeId), redEnvelope.UnpackClaimEnvelope)
+}
+
+// SimulateRefundEnvelope simulasi refundEnvelope dan mengembalikan refundAmount
+func (s *RedEnvelopeService) SimulateRefundEnvelope(envelopeId *big.Int) (*big.Int, error) {
+	return simulateCall(s, refundEnvelopeCall(envelopeId), redEnvelope.UnpackRefundEnvelope)
+}
+
+// simulateCall simulasi call lalu decode hasilnya dengan unpack dari binding
+func simulateCall[T any](s *RedEnvelopeService, call *writeCall, unpack func([]byte) (T, error)) (T, error) {
+	var zero T
+	output, err := s.simulate(call)
+	if err != nil {
+		return zero, err
+	}
+	result, err := unpack(output)
+	if err != nil {
+		return zero, fmt.Errorf("failed to unpack %s result: %v", call.method, err)
+	}
+	return result, nil
+}
+
+// simulate menjalankan calldata dan value yang sama persis dengan transaksi
+// asli lewat eth_call dari sender di pending block
+func (s *RedEnvelopeService) simulate(call *writeCall) ([]byte, error) {
+	data, err := call.calldata()
+	if err != nil {
+		return nil, err
+	}
+
+	msg := ethereum.CallMsg{
+		From:  s.Address,
+		To:    &s.ContractAddress,
+		Gas:   call.gasLimit,
+		Value: call.value,
+		Data:  data,
+	}
+	output, err := s.Client.PendingCallContract(context.Background(), msg)
+	if err != nil {
+		return nil, s.DecodeRevert(err)
+	}
+	return output, nil
+}
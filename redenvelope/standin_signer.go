@@ -0,0 +1,102 @@
+package redenvelope
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// ApproveFunc memutuskan apakah request tanda tangan disetujui. method adalah
+// nama method JSON-RPC (mis. "account_signTransaction"), request argumennya.
+type ApproveFunc func(method string, request interface{}) bool
+
+// errRequestDenied pesan yang sama dengan Clef saat request ditolak
+var errRequestDenied = errors.New("Request denied")
+
+// StandInSigner server JSON-RPC lokal yang meniru API Clef di atas Signer
+// biasa. Dipakai untuk test dan development tanpa menjalankan Clef.
+type StandInSigner struct {
+	server *rpc.Server
+}
+
+// NewStandInSigner membuat stand-in signer. approve nil berarti semua
+// request disetujui.
+func NewStandInSigner(signer Signer, approve ApproveFunc) (*StandInSigner, error) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", &standInAPI{signer: signer, approve: approve}); err != nil {
+		return nil, fmt.Errorf("failed to register signer API: %v", err)
+	}
+	return &StandInSigner{server: server}, nil
+}
+
+// ServeHTTP melayani request JSON-RPC lewat HTTP
+func (s *StandInSigner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.server.ServeHTTP(w, r)
+}
+
+// Close menghentikan server
+func (s *StandInSigner) Close() {
+	s.server.Stop()
+}
+
+// standInAPI namespace "account" ala Clef
+type standInAPI struct {
+	signer  Signer
+	approve ApproveFunc
+}
+
+func (api *standInAPI) approved(method string, request interface{}) bool {
+	return api.approve == nil || api.approve(method, request)
+}
+
+// List account_list
+func (api *standInAPI) List(ctx context.Context) ([]common.Address, error) {
+	if !api.approved("account_list", nil) {
+		return nil, errRequestDenied
+	}
+	return []common.Address{api.signer.Address()}, nil
+}
+
+// SignTransaction account_signTransaction
+func (api *standInAPI) SignTransaction(ctx context.Context, args apitypes.SendTxArgs, methodSelector *string) (*signTransactionResult, error) {
+	if args.From.Address() != api.signer.Address() {
+		return nil, fmt.Errorf("unknown account %s", args.From.Address().Hex())
+	}
+	if args.ChainID == nil {
+		return nil, fmt.Errorf("chainId is required")
+	}
+	if !api.approved("account_signTransaction", args) {
+		return nil, errRequestDenied
+	}
+
+	tx, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+	signed, err := api.signer.SignTx(tx, args.ChainID.ToInt())
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &signTransactionResult{Raw: raw, Tx: signed}, nil
+}
+
+// SignTypedData account_signTypedData
+func (api *standInAPI) SignTypedData(ctx context.Context, addr common.MixedcaseAddress, typedData apitypes.TypedData) (hexutil.Bytes, error) {
+	if addr.Address() != api.signer.Address() {
+		return nil, fmt.Errorf("unknown account %s", addr.Address().Hex())
+	}
+	if !api.approved("account_signTypedData", typedData) {
+		return nil, errRequestDenied
+	}
+	return api.signer.SignTypedData(typedData)
+}
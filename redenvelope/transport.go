@@ -0,0 +1,110 @@
+package redenvelope
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// rpcTransport http.RoundTripper ke node yang mengukur (WithMetrics) dan
+// mencatat (WithLogger) setiap method JSON-RPC, termasuk batch dan polling
+// di dalam go-ethereum seperti WaitMined. Params tidak pernah dicatat.
+type rpcTransport struct {
+	base    http.RoundTripper
+	metrics atomic.Pointer[Metrics]
+	logger  atomic.Pointer[slog.Logger]
+}
+
+// rpcMessage bagian request / response JSON-RPC yang dibutuhkan
+type rpcMessage struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (t *rpcTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	m, logger := t.metrics.Load(), t.logger.Load()
+	if (m == nil && logger == nil) || req.Body == nil {
+		return t.base.RoundTrip(req)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	calls := decodeRPCMessages(body)
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start)
+	failure := ""
+	switch {
+	case err != nil:
+		failure = err.Error()
+	case resp.StatusCode/100 != 2:
+		failure = "http " + resp.Status
+	}
+	if failure != "" {
+		for _, call := range calls {
+			m.rpcCall(call.Method, elapsed, true)
+			t.log(logger, req, call.Method, elapsed, failure)
+		}
+		return resp, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	failures := make(map[string]string)
+	for _, result := range decodeRPCMessages(respBody) {
+		if result.Error != nil {
+			failures[string(result.ID)] = result.Error.Message
+		}
+	}
+	for _, call := range calls {
+		failure, failed := failures[string(call.ID)]
+		m.rpcCall(call.Method, elapsed, failed)
+		t.log(logger, req, call.Method, elapsed, failure)
+	}
+	return resp, nil
+}
+
+// log satu panggilan di level Debug, error di level Warn
+func (t *rpcTransport) log(logger *slog.Logger, req *http.Request, method string, elapsed time.Duration, failure string) {
+	if logger == nil {
+		return
+	}
+	attrs := []any{slog.String("rpcMethod", method), slog.Duration("duration", elapsed)}
+	if failure != "" {
+		logger.WarnContext(req.Context(), "rpc call failed", append(attrs, slog.String("error", failure))...)
+		return
+	}
+	logger.DebugContext(req.Context(), "rpc call", attrs...)
+}
+
+// decodeRPCMessages satu message atau batch
+func decodeRPCMessages(data []byte) []rpcMessage {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var batch []rpcMessage
+		json.Unmarshal(data, &batch)
+		return batch
+	}
+	var single rpcMessage
+	if json.Unmarshal(data, &single) != nil {
+		return nil
+	}
+	return []rpcMessage{single}
+}
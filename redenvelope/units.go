@@ -0,0 +1,98 @@
+package redenvelope
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// NativeDecimals decimals native token (ETH/BNB)
+const NativeDecimals = 18
+
+// ParseAmount mengubah amount desimal ("0.5", "1.25") menjadi unit terkecil
+// dengan decimals tertentu. Suffix "wei" berarti angka sudah dalam unit
+// terkecil.
+func ParseAmount(value string, decimals uint8) (*big.Int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "." {
+		return nil, fmt.Errorf("invalid amount %q", value)
+	}
+	if raw, ok := strings.CutSuffix(value, "wei"); ok {
+		amount, ok := new(big.Int).SetString(strings.TrimSpace(raw), 10)
+		if !ok || amount.Sign() < 0 {
+			return nil, fmt.Errorf("invalid amount %q", value)
+		}
+		return amount, nil
+	}
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	if whole == "" {
+		whole = "0"
+	}
+	if len(fraction) > int(decimals) {
+		return nil, fmt.Errorf("amount %q has more than %d decimals", value, decimals)
+	}
+	if strings.HasPrefix(whole, "-") || strings.HasPrefix(whole, "+") {
+		return nil, fmt.Errorf("invalid amount %q", value)
+	}
+
+	digits := whole + fraction + strings.Repeat("0", int(decimals)-len(fraction))
+	amount, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", value)
+	}
+	return amount, nil
+}
+
+// FormatAmount kebalikan ParseAmount, tanpa nol di belakang koma
+func FormatAmount(amount *big.Int, decimals uint8) string {
+	if amount == nil {
+		return "0"
+	}
+	sign := ""
+	if amount.Sign() < 0 {
+		sign = "-"
+	}
+
+	digits := new(big.Int).Abs(amount).String()
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+	whole := digits[:len(digits)-int(decimals)]
+	fraction := strings.TrimRight(digits[len(digits)-int(decimals):], "0")
+	if fraction == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + fraction
+}
+
+// FeeQuote rincian fee untuk satu envelope
+type FeeQuote struct {
+	FeeBps   uint16
+	GrossPot *big.Int // Yang ditarik dari creator
+	Fee      *big.Int // Ke treasury
+	NetPot   *big.Int // Yang bisa diklaim
+}
+
+// QuoteFee menghitung fee seperti contract:
+// fee = grossPot × feeBps / 10000, netPot = grossPot - fee
+func QuoteFee(kind uint8, totalClaims uint32, amount *big.Int, feeBps uint16) *FeeQuote {
+	grossPot := GrossPot(kind, totalClaims, amount)
+	fee := new(big.Int).Mul(grossPot, big.NewInt(int64(feeBps)))
+	fee.Div(fee, big.NewInt(MaxFeeBps))
+	return &FeeQuote{
+		FeeBps:   feeBps,
+		GrossPot: grossPot,
+		Fee:      fee,
+		NetPot:   new(big.Int).Sub(grossPot, fee),
+	}
+}
+
+// QuoteFee menghitung fee dengan feeBps yang sedang berlaku di contract
+func (s *RedEnvelopeService) QuoteFee(kind uint8, totalClaims uint32, amount *big.Int) (*FeeQuote, error) {
+	feeBps, err := s.GetFeeBps()
+	if err != nil {
+		return nil, err
+	}
+	return QuoteFee(kind, totalClaims, amount, feeBps), nil
+}
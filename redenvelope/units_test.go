@@ -0,0 +1,97 @@
+package redenvelope
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value    string
+		decimals uint8
+		want     string
+	}{
+		{"1", 18, "1000000000000000000"},
+		{"0.5", 18, "500000000000000000"},
+		{".25", 6, "250000"},
+		{"12.000001", 6, "12000001"},
+		{"42wei", 18, "42"},
+		{" 3 ", 0, "3"},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.value, tt.decimals)
+		if err != nil {
+			t.Errorf("ParseAmount(%q, %d) failed: %v", tt.value, tt.decimals, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("ParseAmount(%q, %d) = %s, want %s", tt.value, tt.decimals, got, tt.want)
+		}
+	}
+
+	for _, value := range []string{"", "abc", "-1", "1.0000001", "1.2.3", "-5wei"} {
+		if _, err := ParseAmount(value, 6); err == nil {
+			t.Errorf("Expected error for %q", value)
+		}
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		decimals uint8
+		want     string
+	}{
+		{1500000, 6, "1.5"},
+		{1, 6, "0.000001"},
+		{0, 18, "0"},
+		{2000000, 6, "2"},
+		{-250, 2, "-2.5"},
+		{7, 0, "7"},
+	}
+	for _, tt := range tests {
+		if got := FormatAmount(big.NewInt(tt.amount), tt.decimals); got != tt.want {
+			t.Errorf("FormatAmount(%d, %d) = %s, want %s", tt.amount, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteFee(t *testing.T) {
+	quote := QuoteFee(GROUP_FIXED, 3, big.NewInt(1000), 250)
+	if quote.GrossPot.Int64() != 3000 || quote.Fee.Int64() != 75 || quote.NetPot.Int64() != 2925 {
+		t.Errorf("Unexpected quote %+v", quote)
+	}
+
+	// Pembulatan ke bawah seperti Solidity
+	quote = QuoteFee(GROUP_RANDOM, 5, big.NewInt(999), 100)
+	if quote.GrossPot.Int64() != 999 || quote.Fee.Int64() != 9 || quote.NetPot.Int64() != 990 {
+		t.Errorf("Unexpected quote %+v", quote)
+	}
+}
+
+func TestParseEnvelopeEvent(t *testing.T) {
+	service := newABIOnlyService(t)
+	event := service.ABI.Events[EventEnvelopeRefunded]
+	data, err := event.Inputs.Pack(big.NewInt(9), big.NewInt(1234))
+	if err != nil {
+		t.Fatalf("Failed to pack event: %v", err)
+	}
+
+	parsed, err := service.ParseEnvelopeEvent(&types.Log{Topics: []common.Hash{event.ID}, Data: data, BlockNumber: 7, Index: 2})
+	if err != nil {
+		t.Fatalf("Failed to parse event: %v", err)
+	}
+	if parsed.Name != EventEnvelopeRefunded || parsed.EnvelopeID.Int64() != 9 || parsed.BlockNumber != 7 || parsed.LogIndex != 2 {
+		t.Fatalf("Unexpected event %+v", parsed)
+	}
+	if parsed.Refunded == nil || parsed.Refunded.RefundAmount.Int64() != 1234 || parsed.Claimed != nil {
+		t.Errorf("Unexpected payload %+v", parsed)
+	}
+
+	if _, err := service.ParseEnvelopeEvent(&types.Log{Topics: []common.Hash{{0x01}}}); err == nil {
+		t.Error("Expected error for unknown topic")
+	}
+}
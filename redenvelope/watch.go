@@ -0,0 +1,205 @@
+package redenvelope
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Nama event RedEnvelope
+const (
+	EventEnvelopeCreated  = "EnvelopeCreated"
+	EventEnvelopeClaimed  = "EnvelopeClaimed"
+	EventEnvelopeRefunded = "EnvelopeRefunded"
+)
+
+// maxLogRange jumlah block maksimal per eth_getLogs, banyak provider
+// membatasi range query
+const maxLogRange = 2000
+
+// DefaultPollInterval jeda polling WatchEvents kalau tidak diisi
+const DefaultPollInterval = 2 * time.Second
+
+// EnvelopeEvent satu event RedEnvelope beserta posisinya di chain. Tepat satu
+// dari Created, Claimed atau Refunded terisi sesuai Name.
+type EnvelopeEvent struct {
+	Name        string
+	EnvelopeID  *big.Int
+	BlockNumber uint64
+	BlockHash   common.Hash
+	TxHash      common.Hash
+	LogIndex    uint
+
+	Created  *EnvelopeCreatedEvent
+	Claimed  *EnvelopeClaimedEvent
+	Refunded *EnvelopeRefundedEvent
+}
+
+// WatchOptions pengaturan WatchEvents
+type WatchOptions struct {
+	FromBlock    uint64        // Block pertama yang dibaca
+	PollInterval time.Duration // Default DefaultPollInterval
+
+	// OnSynced (opsional) dipanggil setelah semua event sampai block
+	// tersebut sudah diteruskan ke handle
+	OnSynced func(block uint64)
+}
+
+// ParseEnvelopeEvent decode log RedEnvelope menjadi EnvelopeEvent
+func (s *RedEnvelopeService) ParseEnvelopeEvent(log *types.Log) (*EnvelopeEvent, error) {
+	if len(log.Topics) == 0 {
+		return nil, fmt.Errorf("log has no topics")
+	}
+
+	event := &EnvelopeEvent{
+		BlockNumber: log.BlockNumber,
+		BlockHash:   log.BlockHash,
+		TxHash:      log.TxHash,
+		LogIndex:    log.Index,
+	}
+
+	switch log.Topics[0] {
+	case s.ABI.Events[EventEnvelopeCreated].ID:
+		created, err := s.ParseEnvelopeCreated(log)
+		if err != nil {
+			return nil, err
+		}
+		event.Name, event.EnvelopeID, event.Created = EventEnvelopeCreated, created.EnvelopeId, created
+	case s.ABI.Events[EventEnvelopeClaimed].ID:
+		claimed, err := s.ParseEnvelopeClaimed(log)
+		if err != nil {
+			return nil, err
+		}
+		event.Name, event.EnvelopeID, event.Claimed = EventEnvelopeClaimed, claimed.EnvelopeId, claimed
+	case s.ABI.Events[EventEnvelopeRefunded].ID:
+		refunded, err := s.ParseEnvelopeRefunded(log)
+		if err != nil {
+			return nil, err
+		}
+		event.Name, event.EnvelopeID, event.Refunded = EventEnvelopeRefunded, refunded.EnvelopeId, refunded
+	default:
+		return nil, fmt.Errorf("unknown event topic %s", log.Topics[0].Hex())
+	}
+	return event, nil
+}
+
+// EventsFromReceipt semua event RedEnvelope di receipt
+func (s *RedEnvelopeService) EventsFromReceipt(receipt *types.Receipt) ([]EnvelopeEvent, error) {
+	var events []EnvelopeEvent
+	for _, log := range receipt.Logs {
+		if log.Address != s.ContractAddress {
+			continue
+		}
+		event, err := s.ParseEnvelopeEvent(log)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, *event)
+	}
+	return events, nil
+}
+
+// FilterEvents event RedEnvelope di block from..to (inklusif), berurutan
+func (s *RedEnvelopeService) FilterEvents(ctx context.Context, from, to uint64) ([]EnvelopeEvent, error) {
+	var events []EnvelopeEvent
+	err := s.ForEachEvent(ctx, from, to, func(event EnvelopeEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ForEachEvent memanggil handle untuk setiap event RedEnvelope di block
+// from..to (inklusif), berurutan, satu window maxLogRange block per
+// eth_getLogs. Berhenti di error pertama dari node atau handle.
+func (s *RedEnvelopeService) ForEachEvent(ctx context.Context, from, to uint64, handle func(EnvelopeEvent) error) error {
+	topics := []common.Hash{
+		s.ABI.Events[EventEnvelopeCreated].ID,
+		s.ABI.Events[EventEnvelopeClaimed].ID,
+		s.ABI.Events[EventEnvelopeRefunded].ID,
+	}
+
+	for start := from; start <= to; start += maxLogRange {
+		end := min(start+maxLogRange-1, to)
+		logs, err := s.Client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(start),
+			ToBlock:   new(big.Int).SetUint64(end),
+			Addresses: []common.Address{s.ContractAddress},
+			Topics:    [][]common.Hash{topics},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to filter logs %d-%d: %v", start, end, err)
+		}
+		for i := range logs {
+			if logs[i].Removed {
+				continue
+			}
+			event, err := s.ParseEnvelopeEvent(&logs[i])
+			if err != nil {
+				return err
+			}
+			if err := handle(*event); err != nil {
+				return err
+			}
+		}
+		if end == to {
+			break
+		}
+	}
+	return nil
+}
+
+// WatchEvents polling event baru mulai opts.FromBlock dan memanggil handle
+// untuk setiap event secara berurutan. Hanya block dengan s.Confirmations
+// konfirmasi yang dibaca. Berhenti kalau ctx selesai atau handle error.
+func (s *RedEnvelopeService) WatchEvents(ctx context.Context, opts WatchOptions, handle func(EnvelopeEvent) error) error {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	next := opts.FromBlock
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		head, err := s.Client.BlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get block number: %v", err)
+		}
+		s.Metrics.observeHead(head)
+
+		if head >= s.Confirmations && head-s.Confirmations >= next {
+			safe := head - s.Confirmations
+			events, err := s.FilterEvents(ctx, next, safe)
+			if err != nil {
+				return err
+			}
+			for _, event := range events {
+				s.logEvent(ctx, slog.LevelDebug, event)
+				if err := handle(event); err != nil {
+					return err
+				}
+			}
+			next = safe + 1
+			if opts.OnSynced != nil {
+				opts.OnSynced(safe)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,486 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"rpcsol/redenvelope"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Default AuthConfig
+const (
+	DefaultSessionTTL = 24 * time.Hour
+	DefaultNonceTTL   = 10 * time.Minute
+	DefaultMaxNonces  = 10000
+
+	// issuedAtSkew toleransi jam client yang sedikit lebih cepat
+	issuedAtSkew = time.Minute
+)
+
+// AuthConfig konfigurasi login Sign-In With Ethereum
+type AuthConfig struct {
+	Domain     string           // Harus sama dengan domain di pesan SIWE, misalnya app.example.com
+	SessionTTL time.Duration    // Umur token session, default DefaultSessionTTL
+	NonceTTL   time.Duration    // Umur nonce sebelum dipakai login, default DefaultNonceTTL
+	MaxNonces  int              // Nonce yang belum dipakai sekaligus, default DefaultMaxNonces
+	Operators  []common.Address // Boleh memanggil endpoint admin; owner contract selalu boleh
+}
+
+// WithAuth mewajibkan login SIWE untuk endpoint write dan memasang
+// /auth/* serta endpoint admin. Address session menjadi from setiap tx:
+// refund hanya untuk creator envelope, endpoint admin hanya untuk operator.
+func WithAuth(config AuthConfig) Option {
+	return func(s *Server) error {
+		if config.Domain == "" {
+			return fmt.Errorf("auth domain is required")
+		}
+		if config.SessionTTL == 0 {
+			config.SessionTTL = DefaultSessionTTL
+		}
+		if config.NonceTTL == 0 {
+			config.NonceTTL = DefaultNonceTTL
+		}
+		if config.MaxNonces == 0 {
+			config.MaxNonces = DefaultMaxNonces
+		}
+		s.auth = &authenticator{
+			config:   config,
+			nonces:   make(map[string]time.Time),
+			sessions: make(map[string]session),
+		}
+		return nil
+	}
+}
+
+// access siapa yang boleh memanggil route kalau auth aktif
+type access int
+
+const (
+	accessPublic   access = iota // Tanpa login; ops write tetap butuh session
+	accessSession                // Butuh session
+	accessOperator               // Butuh session operator / owner contract
+)
+
+// session login yang masih berlaku
+type session struct {
+	address   common.Address
+	expiresAt time.Time
+}
+
+// authenticator nonce dan session di memori; hilang saat restart
+type authenticator struct {
+	config AuthConfig
+
+	mu       sync.Mutex
+	nonces   map[string]time.Time // nonce -> kedaluwarsa
+	sessions map[string]session   // token -> session
+}
+
+// issueNonce nonce baru untuk satu kali login. /auth/nonce publik, jadi
+// jumlah nonce yang belum dipakai dibatasi MaxNonces.
+func (a *authenticator) issueNonce(now time.Time) (string, time.Time, error) {
+	nonce, err := randomToken(16)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt := now.Add(a.config.NonceTTL)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.prune(now)
+	if len(a.nonces) >= a.config.MaxNonces {
+		return "", time.Time{}, tooManyRequests("too many pending sign-in nonces, try again later")
+	}
+	a.nonces[nonce] = expiresAt
+	return nonce, expiresAt, nil
+}
+
+// login verifikasi pesan SIWE dan signature-nya, lalu membuat session
+func (a *authenticator) login(text string, signature []byte, chainID uint64, now time.Time) (string, session, error) {
+	message, err := ParseSIWEMessage(text)
+	if err != nil {
+		return "", session{}, badRequest("%v", err)
+	}
+	signer, err := recoverSigner(text, signature)
+	if err != nil {
+		return "", session{}, unauthenticated("%v", err)
+	}
+
+	switch {
+	case signer != message.Address:
+		return "", session{}, unauthenticated("signature is from %s, not %s", signer.Hex(), message.Address.Hex())
+	case message.Domain != a.config.Domain:
+		return "", session{}, unauthenticated("message domain %q does not match %q", message.Domain, a.config.Domain)
+	case !uriOnDomain(message.URI, a.config.Domain):
+		return "", session{}, unauthenticated("message URI %q is not on domain %q", message.URI, a.config.Domain)
+	case message.Version != "1":
+		return "", session{}, unauthenticated("unsupported SIWE version %q", message.Version)
+	case message.ChainID != chainID:
+		return "", session{}, unauthenticated("message chain ID %d does not match %d", message.ChainID, chainID)
+	case message.IssuedAt.After(now.Add(issuedAtSkew)):
+		return "", session{}, unauthenticated("message is issued in the future")
+	case !message.ExpirationTime.IsZero() && !now.Before(message.ExpirationTime):
+		return "", session{}, unauthenticated("message has expired")
+	case !message.NotBefore.IsZero() && now.Before(message.NotBefore):
+		return "", session{}, unauthenticated("message is not valid yet")
+	}
+
+	token, err := randomToken(32)
+	if err != nil {
+		return "", session{}, err
+	}
+	sess := session{address: message.Address, expiresAt: now.Add(a.config.SessionTTL)}
+	if !message.ExpirationTime.IsZero() && message.ExpirationTime.Before(sess.expiresAt) {
+		sess.expiresAt = message.ExpirationTime
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.prune(now)
+	// Nonce dipakai sekali, dicek paling akhir supaya pesan yang salah
+	// tidak menghabiskan nonce
+	if _, ok := a.nonces[message.Nonce]; !ok {
+		return "", session{}, unauthenticated("unknown or expired nonce")
+	}
+	delete(a.nonces, message.Nonce)
+	a.sessions[token] = sess
+	return token, sess, nil
+}
+
+// session dari token, false kalau tidak ada atau kedaluwarsa
+func (a *authenticator) session(token string, now time.Time) (session, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	sess, ok := a.sessions[token]
+	if !ok || !now.Before(sess.expiresAt) {
+		delete(a.sessions, token)
+		return session{}, false
+	}
+	return sess, true
+}
+
+func (a *authenticator) logout(token string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.sessions, token)
+}
+
+// prune buang nonce dan session kedaluwarsa. Caller harus pegang lock.
+func (a *authenticator) prune(now time.Time) {
+	for nonce, expiresAt := range a.nonces {
+		if !now.Before(expiresAt) {
+			delete(a.nonces, nonce)
+		}
+	}
+	for token, sess := range a.sessions {
+		if !now.Before(sess.expiresAt) {
+			delete(a.sessions, token)
+		}
+	}
+}
+
+// uriOnDomain true kalau URI pesan SIWE absolut dan authority-nya domain
+func uriOnDomain(uri, domain string) bool {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme == "" {
+		return false
+	}
+	return strings.EqualFold(parsed.Host, domain)
+}
+
+func randomToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// callerKey key context untuk address session
+type callerKey struct{}
+
+func withCaller(ctx context.Context, address common.Address) context.Context {
+	return context.WithValue(ctx, callerKey{}, address)
+}
+
+// caller address session di ctx
+func caller(ctx context.Context) (common.Address, bool) {
+	address, ok := ctx.Value(callerKey{}).(common.Address)
+	return address, ok
+}
+
+// bearerToken token dari header Authorization: Bearer <token>
+func bearerToken(r *http.Request) string {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// authenticate memasukkan address session ke context request. Request
+// tanpa token tetap diteruskan; token yang tidak valid ditolak.
+func (s *Server) authenticate(r *http.Request) (*http.Request, error) {
+	if s.auth == nil {
+		return r, nil
+	}
+	token := bearerToken(r)
+	if token == "" {
+		return r, nil
+	}
+	sess, ok := s.auth.session(token, time.Now())
+	if !ok {
+		return nil, unauthenticated("invalid or expired session token")
+	}
+	return r.WithContext(withCaller(r.Context(), sess.address)), nil
+}
+
+// checkAccess cek level akses route untuk caller di ctx
+func (s *Server) checkAccess(ctx context.Context, level access) error {
+	if s.auth == nil || level == accessPublic {
+		return nil
+	}
+	address, ok := caller(ctx)
+	if !ok {
+		return unauthenticated("sign in with Ethereum first (POST /auth/login)")
+	}
+	if level == accessOperator {
+		return s.requireOperator(address)
+	}
+	return nil
+}
+
+// requireOperator address ada di AuthConfig.Operators atau owner contract
+func (s *Server) requireOperator(address common.Address) error {
+	operator, err := s.isOperator(address)
+	if err != nil {
+		return err
+	}
+	if !operator {
+		return forbidden("%s is not an operator", address.Hex())
+	}
+	return nil
+}
+
+// isOperator address ada di AuthConfig.Operators atau owner contract
+func (s *Server) isOperator(address common.Address) (bool, error) {
+	if slices.Contains(s.auth.config.Operators, address) {
+		return true, nil
+	}
+	owner, err := s.service.GetOwner()
+	if err != nil {
+		return false, err
+	}
+	return address == owner, nil
+}
+
+// authorizeSender address pengirim tx saat auth aktif: from harus kosong
+// atau sama dengan address session
+func (s *Server) authorizeSender(ctx context.Context, from *common.Address) (*common.Address, error) {
+	if s.auth == nil {
+		return from, nil
+	}
+	address, ok := caller(ctx)
+	if !ok {
+		return nil, unauthenticated("sign in with Ethereum first (POST /auth/login)")
+	}
+	if from != nil && *from != address {
+		return nil, forbidden("from %s does not match the signed-in address %s", from.Hex(), address.Hex())
+	}
+	return &address, nil
+}
+
+// NonceResponse hasil GET /auth/nonce, bahan pesan SIWE
+type NonceResponse struct {
+	Nonce     string    `json:"nonce"`
+	Domain    string    `json:"domain"`
+	ChainID   uint64    `json:"chainId"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// LoginRequest body POST /auth/login
+type LoginRequest struct {
+	Message   string        `json:"message"`   // Pesan EIP-4361 persis seperti yang ditandatangani
+	Signature hexutil.Bytes `json:"signature"` // Hasil personal_sign, 65 byte
+}
+
+// SessionResponse hasil POST /auth/login dan GET /auth/session
+type SessionResponse struct {
+	Token     string         `json:"token,omitempty"` // Kirim sebagai Authorization: Bearer <token>
+	Address   common.Address `json:"address"`
+	Operator  bool           `json:"operator"`
+	ExpiresAt time.Time      `json:"expiresAt"`
+}
+
+// UpdateFeeRequest body POST /admin/fee
+type UpdateFeeRequest struct {
+	FeeBps uint16          `json:"feeBps"`
+	From   *common.Address `json:"from,omitempty"` // Default: address session
+}
+
+// UpdateTreasuryRequest body POST /admin/treasury
+type UpdateTreasuryRequest struct {
+	Treasury common.Address  `json:"treasury"`
+	From     *common.Address `json:"from,omitempty"` // Default: address session
+}
+
+// authRoutes endpoint login dan admin, hanya kalau WithAuth dipakai
+func (s *Server) authRoutes() []route {
+	return []route{
+		{
+			method:   http.MethodGet,
+			path:     "/auth/nonce",
+			summary:  "Issue a single-use nonce for a Sign-In With Ethereum (EIP-4361) message",
+			response: NonceResponse{},
+			handle:   s.handleNonce,
+		},
+		{
+			method:   http.MethodPost,
+			path:     "/auth/login",
+			summary:  "Verify a signed SIWE message and start a session",
+			request:  LoginRequest{},
+			response: SessionResponse{},
+			handle:   s.handleLogin,
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/auth/session",
+			summary:  "Read the current session",
+			response: SessionResponse{},
+			access:   accessSession,
+			handle:   s.handleSession,
+		},
+		{
+			method:   http.MethodPost,
+			path:     "/auth/logout",
+			summary:  "End the current session",
+			response: map[string]interface{}{},
+			access:   accessSession,
+			handle:   s.handleLogout,
+		},
+		{
+			method:   http.MethodPost,
+			path:     "/admin/fee",
+			summary:  "Update the contract fee (operators only)",
+			request:  UpdateFeeRequest{},
+			response: WriteResponse{},
+			access:   accessOperator,
+			handle:   s.handleUpdateFee,
+		},
+		{
+			method:   http.MethodPost,
+			path:     "/admin/treasury",
+			summary:  "Update the fee treasury (operators only)",
+			request:  UpdateTreasuryRequest{},
+			response: WriteResponse{},
+			access:   accessOperator,
+			handle:   s.handleUpdateTreasury,
+		},
+	}
+}
+
+// chainID chain ID service untuk pesan SIWE
+func (s *Server) chainID() uint64 {
+	if s.service.ChainID == nil {
+		return 0
+	}
+	return s.service.ChainID.Uint64()
+}
+
+func (s *Server) handleNonce(r *http.Request) (int, interface{}, error) {
+	nonce, expiresAt, err := s.auth.issueNonce(time.Now())
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, &NonceResponse{
+		Nonce:     nonce,
+		Domain:    s.auth.config.Domain,
+		ChainID:   s.chainID(),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *Server) handleLogin(r *http.Request) (int, interface{}, error) {
+	var req LoginRequest
+	if err := decodeBody(r, &req); err != nil {
+		return 0, nil, err
+	}
+	token, sess, err := s.auth.login(req.Message, req.Signature, s.chainID(), time.Now())
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, &SessionResponse{
+		Token:     token,
+		Address:   sess.address,
+		Operator:  s.requireOperator(sess.address) == nil,
+		ExpiresAt: sess.expiresAt,
+	}, nil
+}
+
+func (s *Server) handleSession(r *http.Request) (int, interface{}, error) {
+	sess, _ := s.auth.session(bearerToken(r), time.Now())
+	return http.StatusOK, &SessionResponse{
+		Address:   sess.address,
+		Operator:  s.requireOperator(sess.address) == nil,
+		ExpiresAt: sess.expiresAt,
+	}, nil
+}
+
+func (s *Server) handleLogout(r *http.Request) (int, interface{}, error) {
+	s.auth.logout(bearerToken(r))
+	return http.StatusOK, map[string]interface{}{}, nil
+}
+
+func (s *Server) handleUpdateFee(r *http.Request) (int, interface{}, error) {
+	var req UpdateFeeRequest
+	if err := decodeBody(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.FeeBps > redenvelope.MaxFeeBps {
+		return 0, nil, badRequest("feeBps %d exceeds %d", req.FeeBps, redenvelope.MaxFeeBps)
+	}
+	resp, err := s.write(r.Context(), req.From,
+		func(service *redenvelope.RedEnvelopeService) (*redenvelope.OfflineTx, error) {
+			return service.BuildOfflineUpdateFeeBps(req.FeeBps)
+		},
+		func() (*types.Transaction, error) {
+			return s.service.UpdateFeeBps(req.FeeBps)
+		})
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, resp, nil
+}
+
+func (s *Server) handleUpdateTreasury(r *http.Request) (int, interface{}, error) {
+	var req UpdateTreasuryRequest
+	if err := decodeBody(r, &req); err != nil {
+		return 0, nil, err
+	}
+	if req.Treasury == (common.Address{}) {
+		return 0, nil, badRequest("treasury must not be the zero address")
+	}
+	resp, err := s.write(r.Context(), req.From,
+		func(service *redenvelope.RedEnvelopeService) (*redenvelope.OfflineTx, error) {
+			return service.BuildOfflineUpdateTreasury(req.Treasury)
+		},
+		func() (*types.Transaction, error) {
+			return s.service.UpdateTreasury(req.Treasury)
+		})
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, resp, nil
+}
@@ -0,0 +1,354 @@
+package server
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+
+	"rpcsol/redenvelope"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// maxListLimit batas limit GET /envelopes
+const maxListLimit = 200
+
+// CreateEnvelopeRequest body POST /envelopes
+type CreateEnvelopeRequest struct {
+	From          *common.Address `json:"from,omitempty"` // Default: signer server
+	Kind          string          `json:"kind"`           // DIRECT_FIXED, GROUP_FIXED atau GROUP_RANDOM
+	Token         *common.Address `json:"token,omitempty"`
+	TotalClaims   uint32          `json:"totalClaims"`
+	Amount        string          `json:"amount"` // Wei; per klaim untuk GROUP_FIXED, total pot untuk kind lain
+	ExpirySeconds uint64          `json:"expirySeconds"`
+	RoomID        string          `json:"roomId,omitempty"` // Di-hash menjadi roomIdHash
+	Recipient     *common.Address `json:"recipient,omitempty"`
+}
+
+// SenderRequest body POST claim / refund
+type SenderRequest struct {
+	From *common.Address `json:"from,omitempty"` // Default: signer server
+}
+
+// WriteResponse hasil endpoint write. Kalau server mengirim tx sendiri,
+// transaction dan receipt terisi; kalau tidak, unsignedTx harus
+// ditandatangani client lalu dikirim ke POST /transactions.
+type WriteResponse struct {
+	Transaction *redenvelope.TxJSON      `json:"transaction,omitempty"`
+	Receipt     *redenvelope.ReceiptJSON `json:"receipt,omitempty"`
+	EnvelopeID  string                   `json:"envelopeId,omitempty"`
+	UnsignedTx  *redenvelope.OfflineTx   `json:"unsignedTx,omitempty"`
+}
+
+// ClaimStatusResponse hasil GET /envelopes/{id}/claims/{address}
+type ClaimStatusResponse struct {
+	EnvelopeID string         `json:"envelopeId"`
+	Address    common.Address `json:"address"`
+	Claimed    bool           `json:"claimed"`
+}
+
+// ListEnvelopesResponse hasil GET /envelopes
+type ListEnvelopesResponse struct {
+	Envelopes []*redenvelope.IndexedEnvelopeJSON `json:"envelopes"`
+	Total     int                                `json:"total"`
+	Offset    int                                `json:"offset"`
+	Limit     int                                `json:"limit"`
+	LastBlock uint64                             `json:"lastBlock"` // Block terakhir yang sudah di-index
+}
+
+// envelopeRoutes semua endpoint envelope
+func (s *Server) envelopeRoutes() []route {
+	idParam := param{name: "id", in: "path", description: "Envelope ID", required: true}
+
+	routes := []route{
+		{
+			method:   http.MethodPost,
+			path:     "/envelopes",
+			summary:  "Create an envelope",
+			request:  CreateEnvelopeRequest{},
+			response: WriteResponse{},
+			status:   http.StatusCreated,
+			handle:   s.handleCreate,
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/envelopes/{id}",
+			summary:  "Read an envelope from the contract",
+			params:   []param{idParam},
+			response: redenvelope.EnvelopeJSON{},
+			handle:   s.handleGet,
+		},
+		{
+			method:   http.MethodPost,
+			path:     "/envelopes/{id}/claim",
+			summary:  "Claim an envelope",
+			params:   []param{idParam},
+			request:  SenderRequest{},
+			response: WriteResponse{},
+			handle:   s.handleClaim,
+		},
+		{
+			method:   http.MethodPost,
+			path:     "/envelopes/{id}/refund",
+			summary:  "Refund an expired envelope",
+			params:   []param{idParam},
+			request:  SenderRequest{},
+			response: WriteResponse{},
+			handle:   s.handleRefund,
+		},
+		{
+			method:  http.MethodGet,
+			path:    "/envelopes/{id}/claims/{address}",
+			summary: "Check whether an address has claimed an envelope",
+			params: []param{
+				idParam,
+				{name: "address", in: "path", description: "Claimer address", required: true},
+			},
+			response: ClaimStatusResponse{},
+			handle:   s.handleClaimStatus,
+		},
+		{
+			method:  http.MethodGet,
+			path:    "/fees/quote",
+			summary: "Quote the fee for an envelope with the current feeBps",
+			params: []param{
+				{name: "kind", in: "query", description: "DIRECT_FIXED, GROUP_FIXED or GROUP_RANDOM", required: true},
+				{name: "totalClaims", in: "query", description: "Total claims", required: true},
+				{name: "amount", in: "query", description: "Amount in wei, same meaning as in create", required: true},
+			},
+			response: redenvelope.FeeQuoteJSON{},
+			handle:   s.handleQuote,
+		},
+		{
+			method:   http.MethodPost,
+			path:     "/transactions",
+			summary:  "Broadcast an unsignedTx from a write endpoint after the client signed it (rawTx filled in)",
+			request:  redenvelope.OfflineTx{},
+			response: WriteResponse{},
+			handle:   s.handleBroadcast,
+		},
+	}
+
+	if s.indexer != nil {
+		routes = append(routes, route{
+			method:  http.MethodGet,
+			path:    "/envelopes",
+			summary: "List envelopes from the event index, newest first",
+			params: []param{
+				{name: "creator", in: "query", description: "Filter by creator address"},
+				{name: "token", in: "query", description: "Filter by token address (zero address for native)"},
+				{name: "roomId", in: "query", description: "Filter by room ID (hashed like in create)"},
+				{name: "roomIdHash", in: "query", description: "Filter by room ID hash"},
+				{name: "status", in: "query", description: "active, empty, expired or refunded"},
+				{name: "offset", in: "query", description: "Number of envelopes to skip"},
+				{name: "limit", in: "query", description: "Page size, at most 200"},
+			},
+			response: ListEnvelopesResponse{},
+			handle:   s.handleList,
+		}, s.eventsRoute())
+	}
+	return routes
+}
+
+func (s *Server) handleCreate(r *http.Request) (int, interface{}, error) {
+	var req CreateEnvelopeRequest
+	if err := decodeBody(r, &req); err != nil {
+		return 0, nil, err
+	}
+	resp, err := s.createEnvelope(r.Context(), &req)
+	if err != nil {
+		return 0, nil, err
+	}
+	if resp.UnsignedTx != nil {
+		return http.StatusOK, resp, nil
+	}
+	return http.StatusCreated, resp, nil
+}
+
+func (s *Server) handleClaim(r *http.Request) (int, interface{}, error) {
+	return s.handleEnvelopeWrite(r, s.claimEnvelope)
+}
+
+func (s *Server) handleRefund(r *http.Request) (int, interface{}, error) {
+	return s.handleEnvelopeWrite(r, s.refundEnvelope)
+}
+
+// handleEnvelopeWrite claim / refund dengan body SenderRequest opsional
+func (s *Server) handleEnvelopeWrite(r *http.Request, write func(context.Context, *big.Int, *common.Address) (*WriteResponse, error)) (int, interface{}, error) {
+	id, err := pathEnvelopeID(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	var req SenderRequest
+	if r.ContentLength != 0 {
+		if err := decodeBody(r, &req); err != nil {
+			return 0, nil, err
+		}
+	}
+	resp, err := write(r.Context(), id, req.From)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, resp, nil
+}
+
+func (s *Server) handleBroadcast(r *http.Request) (int, interface{}, error) {
+	var offline redenvelope.OfflineTx
+	if err := decodeBody(r, &offline); err != nil {
+		return 0, nil, err
+	}
+	resp, err := s.broadcast(r.Context(), &offline)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, resp, nil
+}
+
+func (s *Server) handleGet(r *http.Request) (int, interface{}, error) {
+	id, err := pathEnvelopeID(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	envelope, err := s.getEnvelope(id)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, envelope, nil
+}
+
+func (s *Server) handleClaimStatus(r *http.Request) (int, interface{}, error) {
+	id, err := pathEnvelopeID(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	user, err := parseAddress("address", r.PathValue("address"))
+	if err != nil {
+		return 0, nil, err
+	}
+	claimed, err := s.service.HasClaimed(id, user)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, &ClaimStatusResponse{EnvelopeID: id.String(), Address: user, Claimed: claimed}, nil
+}
+
+func (s *Server) handleQuote(r *http.Request) (int, interface{}, error) {
+	query := r.URL.Query()
+	totalClaims, err := strconv.ParseUint(query.Get("totalClaims"), 10, 32)
+	if err != nil {
+		return 0, nil, badRequest("invalid totalClaims %q", query.Get("totalClaims"))
+	}
+	quote, err := s.quote(query.Get("kind"), uint32(totalClaims), query.Get("amount"))
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, quote, nil
+}
+
+func (s *Server) handleList(r *http.Request) (int, interface{}, error) {
+	query := r.URL.Query()
+	var filter redenvelope.IndexFilter
+
+	for name, target := range map[string]**common.Address{"creator": &filter.Creator, "token": &filter.Token} {
+		if value := query.Get(name); value != "" {
+			address, err := parseAddress(name, value)
+			if err != nil {
+				return 0, nil, err
+			}
+			*target = &address
+		}
+	}
+	if value := query.Get("roomIdHash"); value != "" {
+		hash, err := parseHash("roomIdHash", value)
+		if err != nil {
+			return 0, nil, err
+		}
+		filter.RoomIdHash = &hash
+	} else if value := query.Get("roomId"); value != "" {
+		hash := common.Hash(redenvelope.GenerateRoomIdHash(value))
+		filter.RoomIdHash = &hash
+	}
+	switch status := query.Get("status"); status {
+	case "", redenvelope.EnvelopeStatusActive, redenvelope.EnvelopeStatusEmpty,
+		redenvelope.EnvelopeStatusExpired, redenvelope.EnvelopeStatusRefunded:
+		filter.Status = status
+	default:
+		return 0, nil, badRequest("invalid status %q", status)
+	}
+
+	var err error
+	if filter.Offset, err = queryInt(query.Get("offset"), 0); err != nil {
+		return 0, nil, badRequest("invalid offset: %v", err)
+	}
+	if filter.Limit, err = queryInt(query.Get("limit"), redenvelope.DefaultIndexLimit); err != nil {
+		return 0, nil, badRequest("invalid limit: %v", err)
+	}
+	filter.Limit = min(max(filter.Limit, 1), maxListLimit)
+
+	envelopes, total := s.indexer.List(filter)
+	lastBlock, _ := s.indexer.LastBlock()
+	now := time.Now()
+	resp := &ListEnvelopesResponse{
+		Envelopes: make([]*redenvelope.IndexedEnvelopeJSON, 0, len(envelopes)),
+		Total:     total,
+		Offset:    filter.Offset,
+		Limit:     filter.Limit,
+		LastBlock: lastBlock,
+	}
+	for _, envelope := range envelopes {
+		resp.Envelopes = append(resp.Envelopes, redenvelope.NewIndexedEnvelopeJSON(envelope, now))
+	}
+	return http.StatusOK, resp, nil
+}
+
+func pathEnvelopeID(r *http.Request) (*big.Int, error) {
+	return parseEnvelopeID(r.PathValue("id"))
+}
+
+func parseEnvelopeID(value string) (*big.Int, error) {
+	id, ok := new(big.Int).SetString(value, 10)
+	if !ok || id.Sign() < 0 {
+		return nil, badRequest("invalid envelope ID %q", value)
+	}
+	return id, nil
+}
+
+func parseWei(name, value string) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() <= 0 {
+		return nil, badRequest("invalid %s %q, expected a positive wei amount", name, value)
+	}
+	return amount, nil
+}
+
+func parseAddress(name, value string) (common.Address, error) {
+	if !common.IsHexAddress(value) {
+		return common.Address{}, badRequest("invalid %s address %q", name, value)
+	}
+	return common.HexToAddress(value), nil
+}
+
+func parseHash(name, value string) (common.Hash, error) {
+	data, err := hexutil.Decode(value)
+	if err != nil || len(data) != common.HashLength {
+		return common.Hash{}, badRequest("invalid %s %q", name, value)
+	}
+	return common.BytesToHash(data), nil
+}
+
+func addressOrZero(address *common.Address) common.Address {
+	if address == nil {
+		return common.Address{}
+	}
+	return *address
+}
+
+func queryInt(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
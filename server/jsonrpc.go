@@ -0,0 +1,250 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"rpcsol/redenvelope"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// rpcNamespace namespace method JSON-RPC (redenvelope_create, ...)
+const rpcNamespace = "redenvelope"
+
+// Code error JSON-RPC. Data error selalu ErrorJSON.
+const (
+	rpcCodeInvalidParams = -32602 // Argumen tidak valid
+	rpcCodeReverted      = 3      // Revert contract, sama seperti eth_call
+	rpcCodeServer        = -32000 // Error lain
+)
+
+// WithJSONRPC memasang gateway JSON-RPC 2.0 di /rpc, lewat HTTP POST dan
+// websocket. origins dipakai untuk cek Origin websocket ("*" untuk semua).
+// redenvelope_subscribe hanya tersedia lewat websocket dan butuh
+// WithIndexer.
+func WithJSONRPC(origins ...string) Option {
+	return func(s *Server) error {
+		rpcServer := rpc.NewServer()
+		rpcServer.SetHTTPBodyLimit(maxBodyBytes)
+		if err := rpcServer.RegisterName(rpcNamespace, &rpcAPI{srv: s}); err != nil {
+			return err
+		}
+		s.rpc = rpcServer
+		s.rpcOrigins = origins
+		return nil
+	}
+}
+
+// rpcHandler HTTP dan websocket di satu path. Token session dibaca dari
+// header Authorization, untuk websocket saat handshake.
+func (s *Server) rpcHandler() http.Handler {
+	ws := s.rpc.WebsocketHandler(s.rpcOrigins)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r, err := s.authenticate(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			if _, ok := caller(r.Context()); ok {
+				s.serveSessionWebsocket(w, r, bearerToken(r))
+				return
+			}
+			ws.ServeHTTP(w, r)
+			return
+		}
+		s.rpc.ServeHTTP(w, r)
+	})
+}
+
+// serveSessionWebsocket melayani websocket yang login dengan rpc.Server
+// sendiri, karena context request HTTP tidak sampai ke method yang
+// dipanggil lewat websocket. Token dicek ulang di setiap panggilan write.
+func (s *Server) serveSessionWebsocket(w http.ResponseWriter, r *http.Request, token string) {
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName(rpcNamespace, &rpcAPI{srv: s, token: token}); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.rpcMu.Lock()
+	if s.rpcSessions == nil {
+		s.rpcSessions = make(map[*rpc.Server]struct{})
+	}
+	s.rpcSessions[rpcServer] = struct{}{}
+	s.rpcMu.Unlock()
+	defer func() {
+		s.rpcMu.Lock()
+		delete(s.rpcSessions, rpcServer)
+		s.rpcMu.Unlock()
+		rpcServer.Stop()
+	}()
+
+	// Blok sampai koneksi websocket ditutup
+	rpcServer.WebsocketHandler(s.rpcOrigins).ServeHTTP(w, r)
+}
+
+// rpcError error JSON-RPC dengan ErrorJSON sebagai data
+type rpcError struct {
+	code int
+	data *redenvelope.ErrorJSON
+}
+
+func (e *rpcError) Error() string          { return e.data.Message }
+func (e *rpcError) ErrorCode() int         { return e.code }
+func (e *rpcError) ErrorData() interface{} { return e.data }
+
+// toRPCError memetakan error operasi ke code JSON-RPC
+func toRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+	_, body := errorResponse(err)
+
+	code := rpcCodeServer
+	var contractErr *redenvelope.ContractError
+	switch {
+	case body.Error.Code == redenvelope.ErrorCodeInvalidArgument:
+		code = rpcCodeInvalidParams
+	case errors.As(err, &contractErr):
+		code = rpcCodeReverted
+	}
+	return &rpcError{code: code, data: body.Error}
+}
+
+// rpcAPI method namespace redenvelope. Argumen dan hasil memakai tipe JSON
+// yang sama dengan REST API.
+type rpcAPI struct {
+	srv   *Server
+	token string // Token session websocket, kosong untuk HTTP
+}
+
+// context menambahkan address session websocket ke ctx. Untuk HTTP,
+// address sudah ada di ctx dari rpcHandler.
+func (api *rpcAPI) context(ctx context.Context) (context.Context, error) {
+	if api.token == "" {
+		return ctx, nil
+	}
+	sess, ok := api.srv.auth.session(api.token, time.Now())
+	if !ok {
+		return nil, toRPCError(unauthenticated("invalid or expired session token"))
+	}
+	return withCaller(ctx, sess.address), nil
+}
+
+// Create redenvelope_create, sama seperti POST /envelopes
+func (api *rpcAPI) Create(ctx context.Context, req CreateEnvelopeRequest) (*WriteResponse, error) {
+	ctx, err := api.context(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := api.srv.createEnvelope(ctx, &req)
+	return resp, toRPCError(err)
+}
+
+// Claim redenvelope_claim(id, from?)
+func (api *rpcAPI) Claim(ctx context.Context, id string, from *common.Address) (*WriteResponse, error) {
+	return api.write(ctx, id, from, api.srv.claimEnvelope)
+}
+
+// Refund redenvelope_refund(id, from?)
+func (api *rpcAPI) Refund(ctx context.Context, id string, from *common.Address) (*WriteResponse, error) {
+	return api.write(ctx, id, from, api.srv.refundEnvelope)
+}
+
+func (api *rpcAPI) write(ctx context.Context, id string, from *common.Address, write func(context.Context, *big.Int, *common.Address) (*WriteResponse, error)) (*WriteResponse, error) {
+	ctx, err := api.context(ctx)
+	if err != nil {
+		return nil, err
+	}
+	envelopeID, err := parseEnvelopeID(id)
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+	resp, err := write(ctx, envelopeID, from)
+	return resp, toRPCError(err)
+}
+
+// SendTransaction redenvelope_sendTransaction, sama seperti POST /transactions
+func (api *rpcAPI) SendTransaction(ctx context.Context, offline redenvelope.OfflineTx) (*WriteResponse, error) {
+	ctx, err := api.context(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := api.srv.broadcast(ctx, &offline)
+	return resp, toRPCError(err)
+}
+
+// Get redenvelope_get(id)
+func (api *rpcAPI) Get(id string) (*redenvelope.EnvelopeJSON, error) {
+	envelopeID, err := parseEnvelopeID(id)
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+	envelope, err := api.srv.getEnvelope(envelopeID)
+	return envelope, toRPCError(err)
+}
+
+// Quote redenvelope_quote(kind, totalClaims, amount)
+func (api *rpcAPI) Quote(kind string, totalClaims uint32, amount string) (*redenvelope.FeeQuoteJSON, error) {
+	quote, err := api.srv.quote(kind, totalClaims, amount)
+	return quote, toRPCError(err)
+}
+
+// rpcEventBuffer buffer event per subscription. Client yang tertinggal lebih
+// dari ini menerima notifikasi ErrorResponse terakhir (code
+// SubscriptionDropped) dan harus subscribe ulang.
+const rpcEventBuffer = 128
+
+// Events redenvelope_subscribe("events", filter?): notifikasi EventJSON
+// untuk setiap event baru di index yang cocok dengan filter
+func (api *rpcAPI) Events(ctx context.Context, params *redenvelope.EventFilterJSON) (*rpc.Subscription, error) {
+	indexer := api.srv.indexer
+	if indexer == nil {
+		return nil, &rpcError{code: rpcCodeServer, data: &redenvelope.ErrorJSON{
+			Code:    redenvelope.ErrorCodeInternal,
+			Message: "event subscriptions require the event index",
+		}}
+	}
+	filter, err := eventFilter(params)
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+
+	sub := notifier.CreateSubscription()
+	events, feedSub := indexer.SubscribeEventsBuffered(rpcEventBuffer)
+	go func() {
+		defer feedSub.Unsubscribe()
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					// Server tidak bisa menutup subscription, jadi client
+					// diberi tahu lewat notifikasi terakhir
+					notifier.Notify(sub.ID, &ErrorResponse{Error: &redenvelope.ErrorJSON{
+						Code:    redenvelope.ErrorCodeSubscriptionDropped,
+						Message: fmt.Sprintf("subscriber fell behind by more than %d events, resubscribe", rpcEventBuffer),
+					}})
+					return
+				}
+				if indexer.Match(filter, event) {
+					notifier.Notify(sub.ID, redenvelope.NewEventJSON(event))
+				}
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"net/http"
+
+	"rpcsol/metrics"
+)
+
+// WithMetrics memasang GET /metrics dengan isi reg dalam format teks
+// Prometheus. Metric service didaftarkan lewat redenvelope.WithMetrics.
+func WithMetrics(reg *metrics.Registry) Option {
+	return func(s *Server) error {
+		s.metrics = reg
+		return nil
+	}
+}
+
+// metricsRoute GET /metrics
+func (s *Server) metricsRoute() route {
+	handler := s.metrics.Handler()
+	return route{
+		method:      http.MethodGet,
+		path:        "/metrics",
+		summary:     "Prometheus metrics in text exposition format",
+		response:    "",
+		contentType: metrics.ContentType,
+		stream:      handler.ServeHTTP,
+	}
+}
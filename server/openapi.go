@@ -0,0 +1,206 @@
+package server
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// openAPIVersion versi spesifikasi OpenAPI yang dihasilkan
+const openAPIVersion = "3.0.3"
+
+// pathParamPattern {name} di path route
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// OpenAPI dokumen OpenAPI 3 yang dibangun dari tabel route: path, parameter,
+// body request dan response (schema dari tipe Go lewat reflection).
+func (s *Server) OpenAPI() map[string]interface{} {
+	schemas := make(map[string]interface{})
+	paths := make(map[string]interface{})
+
+	for _, rt := range s.routes {
+		operation := map[string]interface{}{
+			"summary":     rt.summary,
+			"operationId": operationID(rt),
+		}
+
+		var params []interface{}
+		for _, p := range rt.params {
+			params = append(params, map[string]interface{}{
+				"name":        p.name,
+				"in":          p.in,
+				"description": p.description,
+				"required":    p.required,
+				"schema":      map[string]interface{}{"type": "string"},
+			})
+		}
+		if params != nil {
+			operation["parameters"] = params
+		}
+
+		if rt.request != nil {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": schemaFor(reflect.TypeOf(rt.request), schemas)},
+				},
+			}
+		}
+
+		status := rt.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		contentType := rt.contentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		operation["responses"] = map[string]interface{}{
+			fmt.Sprint(status): map[string]interface{}{
+				"description": http.StatusText(status),
+				"content": map[string]interface{}{
+					contentType: map[string]interface{}{"schema": schemaFor(reflect.TypeOf(rt.response), schemas)},
+				},
+			},
+			"default": map[string]interface{}{
+				"description": "Error, see the error code table in info.description",
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": schemaFor(reflect.TypeOf(ErrorResponse{}), schemas)},
+				},
+			},
+		}
+
+		item, ok := paths[rt.path].(map[string]interface{})
+		if !ok {
+			item = make(map[string]interface{})
+			paths[rt.path] = item
+		}
+		item[strings.ToLower(rt.method)] = operation
+	}
+
+	return map[string]interface{}{
+		"openapi": openAPIVersion,
+		"info": map[string]interface{}{
+			"title":       "RedEnvelope API",
+			"version":     "1.0.0",
+			"description": errorTable(),
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": schemas},
+	}
+}
+
+// operationID nama operasi dari method dan path, misalnya postEnvelopesIdClaim
+func operationID(rt route) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(rt.method))
+	for _, part := range strings.FieldsFunc(rt.path, func(r rune) bool { return strings.ContainsRune("/{}.", r) }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// errorTable tabel code error -> HTTP status untuk deskripsi dokumen
+func errorTable() string {
+	codes := make([]string, 0, len(errorStatus))
+	for code := range errorStatus {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	var b strings.Builder
+	b.WriteString("Errors are returned as {\"error\": {\"code\", \"message\"}}.\n\n| code | HTTP status |\n|---|---|\n")
+	for _, code := range codes {
+		fmt.Fprintf(&b, "| %s | %d |\n", code, errorStatus[code])
+	}
+	return b.String()
+}
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	addressType       = reflect.TypeOf(common.Address{})
+	hashType          = reflect.TypeOf(common.Hash{})
+)
+
+// schemaFor schema JSON untuk tipe Go. Struct dimasukkan ke components dan
+// direferensikan dengan $ref.
+func schemaFor(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case addressType:
+		return map[string]interface{}{"type": "string", "pattern": "^0x[0-9a-fA-F]{40}$"}
+	case hashType:
+		return map[string]interface{}{"type": "string", "pattern": "^0x[0-9a-fA-F]{64}$"}
+	}
+	// Tipe hex go-ethereum (hexutil.Big, hexutil.Bytes, ...) di-encode sebagai string
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return map[string]interface{}{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem(), schemas)}
+	case reflect.Struct:
+		name := t.Name()
+		if name == "" {
+			return structSchema(t, schemas)
+		}
+		if _, ok := schemas[name]; !ok {
+			schemas[name] = nil // Placeholder untuk tipe rekursif
+			schemas[name] = structSchema(t, schemas)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	}
+	return map[string]interface{}{}
+}
+
+// structSchema schema object dari field struct yang punya tag json. Field
+// dengan omitempty / omitzero tidak wajib.
+func structSchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = schemaFor(field.Type, schemas)
+		if !strings.Contains(options, "omitempty") && !strings.Contains(options, "omitzero") {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if required != nil {
+		schema["required"] = required
+	}
+	return schema
+}
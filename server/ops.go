@@ -0,0 +1,205 @@
+package server
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"rpcsol/redenvelope"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Operasi envelope yang dipakai REST dan JSON-RPC. Error input dikembalikan
+// sebagai badRequest, error lain apa adanya untuk dipetakan transport.
+
+// createEnvelope kirim createEnvelope, atau tx unsigned kalau bukan signer server
+func (s *Server) createEnvelope(ctx context.Context, req *CreateEnvelopeRequest) (*WriteResponse, error) {
+	kind, err := redenvelope.ParseKind(req.Kind)
+	if err != nil {
+		return nil, badRequest("%v", err)
+	}
+	amount, err := parseWei("amount", req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	if req.ExpirySeconds == 0 {
+		return nil, badRequest("expirySeconds is required")
+	}
+	token := addressOrZero(req.Token)
+	recipient := addressOrZero(req.Recipient)
+	expiry := time.Duration(req.ExpirySeconds) * time.Second
+	roomIdHash := redenvelope.EmptyRoomIdHash
+	if req.RoomID != "" {
+		roomIdHash = redenvelope.GenerateRoomIdHash(req.RoomID)
+	}
+
+	return s.write(ctx, req.From,
+		func(service *redenvelope.RedEnvelopeService) (*redenvelope.OfflineTx, error) {
+			return service.BuildOfflineCreate(kind, token, req.TotalClaims, amount, expiry, roomIdHash, recipient)
+		},
+		func() (*types.Transaction, error) {
+			return s.service.CreateEnvelope(kind, token, req.TotalClaims, amount, expiry, roomIdHash, recipient)
+		})
+}
+
+func (s *Server) claimEnvelope(ctx context.Context, id *big.Int, from *common.Address) (*WriteResponse, error) {
+	return s.write(ctx, from,
+		func(service *redenvelope.RedEnvelopeService) (*redenvelope.OfflineTx, error) {
+			return service.BuildOfflineClaim(id)
+		},
+		func() (*types.Transaction, error) {
+			return s.service.ClaimEnvelope(id)
+		})
+}
+
+// refundEnvelope refund; dengan auth hanya creator envelope yang boleh
+func (s *Server) refundEnvelope(ctx context.Context, id *big.Int, from *common.Address) (*WriteResponse, error) {
+	if s.auth != nil {
+		sender, err := s.authorizeSender(ctx, from)
+		if err != nil {
+			return nil, err
+		}
+		envelope, err := s.getEnvelope(id)
+		if err != nil {
+			return nil, err
+		}
+		if envelope.Creator != *sender {
+			return nil, forbidden("only the creator %s can refund envelope %s", envelope.Creator.Hex(), id)
+		}
+	}
+	return s.write(ctx, from,
+		func(service *redenvelope.RedEnvelopeService) (*redenvelope.OfflineTx, error) {
+			return service.BuildOfflineRefund(id)
+		},
+		func() (*types.Transaction, error) {
+			return s.service.RefundEnvelope(id)
+		})
+}
+
+// write kirim tx lewat send kalau pengirimnya signer server, atau
+// kembalikan tx unsigned dari build. Dengan auth, pengirim selalu address
+// session; tanpa auth server tidak pernah menandatangani.
+func (s *Server) write(
+	ctx context.Context,
+	from *common.Address,
+	build func(*redenvelope.RedEnvelopeService) (*redenvelope.OfflineTx, error),
+	send func() (*types.Transaction, error),
+) (*WriteResponse, error) {
+	from, err := s.authorizeSender(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	sender, serverSigned, err := s.sender(from)
+	if err != nil {
+		return nil, err
+	}
+	if serverSigned && s.auth == nil {
+		return nil, forbidden("server-signed transactions require a SIWE session (WithAuth); set from to get an unsigned tx")
+	}
+	if !serverSigned {
+		tx, err := build(s.service.WatchOnly(sender))
+		if err != nil {
+			return nil, err
+		}
+		return &WriteResponse{UnsignedTx: tx}, nil
+	}
+
+	s.sendMu.Lock()
+	tx, err := send()
+	s.sendMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return s.waitResponse(ctx, tx, sender)
+}
+
+// broadcast kirim OfflineTx yang sudah ditandatangani client
+func (s *Server) broadcast(ctx context.Context, offline *redenvelope.OfflineTx) (*WriteResponse, error) {
+	if offline.ChainID == nil || offline.GasPrice == nil || offline.Value == nil {
+		return nil, badRequest("transaction is missing chainId, gasPrice or value")
+	}
+	if !offline.Signed() {
+		return nil, badRequest("rawTx is required")
+	}
+	if err := redenvelope.VerifyOfflineTx(offline); err != nil {
+		return nil, badRequest("%v", err)
+	}
+	if _, err := s.authorizeSender(ctx, &offline.From); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.service.BroadcastOfflineTx(offline)
+	if err != nil {
+		return nil, err
+	}
+	return s.waitResponse(ctx, tx, offline.From)
+}
+
+// getEnvelope envelope dari contract, ErrEnvelopeNotFound kalau kosong
+func (s *Server) getEnvelope(id *big.Int) (*redenvelope.EnvelopeJSON, error) {
+	envelope, err := s.service.GetEnvelope(id)
+	if err != nil {
+		return nil, err
+	}
+	if envelope.Creator == (common.Address{}) {
+		return nil, redenvelope.ErrEnvelopeNotFound
+	}
+	return redenvelope.NewEnvelopeJSON(id, envelope), nil
+}
+
+// quote fee untuk kind (nama KindName) dan amount wei
+func (s *Server) quote(kindName string, totalClaims uint32, amountWei string) (*redenvelope.FeeQuoteJSON, error) {
+	kind, err := redenvelope.ParseKind(kindName)
+	if err != nil {
+		return nil, badRequest("%v", err)
+	}
+	amount, err := parseWei("amount", amountWei)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := s.service.QuoteFee(kind, totalClaims, amount)
+	if err != nil {
+		return nil, err
+	}
+	return redenvelope.NewFeeQuoteJSON(quote), nil
+}
+
+// sender address pengirim tx dan apakah server yang menandatangani
+func (s *Server) sender(from *common.Address) (common.Address, bool, error) {
+	_, watchOnly := s.service.Signer.(*redenvelope.WatchOnlySigner)
+	if from == nil || *from == s.service.Address {
+		if watchOnly {
+			return common.Address{}, false, badRequest("from is required, this server does not sign transactions")
+		}
+		return s.service.Address, true, nil
+	}
+	return *from, false, nil
+}
+
+// waitResponse menunggu receipt tx dan menyusun WriteResponse
+func (s *Server) waitResponse(ctx context.Context, tx *types.Transaction, from common.Address) (*WriteResponse, error) {
+	receipt, err := s.service.WaitMined(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.service.ReceiptError(tx, receipt); err != nil {
+		return nil, err
+	}
+	events, err := s.service.EventsFromReceipt(receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &WriteResponse{
+		Transaction: redenvelope.NewTxJSON(tx, from),
+		Receipt:     redenvelope.NewReceiptJSON(receipt, events),
+	}
+	for _, event := range events {
+		if event.Created != nil {
+			resp.EnvelopeID = event.EnvelopeID.String()
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,289 @@
+// Package server REST API (net/http) di atas RedEnvelopeService.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"rpcsol/metrics"
+	"rpcsol/redenvelope"
+	"rpcsol/webhook"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxBodyBytes batas ukuran body request
+const maxBodyBytes = 1 << 20
+
+// Server REST API RedEnvelope. Route didaftarkan dari tabel routes, dan
+// dokumen OpenAPI dibangun dari tabel yang sama.
+type Server struct {
+	service  *redenvelope.RedEnvelopeService
+	indexer  *redenvelope.Indexer
+	webhooks *webhook.Dispatcher
+	auth     *authenticator    // nil kalau tanpa WithAuth
+	metrics  *metrics.Registry // nil kalau tanpa WithMetrics
+
+	routes []route
+	mux    *http.ServeMux
+
+	rpc        *rpc.Server // Gateway JSON-RPC, nil kalau tidak dipasang
+	rpcOrigins []string
+
+	// rpcMu menjaga rpcSessions, rpc.Server per websocket yang login
+	rpcMu       sync.Mutex
+	rpcSessions map[*rpc.Server]struct{}
+
+	// sendMu menyerialkan tx yang ditandatangani server supaya nonce
+	// tidak bentrok antar request
+	sendMu sync.Mutex
+}
+
+// Option konfigurasi Server
+type Option func(*Server) error
+
+// WithIndexer mengaktifkan GET /envelopes dari index. Indexer harus
+// dijalankan sendiri (Indexer.Run).
+func WithIndexer(indexer *redenvelope.Indexer) Option {
+	return func(s *Server) error {
+		s.indexer = indexer
+		return nil
+	}
+}
+
+// New membuat Server. Transaksi dikirim dengan signer service; kalau
+// signer watch-only atau request meminta address lain, endpoint write
+// mengembalikan tx unsigned untuk ditandatangani client.
+func New(service *redenvelope.RedEnvelopeService, opts ...Option) (*Server, error) {
+	s := &Server{
+		service: service,
+		mux:     http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, err
+		}
+	}
+
+	s.routes = s.envelopeRoutes()
+	if s.webhooks != nil {
+		s.routes = append(s.routes, s.webhookRoutes()...)
+	}
+	if s.auth != nil {
+		s.routes = append(s.routes, s.authRoutes()...)
+	}
+	if s.metrics != nil {
+		s.routes = append(s.routes, s.metricsRoute())
+	}
+	s.routes = append(s.routes, route{
+		method:   http.MethodGet,
+		path:     "/openapi.json",
+		summary:  "OpenAPI document for this server",
+		response: map[string]interface{}{},
+		handle: func(r *http.Request) (int, interface{}, error) {
+			return http.StatusOK, s.OpenAPI(), nil
+		},
+	})
+
+	for _, rt := range s.routes {
+		s.mux.Handle(rt.method+" "+rt.path, s.wrap(rt))
+	}
+	if s.rpc != nil {
+		s.mux.Handle("/rpc", s.rpcHandler())
+	}
+	return s, nil
+}
+
+// Close menutup koneksi JSON-RPC (termasuk websocket dan subscription)
+func (s *Server) Close() {
+	if s.rpc != nil {
+		s.rpc.Stop()
+	}
+	s.rpcMu.Lock()
+	defer s.rpcMu.Unlock()
+	for rpcServer := range s.rpcSessions {
+		rpcServer.Stop()
+	}
+}
+
+// ServeHTTP implementasi http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// route satu endpoint beserta metadata untuk OpenAPI
+type route struct {
+	method  string
+	path    string // Pola http.ServeMux, {name} juga dipakai di OpenAPI
+	summary string
+	params  []param
+
+	request  interface{} // Contoh nilai body request, nil kalau tanpa body
+	response interface{} // Contoh nilai body response sukses
+	status   int         // Status sukses utama, default 200
+	access   access      // Dicek hanya kalau WithAuth dipakai
+
+	handle func(r *http.Request) (int, interface{}, error)
+
+	// stream menggantikan handle untuk response streaming (SSE).
+	// contentType response-nya, response tetap dipakai untuk schema.
+	stream      http.HandlerFunc
+	contentType string
+}
+
+// param parameter path / query
+type param struct {
+	name        string
+	in          string // "path" atau "query"
+	description string
+	required    bool
+}
+
+// wrap menjalankan handler dan menulis hasil atau error sebagai JSON
+func (s *Server) wrap(rt route) http.Handler {
+	if rt.stream != nil {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r, err := s.authenticate(r)
+			if err == nil {
+				err = s.checkAccess(r.Context(), rt.access)
+			}
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			rt.stream(w, r)
+		})
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+		r, err := s.authenticate(r)
+		if err == nil {
+			err = s.checkAccess(r.Context(), rt.access)
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		status, body, err := rt.handle(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, status, body)
+	})
+}
+
+// ErrorResponse body untuk semua response error
+type ErrorResponse struct {
+	Error *redenvelope.ErrorJSON `json:"error"`
+}
+
+// requestError request tidak valid, dikembalikan sebagai 400
+type requestError struct {
+	err error
+}
+
+func (e *requestError) Error() string { return e.err.Error() }
+func (e *requestError) Unwrap() error { return e.err }
+
+func badRequest(format string, args ...interface{}) error {
+	return &requestError{err: fmt.Errorf(format, args...)}
+}
+
+// codeError error server dengan code ErrorJSON tertentu (NotFound,
+// Unauthenticated, Forbidden, TooManyRequests)
+type codeError struct {
+	code string
+	err  error
+}
+
+func (e *codeError) Error() string { return e.err.Error() }
+func (e *codeError) Unwrap() error { return e.err }
+
+func notFound(err error) error {
+	return &codeError{code: redenvelope.ErrorCodeNotFound, err: err}
+}
+
+func unauthenticated(format string, args ...interface{}) error {
+	return &codeError{code: redenvelope.ErrorCodeUnauthenticated, err: fmt.Errorf(format, args...)}
+}
+
+func forbidden(format string, args ...interface{}) error {
+	return &codeError{code: redenvelope.ErrorCodeForbidden, err: fmt.Errorf(format, args...)}
+}
+
+func tooManyRequests(format string, args ...interface{}) error {
+	return &codeError{code: redenvelope.ErrorCodeTooManyRequests, err: fmt.Errorf(format, args...)}
+}
+
+// errorStatus HTTP status untuk setiap code ErrorJSON
+var errorStatus = map[string]int{
+	redenvelope.ErrAlreadyClaimed.Name:    http.StatusConflict,
+	redenvelope.ErrEnvelopeExpired.Name:   http.StatusGone,
+	redenvelope.ErrEnvelopeNotFound.Name:  http.StatusNotFound,
+	redenvelope.ErrInvalidParameters.Name: http.StatusUnprocessableEntity,
+	redenvelope.ErrNotEligible.Name:       http.StatusForbidden,
+	redenvelope.ErrTransferFailed.Name:    http.StatusBadGateway,
+	redenvelope.ErrUnauthorized.Name:      http.StatusForbidden,
+
+	redenvelope.ErrorCodeReverted:             http.StatusUnprocessableEntity,
+	redenvelope.ErrorCodeInsufficientFunds:    http.StatusPaymentRequired,
+	redenvelope.ErrorCodeChainIDMismatch:      http.StatusServiceUnavailable,
+	redenvelope.ErrorCodeGasPriceTooHigh:      http.StatusServiceUnavailable,
+	redenvelope.ErrorCodeIncompatibleContract: http.StatusServiceUnavailable,
+	redenvelope.ErrorCodeInvalidArgument:      http.StatusBadRequest,
+	redenvelope.ErrorCodeNotFound:             http.StatusNotFound,
+	redenvelope.ErrorCodeUnauthenticated:      http.StatusUnauthorized,
+	redenvelope.ErrorCodeForbidden:            http.StatusForbidden,
+	redenvelope.ErrorCodeTooManyRequests:      http.StatusTooManyRequests,
+	redenvelope.ErrorCodeInternal:             http.StatusInternalServerError,
+}
+
+// errorResponse status dan body untuk err
+func errorResponse(err error) (int, *ErrorResponse) {
+	out := redenvelope.NewErrorJSON(err)
+
+	var reqErr *requestError
+	var maxBytesErr *http.MaxBytesError
+	var codeErr *codeError
+	switch {
+	case errors.As(err, &reqErr) || errors.As(err, &maxBytesErr):
+		out.Code = redenvelope.ErrorCodeInvalidArgument
+	case errors.As(err, &codeErr):
+		out.Code = codeErr.code
+	}
+
+	status, ok := errorStatus[out.Code]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+	return status, &ErrorResponse{Error: out}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status, body := errorResponse(err)
+	writeJSON(w, status, body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// decodeBody decode body JSON ke v, field yang tidak dikenal ditolak
+func decodeBody(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return err
+		}
+		return badRequest("invalid request body: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,770 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"rpcsol/metrics"
+	"rpcsol/redenvelope"
+	"rpcsol/webhook"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// newTestServer server tanpa node; hanya untuk jalur yang tidak memanggil RPC
+func newTestServer(t *testing.T, opts ...Option) *Server {
+	service := &redenvelope.RedEnvelopeService{Signer: redenvelope.NewWatchOnlySigner(common.Address{})}
+	srv, err := New(service, opts...)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return srv
+}
+
+func doRequest(t *testing.T, srv *Server, method, path, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("Response is not JSON: %s", rec.Body.String())
+	}
+	return rec, decoded
+}
+
+func TestErrorResponse_StatusMapping(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{fmt.Errorf("failed to claim envelope: simulation failed: %w", redenvelope.ErrAlreadyClaimed), http.StatusConflict, "AlreadyClaimed"},
+		{redenvelope.ErrEnvelopeExpired, http.StatusGone, "EnvelopeExpired"},
+		{redenvelope.ErrEnvelopeNotFound, http.StatusNotFound, "EnvelopeNotFound"},
+		{redenvelope.ErrNotEligible, http.StatusForbidden, "NotEligible"},
+		{&redenvelope.InsufficientFundsError{Asset: redenvelope.NativeAsset, Required: big.NewInt(2), Available: big.NewInt(1), Missing: big.NewInt(1)}, http.StatusPaymentRequired, "InsufficientFunds"},
+		{badRequest("bad"), http.StatusBadRequest, redenvelope.ErrorCodeInvalidArgument},
+		{fmt.Errorf("dial tcp: connection refused"), http.StatusInternalServerError, redenvelope.ErrorCodeInternal},
+	}
+	for _, tt := range tests {
+		status, body := errorResponse(tt.err)
+		if status != tt.status || body.Error.Code != tt.code {
+			t.Errorf("errorResponse(%v) = %d %s, want %d %s", tt.err, status, body.Error.Code, tt.status, tt.code)
+		}
+	}
+}
+
+func TestCreate_RejectsInvalidRequest(t *testing.T) {
+	srv := newTestServer(t)
+
+	tests := []struct {
+		body    string
+		message string
+	}{
+		{`{"kind":"BIG","totalClaims":1,"amount":"1","expirySeconds":60}`, "invalid envelope kind"},
+		{`{"kind":"GROUP_FIXED","totalClaims":1,"amount":"-1","expirySeconds":60}`, "invalid amount"},
+		{`{"kind":"GROUP_FIXED","totalClaims":1,"amount":"1"}`, "expirySeconds is required"},
+		{`{"kind":"GROUP_FIXED","unknown":true}`, "unknown field"},
+		{`{"kind":"GROUP_FIXED","totalClaims":1,"amount":"1","expirySeconds":60}`, "from is required"},
+	}
+	for _, tt := range tests {
+		rec, body := doRequest(t, srv, http.MethodPost, "/envelopes", tt.body)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", tt.body, rec.Code)
+			continue
+		}
+		errBody := body["error"].(map[string]interface{})
+		if errBody["code"] != redenvelope.ErrorCodeInvalidArgument || !strings.Contains(errBody["message"].(string), tt.message) {
+			t.Errorf("Unexpected error for %s: %v", tt.body, errBody)
+		}
+	}
+
+	if rec, _ := doRequest(t, srv, http.MethodGet, "/envelopes/abc", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid envelope ID, got %d", rec.Code)
+	}
+}
+
+func TestListEnvelopes_FromIndex(t *testing.T) {
+	indexer := redenvelope.NewIndexer(&redenvelope.RedEnvelopeService{})
+	creator := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	for id := int64(1); id <= 3; id++ {
+		indexer.Apply(redenvelope.EnvelopeEvent{
+			Name:        redenvelope.EventEnvelopeCreated,
+			EnvelopeID:  big.NewInt(id),
+			BlockNumber: uint64(id),
+			TxHash:      common.BigToHash(big.NewInt(id)),
+			Created: &redenvelope.EnvelopeCreatedEvent{
+				EnvelopeId:  big.NewInt(id),
+				Creator:     creator,
+				Kind:        redenvelope.GROUP_RANDOM,
+				NetPot:      big.NewInt(1000),
+				TotalClaims: 5,
+				Expiry:      uint64(time.Now().Add(time.Hour).Unix()),
+				FeeAmount:   big.NewInt(10),
+			},
+		})
+	}
+	srv := newTestServer(t, WithIndexer(indexer))
+
+	rec, body := doRequest(t, srv, http.MethodGet, "/envelopes?creator="+creator.Hex()+"&limit=2", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %v", rec.Code, body)
+	}
+	envelopes := body["envelopes"].([]interface{})
+	if body["total"].(float64) != 3 || len(envelopes) != 2 {
+		t.Fatalf("Unexpected list response %v", body)
+	}
+	first := envelopes[0].(map[string]interface{})
+	if first["id"] != "3" || first["netPot"] != "1000" || first["status"] != redenvelope.EnvelopeStatusActive {
+		t.Errorf("Unexpected envelope %v", first)
+	}
+
+	if rec, _ := doRequest(t, srv, http.MethodGet, "/envelopes?status=lost", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid status, got %d", rec.Code)
+	}
+}
+
+func TestOpenAPI_GeneratedFromRoutes(t *testing.T) {
+	withoutIndex := newTestServer(t).OpenAPI()
+	if _, ok := withoutIndex["paths"].(map[string]interface{})["/envelopes"].(map[string]interface{})["get"]; ok {
+		t.Error("List endpoint documented without an indexer")
+	}
+
+	srv := newTestServer(t, WithIndexer(redenvelope.NewIndexer(&redenvelope.RedEnvelopeService{})))
+	rec, doc := doRequest(t, srv, http.MethodGet, "/openapi.json", "")
+	if rec.Code != http.StatusOK || doc["openapi"] != openAPIVersion {
+		t.Fatalf("Unexpected OpenAPI response %d", rec.Code)
+	}
+
+	paths := doc["paths"].(map[string]interface{})
+	for _, rt := range srv.routes {
+		item, ok := paths[rt.path].(map[string]interface{})
+		if !ok || item[strings.ToLower(rt.method)] == nil {
+			t.Errorf("Route %s %s missing from OpenAPI document", rt.method, rt.path)
+		}
+	}
+
+	schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	create := schemas["CreateEnvelopeRequest"].(map[string]interface{})
+	properties := create["properties"].(map[string]interface{})
+	if properties["amount"].(map[string]interface{})["type"] != "string" || properties["totalClaims"].(map[string]interface{})["type"] != "integer" {
+		t.Errorf("Unexpected CreateEnvelopeRequest schema %v", create)
+	}
+	if properties["from"].(map[string]interface{})["pattern"] == nil {
+		t.Errorf("Expected address pattern for from, got %v", properties["from"])
+	}
+	for _, name := range []string{"WriteResponse", "TxJSON", "ReceiptJSON", "EventJSON", "ErrorResponse", "OfflineTx"} {
+		if schemas[name] == nil {
+			t.Errorf("Schema %s missing", name)
+		}
+	}
+}
+
+func TestJSONRPC_InvalidParams(t *testing.T) {
+	httpServer := httptest.NewServer(newTestServer(t, WithJSONRPC()))
+	defer httpServer.Close()
+
+	client, err := rpc.DialHTTP(httpServer.URL + "/rpc")
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer client.Close()
+
+	var quote redenvelope.FeeQuoteJSON
+	err = client.Call(&quote, "redenvelope_quote", "BOGUS", 1, "1000")
+	var rpcErr rpc.Error
+	var dataErr rpc.DataError
+	if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != rpcCodeInvalidParams {
+		t.Fatalf("Expected invalid params error, got %v", err)
+	}
+	if !errors.As(err, &dataErr) || dataErr.ErrorData().(map[string]interface{})["code"] != redenvelope.ErrorCodeInvalidArgument {
+		t.Errorf("Expected ErrorJSON data, got %v", dataErr.ErrorData())
+	}
+
+	var envelope redenvelope.EnvelopeJSON
+	if err := client.Call(&envelope, "redenvelope_get", "-1"); !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != rpcCodeInvalidParams {
+		t.Errorf("Expected invalid params error for negative ID, got %v", err)
+	}
+}
+
+func TestJSONRPC_SubscribeEvents(t *testing.T) {
+	indexer := redenvelope.NewIndexer(&redenvelope.RedEnvelopeService{})
+	srv := newTestServer(t, WithIndexer(indexer), WithJSONRPC("*"))
+	defer srv.Close()
+	httpServer := httptest.NewServer(srv)
+	defer httpServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client, err := rpc.DialContext(ctx, "ws"+strings.TrimPrefix(httpServer.URL, "http")+"/rpc")
+	if err != nil {
+		t.Fatalf("Failed to dial websocket: %v", err)
+	}
+	defer client.Close()
+
+	creator := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	events := make(chan *redenvelope.EventJSON, 4)
+	sub, err := client.Subscribe(ctx, rpcNamespace, events, "events", redenvelope.EventFilterJSON{Creator: &creator})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	for id, owner := range []common.Address{common.HexToAddress("0x00000000000000000000000000000000000000c2"), creator} {
+		indexer.Apply(redenvelope.EnvelopeEvent{
+			Name:        redenvelope.EventEnvelopeCreated,
+			EnvelopeID:  big.NewInt(int64(id + 1)),
+			BlockNumber: uint64(id + 1),
+			TxHash:      common.BigToHash(big.NewInt(int64(id + 1))),
+			Created: &redenvelope.EnvelopeCreatedEvent{
+				EnvelopeId:  big.NewInt(int64(id + 1)),
+				Creator:     owner,
+				Kind:        redenvelope.GROUP_RANDOM,
+				NetPot:      big.NewInt(1000),
+				TotalClaims: 5,
+				FeeAmount:   big.NewInt(10),
+			},
+		})
+	}
+
+	select {
+	case event := <-events:
+		if event.EnvelopeID != "2" || event.Name != redenvelope.EventEnvelopeCreated {
+			t.Errorf("Expected only envelope 2 from the creator filter, got %+v", event)
+		}
+	case err := <-sub.Err():
+		t.Fatalf("Subscription failed: %v", err)
+	case <-ctx.Done():
+		t.Fatal("Timed out waiting for event notification")
+	}
+}
+
+// readSSE membaca satu frame SSE (sampai baris kosong)
+func readSSE(t *testing.T, reader *bufio.Reader) map[string]string {
+	frame := make(map[string]string)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read event stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return frame
+		}
+		field, value, _ := strings.Cut(line, ": ")
+		frame[field] = value
+	}
+}
+
+func TestEvents_StreamWithFilterAndResume(t *testing.T) {
+	indexer := redenvelope.NewIndexer(&redenvelope.RedEnvelopeService{})
+	httpServer := httptest.NewServer(newTestServer(t, WithIndexer(indexer)))
+	defer httpServer.Close()
+
+	creator := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	req, _ := http.NewRequest(http.MethodGet, httpServer.URL+"/events?address="+creator.Hex(), nil)
+	req.Header.Set("Last-Event-ID", "1-0")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to open event stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Unexpected response %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	reader := bufio.NewReader(resp.Body)
+	if frame := readSSE(t, reader); frame[""] != "connected" {
+		t.Fatalf("Expected connected comment, got %v", frame)
+	}
+
+	// Block 1 sudah diterima client (Last-Event-ID), envelope 2 bukan milik creator
+	for id, owner := range []common.Address{creator, common.HexToAddress("0x00000000000000000000000000000000000000c2"), creator} {
+		indexer.Apply(redenvelope.EnvelopeEvent{
+			Name:        redenvelope.EventEnvelopeCreated,
+			EnvelopeID:  big.NewInt(int64(id + 1)),
+			BlockNumber: uint64(id + 1),
+			LogIndex:    uint(id),
+			TxHash:      common.BigToHash(big.NewInt(int64(id + 1))),
+			Created: &redenvelope.EnvelopeCreatedEvent{
+				EnvelopeId:  big.NewInt(int64(id + 1)),
+				Creator:     owner,
+				Kind:        redenvelope.GROUP_RANDOM,
+				NetPot:      big.NewInt(1000),
+				TotalClaims: 5,
+				FeeAmount:   big.NewInt(10),
+			},
+		})
+	}
+
+	frame := readSSE(t, reader)
+	if frame["id"] != "3-2" || frame["event"] != redenvelope.EventEnvelopeCreated {
+		t.Fatalf("Expected envelope 3 at 3-2, got %v", frame)
+	}
+	var event redenvelope.EventJSON
+	if err := json.Unmarshal([]byte(frame["data"]), &event); err != nil || event.EnvelopeID != "3" {
+		t.Errorf("Unexpected event data %q: %v", frame["data"], err)
+	}
+}
+
+// stalledWriter ResponseWriter milik client yang tidak pernah membaca:
+// setelah write pertama, semua write tertahan sampai release ditutup
+type stalledWriter struct {
+	*httptest.ResponseRecorder
+	once      sync.Once
+	connected chan struct{}
+	release   chan struct{}
+}
+
+func (w *stalledWriter) Write(p []byte) (int, error) {
+	first := false
+	w.once.Do(func() { first = true })
+	if first {
+		defer close(w.connected)
+	} else {
+		<-w.release
+	}
+	return w.ResponseRecorder.Write(p)
+}
+
+func TestEvents_SlowClientDoesNotBlockIndexer(t *testing.T) {
+	indexer := redenvelope.NewIndexer(&redenvelope.RedEnvelopeService{})
+	srv := newTestServer(t, WithIndexer(indexer))
+	w := &stalledWriter{ResponseRecorder: httptest.NewRecorder(), connected: make(chan struct{}), release: make(chan struct{})}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		srv.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events", nil))
+	}()
+	<-w.connected
+
+	applied := make(chan struct{})
+	go func() {
+		defer close(applied)
+		for i := 1; i <= sseEventBuffer+10; i++ {
+			indexer.Apply(redenvelope.EnvelopeEvent{
+				Name:        redenvelope.EventEnvelopeRefunded,
+				EnvelopeID:  big.NewInt(int64(i)),
+				BlockNumber: uint64(i),
+				TxHash:      common.BigToHash(big.NewInt(int64(i))),
+				Refunded:    &redenvelope.EnvelopeRefundedEvent{EnvelopeId: big.NewInt(int64(i)), RefundAmount: big.NewInt(1)},
+			})
+		}
+	}()
+	select {
+	case <-applied:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Indexer.Apply blocked on a client that never reads")
+	}
+
+	// Client yang tertinggal diputus setelah write-nya lepas
+	close(w.release)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Slow client was not disconnected")
+	}
+}
+
+func TestEvents_RejectsInvalidLastEventID(t *testing.T) {
+	indexer := redenvelope.NewIndexer(&redenvelope.RedEnvelopeService{})
+	head := uint64(sseMaxReplayBlocks + 100)
+	indexer.Apply(redenvelope.EnvelopeEvent{
+		Name:        redenvelope.EventEnvelopeRefunded,
+		EnvelopeID:  big.NewInt(1),
+		BlockNumber: head,
+		TxHash:      common.HexToHash("0x01"),
+		Refunded:    &redenvelope.EnvelopeRefundedEvent{EnvelopeId: big.NewInt(1), RefundAmount: big.NewInt(1)},
+	})
+	srv := newTestServer(t, WithIndexer(indexer))
+	tooOld := fmt.Sprintf("/events?lastEventId=%d-0", head-sseMaxReplayBlocks-1)
+	for _, path := range []string{"/events?lastEventId=12", "/events?lastEventId=a-1", "/events?events=EnvelopeLost", tooOld} {
+		rec, body := doRequest(t, srv, http.MethodGet, path, "")
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d: %v", path, rec.Code, body)
+		}
+	}
+}
+
+func TestWebhooks_RegisterAndQuery(t *testing.T) {
+	indexer := redenvelope.NewIndexer(&redenvelope.RedEnvelopeService{})
+	store, _ := webhook.OpenStore("")
+	dispatcher, err := webhook.NewDispatcher(store, indexer)
+	if err != nil {
+		t.Fatalf("Failed to create dispatcher: %v", err)
+	}
+	srv := newTestServer(t, WithIndexer(indexer), WithWebhooks(dispatcher))
+
+	rec, body := doRequest(t, srv, http.MethodPost, "/webhooks",
+		`{"url":"https://partner.example/hook","filter":{"events":["EnvelopeClaimed"],"creator":"0x00000000000000000000000000000000000000c1"}}`)
+	if rec.Code != http.StatusCreated || body["secret"] == "" || body["id"] == "" {
+		t.Fatalf("Expected 201 with id and secret, got %d: %v", rec.Code, body)
+	}
+	id := body["id"].(string)
+
+	rec, body = doRequest(t, srv, http.MethodGet, "/webhooks", "")
+	webhooks := body["webhooks"].([]interface{})
+	if rec.Code != http.StatusOK || len(webhooks) != 1 {
+		t.Fatalf("Unexpected list response %d: %v", rec.Code, body)
+	}
+	if _, hasSecret := webhooks[0].(map[string]interface{})["secret"]; hasSecret {
+		t.Error("Secret should not be listed")
+	}
+
+	for _, tc := range []struct {
+		method, path, body string
+		status             int
+	}{
+		{http.MethodPost, "/webhooks", `{"url":"not a url"}`, http.StatusBadRequest},
+		{http.MethodPost, "/webhooks", `{"url":"https://x.example","filter":{"envelopeId":"abc"}}`, http.StatusBadRequest},
+		{http.MethodGet, "/webhooks/missing", "", http.StatusNotFound},
+		{http.MethodGet, "/deliveries?status=lost", "", http.StatusBadRequest},
+		{http.MethodGet, "/deliveries?webhookId=" + id, "", http.StatusOK},
+		{http.MethodPost, "/deliveries/missing/redeliver", "", http.StatusNotFound},
+		{http.MethodDelete, "/webhooks/" + id, "", http.StatusOK},
+		{http.MethodDelete, "/webhooks/" + id, "", http.StatusNotFound},
+	} {
+		rec, body := doRequest(t, srv, tc.method, tc.path, tc.body)
+		if rec.Code != tc.status {
+			t.Errorf("%s %s: expected %d, got %d: %v", tc.method, tc.path, tc.status, rec.Code, body)
+		}
+	}
+}
+
+func TestSIWEMessage_RoundTrip(t *testing.T) {
+	message := &SIWEMessage{
+		Domain:         "app.example.com",
+		Address:        common.HexToAddress("0x00000000000000000000000000000000000000aa"),
+		Statement:      "Sign in to RedEnvelope",
+		URI:            "https://app.example.com/login",
+		Version:        "1",
+		ChainID:        31337,
+		Nonce:          "abcdef0123456789",
+		IssuedAt:       time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		ExpirationTime: time.Date(2026, 1, 3, 3, 4, 5, 0, time.UTC),
+		Resources:      []string{"https://app.example.com/terms"},
+	}
+	parsed, err := ParseSIWEMessage(message.String())
+	if err != nil {
+		t.Fatalf("Failed to parse: %v", err)
+	}
+	if parsed.String() != message.String() {
+		t.Errorf("Round trip mismatch:\n%s\n---\n%s", parsed.String(), message.String())
+	}
+
+	message.Nonce = "short"
+	if _, err := ParseSIWEMessage(message.String()); err == nil {
+		t.Error("Expected error for short nonce")
+	}
+	if _, err := ParseSIWEMessage("hello"); err == nil {
+		t.Error("Expected error for missing header")
+	}
+}
+
+func doAuthRequest(t *testing.T, srv *Server, method, path, token, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("Response is not JSON: %s", rec.Body.String())
+	}
+	return rec, decoded
+}
+
+// signLogin body POST /auth/login untuk message yang ditandatangani key
+func signLogin(t *testing.T, key *ecdsa.PrivateKey, message string) string {
+	signature, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+	if err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	login, _ := json.Marshal(&LoginRequest{Message: message, Signature: signature})
+	return string(login)
+}
+
+func TestAuth_LoginAndPermissions(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	service := &redenvelope.RedEnvelopeService{
+		Signer:  redenvelope.NewWatchOnlySigner(common.Address{}),
+		ChainID: big.NewInt(31337),
+	}
+	indexer := redenvelope.NewIndexer(service)
+	store, _ := webhook.OpenStore("")
+	dispatcher, err := webhook.NewDispatcher(store, indexer)
+	if err != nil {
+		t.Fatalf("Failed to create dispatcher: %v", err)
+	}
+	srv, err := New(service, WithIndexer(indexer), WithWebhooks(dispatcher),
+		WithAuth(AuthConfig{Domain: "app.example.com", Operators: []common.Address{address}}))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	rec, body := doRequest(t, srv, http.MethodGet, "/auth/nonce", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 for nonce, got %d: %v", rec.Code, body)
+	}
+	message := (&SIWEMessage{
+		Domain:   "app.example.com",
+		Address:  address,
+		URI:      "https://app.example.com",
+		Version:  "1",
+		ChainID:  31337,
+		Nonce:    body["nonce"].(string),
+		IssuedAt: time.Now(),
+	}).String()
+	login := signLogin(t, key, message)
+
+	rec, body = doRequest(t, srv, http.MethodPost, "/auth/login", login)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 for login, got %d: %v", rec.Code, body)
+	}
+	token := body["token"].(string)
+	if !strings.EqualFold(body["address"].(string), address.Hex()) || body["operator"] != true {
+		t.Errorf("Unexpected session: %v", body)
+	}
+
+	// Nonce hanya bisa dipakai sekali
+	if rec, _ := doRequest(t, srv, http.MethodPost, "/auth/login", login); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for reused nonce, got %d", rec.Code)
+	}
+
+	// URI harus berada di domain yang sama
+	_, body = doRequest(t, srv, http.MethodGet, "/auth/nonce", "")
+	foreign := (&SIWEMessage{
+		Domain:   "app.example.com",
+		Address:  address,
+		URI:      "https://phishing.example/login",
+		Version:  "1",
+		ChainID:  31337,
+		Nonce:    body["nonce"].(string),
+		IssuedAt: time.Now(),
+	}).String()
+	if rec, _ := doRequest(t, srv, http.MethodPost, "/auth/login", signLogin(t, key, foreign)); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for URI outside the domain, got %d", rec.Code)
+	}
+
+	rec, body = doRequest(t, srv, http.MethodPost, "/envelopes/1/claim", "{}")
+	if rec.Code != http.StatusUnauthorized || body["error"].(map[string]interface{})["code"] != redenvelope.ErrorCodeUnauthenticated {
+		t.Errorf("Expected 401 for write without session, got %d: %v", rec.Code, body)
+	}
+	other := `{"from":"0x00000000000000000000000000000000000000bb"}`
+	if rec, body := doAuthRequest(t, srv, http.MethodPost, "/envelopes/1/claim", token, other); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for foreign from, got %d: %v", rec.Code, body)
+	}
+	if rec, _ := doAuthRequest(t, srv, http.MethodPost, "/admin/fee", "", `{"feeBps":10}`); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for admin without session, got %d", rec.Code)
+	}
+	for _, path := range []string{"/webhooks", "/deliveries"} {
+		if rec, _ := doAuthRequest(t, srv, http.MethodGet, path, "", ""); rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected 401 for %s without session, got %d", path, rec.Code)
+		}
+	}
+	if rec, _ := doAuthRequest(t, srv, http.MethodPost, "/webhooks", "", `{"url":"https://hooks.example.com"}`); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for webhook registration without session, got %d", rec.Code)
+	}
+	if rec, body := doAuthRequest(t, srv, http.MethodGet, "/webhooks", token, ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 for operator listing webhooks, got %d: %v", rec.Code, body)
+	}
+	// Operator lolos cek akses, lalu validasi request berjalan
+	if rec, body := doAuthRequest(t, srv, http.MethodPost, "/admin/fee", token, `{"feeBps":60000}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid fee, got %d: %v", rec.Code, body)
+	}
+
+	if rec, _ := doAuthRequest(t, srv, http.MethodGet, "/auth/session", "bogus", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for invalid token, got %d", rec.Code)
+	}
+	if rec, _ := doAuthRequest(t, srv, http.MethodPost, "/auth/logout", token, ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 for logout, got %d", rec.Code)
+	}
+	if rec, _ := doAuthRequest(t, srv, http.MethodGet, "/auth/session", token, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 after logout, got %d", rec.Code)
+	}
+}
+
+// ownerNode node palsu yang menjawab setiap eth_call dengan address owner
+type ownerNode struct {
+	owner common.Address
+}
+
+func (n *ownerNode) Call(ctx context.Context, args map[string]interface{}, block string) (hexutil.Bytes, error) {
+	return common.LeftPadBytes(n.owner.Bytes(), 32), nil
+}
+
+// signIn login SIWE ke srv (domain app.example.com) dan mengembalikan token
+func signIn(t *testing.T, srv *Server, key *ecdsa.PrivateKey) string {
+	_, body := doRequest(t, srv, http.MethodGet, "/auth/nonce", "")
+	message := (&SIWEMessage{
+		Domain:   "app.example.com",
+		Address:  crypto.PubkeyToAddress(key.PublicKey),
+		URI:      "https://app.example.com",
+		Version:  "1",
+		ChainID:  31337,
+		Nonce:    body["nonce"].(string),
+		IssuedAt: time.Now(),
+	}).String()
+	rec, body := doRequest(t, srv, http.MethodPost, "/auth/login", signLogin(t, key, message))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 for login, got %d: %v", rec.Code, body)
+	}
+	return body["token"].(string)
+}
+
+func TestWebhooks_SessionOwnsWebhooks(t *testing.T) {
+	var keys [3]*ecdsa.PrivateKey
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatalf("Failed to generate key: %v", err)
+		}
+		keys[i] = key
+	}
+	operator, alice, bob := keys[0], keys[1], keys[2]
+
+	node := rpc.NewServer()
+	if err := node.RegisterName("eth", &ownerNode{owner: common.HexToAddress("0x00000000000000000000000000000000000000ee")}); err != nil {
+		t.Fatalf("Failed to register fake node: %v", err)
+	}
+	client := ethclient.NewClient(rpc.DialInProc(node))
+	defer client.Close()
+
+	service := &redenvelope.RedEnvelopeService{
+		Client:  client,
+		Signer:  redenvelope.NewWatchOnlySigner(common.Address{}),
+		ChainID: big.NewInt(31337),
+	}
+	indexer := redenvelope.NewIndexer(service)
+	store, _ := webhook.OpenStore("")
+	dispatcher, err := webhook.NewDispatcher(store, indexer)
+	if err != nil {
+		t.Fatalf("Failed to create dispatcher: %v", err)
+	}
+	srv, err := New(service, WithIndexer(indexer), WithWebhooks(dispatcher),
+		WithAuth(AuthConfig{Domain: "app.example.com", Operators: []common.Address{crypto.PubkeyToAddress(operator.PublicKey)}}))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	operatorToken, aliceToken, bobToken := signIn(t, srv, operator), signIn(t, srv, alice), signIn(t, srv, bob)
+
+	rec, body := doAuthRequest(t, srv, http.MethodPost, "/webhooks", aliceToken, `{"url":"https://hooks.example.com/alice"}`)
+	if rec.Code != http.StatusCreated || !strings.EqualFold(body["owner"].(string), crypto.PubkeyToAddress(alice.PublicKey).Hex()) {
+		t.Fatalf("Expected 201 with alice as owner, got %d: %v", rec.Code, body)
+	}
+	aliceHook := body["id"].(string)
+	rec, body = doAuthRequest(t, srv, http.MethodPost, "/webhooks", bobToken, `{"url":"https://hooks.example.com/bob"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201 for bob, got %d: %v", rec.Code, body)
+	}
+
+	listed := func(token string) int {
+		rec, body := doAuthRequest(t, srv, http.MethodGet, "/webhooks", token, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200 for listing webhooks, got %d: %v", rec.Code, body)
+		}
+		return len(body["webhooks"].([]interface{}))
+	}
+	if n := listed(aliceToken); n != 1 {
+		t.Errorf("Expected alice to see only her webhook, got %d", n)
+	}
+	if n := listed(operatorToken); n != 2 {
+		t.Errorf("Expected the operator to see all webhooks, got %d", n)
+	}
+
+	// Webhook milik orang lain tidak terlihat dan tidak bisa dihapus
+	if rec, _ := doAuthRequest(t, srv, http.MethodGet, "/webhooks/"+aliceHook, bobToken, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for bob reading alice's webhook, got %d", rec.Code)
+	}
+	if rec, _ := doAuthRequest(t, srv, http.MethodDelete, "/webhooks/"+aliceHook, bobToken, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for bob deleting alice's webhook, got %d", rec.Code)
+	}
+	if rec, _ := doAuthRequest(t, srv, http.MethodGet, "/deliveries", aliceToken, ""); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for delivery history without operator, got %d", rec.Code)
+	}
+	if rec, body := doAuthRequest(t, srv, http.MethodDelete, "/webhooks/"+aliceHook, aliceToken, ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 for alice deleting her webhook, got %d: %v", rec.Code, body)
+	}
+	if n := listed(operatorToken); n != 1 {
+		t.Errorf("Expected one webhook left, got %d", n)
+	}
+}
+
+func TestAuth_NonceLimit(t *testing.T) {
+	srv := newTestServer(t, WithAuth(AuthConfig{Domain: "app.example.com", MaxNonces: 2}))
+
+	for i := 0; i < 2; i++ {
+		if rec, body := doRequest(t, srv, http.MethodGet, "/auth/nonce", ""); rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200 for nonce %d, got %d: %v", i, rec.Code, body)
+		}
+	}
+	rec, body := doRequest(t, srv, http.MethodGet, "/auth/nonce", "")
+	if rec.Code != http.StatusTooManyRequests || body["error"].(map[string]interface{})["code"] != redenvelope.ErrorCodeTooManyRequests {
+		t.Errorf("Expected 429 once the nonce limit is reached, got %d: %v", rec.Code, body)
+	}
+}
+
+func TestWrite_ServerSignedRequiresAuth(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	service := &redenvelope.RedEnvelopeService{
+		Signer:  redenvelope.NewKeySignerFromECDSA(key),
+		Address: crypto.PubkeyToAddress(key.PublicKey),
+	}
+	srv, err := New(service)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	for _, body := range []string{"{}", fmt.Sprintf(`{"from":%q}`, service.Address.Hex())} {
+		rec, resp := doRequest(t, srv, http.MethodPost, "/envelopes/1/claim", body)
+		if rec.Code != http.StatusForbidden || !strings.Contains(resp["error"].(map[string]interface{})["message"].(string), "SIWE session") {
+			t.Errorf("Expected 403 for server-signed claim without auth (%s), got %d: %v", body, rec.Code, resp)
+		}
+	}
+}
+
+func TestMetrics_Endpoint(t *testing.T) {
+	reg := metrics.NewRegistry()
+	redenvelope.NewMetrics(reg)
+	srv := newTestServer(t, WithMetrics(reg))
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != metrics.ContentType {
+		t.Fatalf("Expected 200 text metrics, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Body.String(), "# TYPE redenvelope_tx_submissions_total counter") {
+		t.Errorf("Missing service metrics:\n%s", rec.Body.String())
+	}
+	if _, ok := srv.OpenAPI()["paths"].(map[string]interface{})["/metrics"]; !ok {
+		t.Error("Expected /metrics in OpenAPI document")
+	}
+}
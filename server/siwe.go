@@ -0,0 +1,166 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// siweHeaderSuffix akhir baris pertama pesan EIP-4361
+const siweHeaderSuffix = " wants you to sign in with your Ethereum account:"
+
+// SIWEMessage pesan Sign-In With Ethereum (EIP-4361)
+type SIWEMessage struct {
+	Domain         string
+	Address        common.Address
+	Statement      string // Opsional
+	URI            string
+	Version        string
+	ChainID        uint64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime time.Time // Zero kalau tidak ada
+	NotBefore      time.Time // Zero kalau tidak ada
+	RequestID      string
+	Resources      []string
+}
+
+// String pesan dalam format EIP-4361, yaitu teks yang ditandatangani wallet
+func (m *SIWEMessage) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%s\n%s\n\n", m.Domain, siweHeaderSuffix, m.Address.Hex())
+	if m.Statement != "" {
+		fmt.Fprintf(&b, "%s\n\n", m.Statement)
+	}
+	fmt.Fprintf(&b, "URI: %s\nVersion: %s\nChain ID: %d\nNonce: %s\nIssued At: %s",
+		m.URI, m.Version, m.ChainID, m.Nonce, m.IssuedAt.UTC().Format(time.RFC3339))
+	if !m.ExpirationTime.IsZero() {
+		fmt.Fprintf(&b, "\nExpiration Time: %s", m.ExpirationTime.UTC().Format(time.RFC3339))
+	}
+	if !m.NotBefore.IsZero() {
+		fmt.Fprintf(&b, "\nNot Before: %s", m.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if m.RequestID != "" {
+		fmt.Fprintf(&b, "\nRequest ID: %s", m.RequestID)
+	}
+	if len(m.Resources) > 0 {
+		b.WriteString("\nResources:")
+		for _, resource := range m.Resources {
+			fmt.Fprintf(&b, "\n- %s", resource)
+		}
+	}
+	return b.String()
+}
+
+// ParseSIWEMessage parse pesan EIP-4361. Field wajib: domain, address,
+// URI, Version, Chain ID, Nonce, Issued At.
+func ParseSIWEMessage(text string) (*SIWEMessage, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if len(lines) < 4 || !strings.HasSuffix(lines[0], siweHeaderSuffix) {
+		return nil, fmt.Errorf("invalid SIWE message: missing header")
+	}
+	m := &SIWEMessage{Domain: strings.TrimSuffix(lines[0], siweHeaderSuffix)}
+	if m.Domain == "" {
+		return nil, fmt.Errorf("invalid SIWE message: empty domain")
+	}
+	if !common.IsHexAddress(lines[1]) {
+		return nil, fmt.Errorf("invalid SIWE message: invalid address %q", lines[1])
+	}
+	m.Address = common.HexToAddress(lines[1])
+	if lines[2] != "" {
+		return nil, fmt.Errorf("invalid SIWE message: expected empty line after address")
+	}
+
+	rest := lines[3:]
+	if !strings.HasPrefix(rest[0], "URI: ") {
+		if len(rest) < 2 || rest[1] != "" {
+			return nil, fmt.Errorf("invalid SIWE message: expected empty line after statement")
+		}
+		m.Statement = rest[0]
+		rest = rest[2:]
+	}
+
+	var err error
+	for i := 0; i < len(rest); i++ {
+		key, value, ok := strings.Cut(rest[i], ": ")
+		if rest[i] == "Resources:" {
+			for _, line := range rest[i+1:] {
+				resource, ok := strings.CutPrefix(line, "- ")
+				if !ok {
+					return nil, fmt.Errorf("invalid SIWE message: invalid resource %q", line)
+				}
+				m.Resources = append(m.Resources, resource)
+			}
+			break
+		}
+		if !ok {
+			return nil, fmt.Errorf("invalid SIWE message: invalid line %q", rest[i])
+		}
+		switch key {
+		case "URI":
+			m.URI = value
+		case "Version":
+			m.Version = value
+		case "Chain ID":
+			if m.ChainID, err = strconv.ParseUint(value, 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid SIWE message: invalid chain ID %q", value)
+			}
+		case "Nonce":
+			m.Nonce = value
+		case "Issued At":
+			if m.IssuedAt, err = time.Parse(time.RFC3339, value); err != nil {
+				return nil, fmt.Errorf("invalid SIWE message: invalid issued at %q", value)
+			}
+		case "Expiration Time":
+			if m.ExpirationTime, err = time.Parse(time.RFC3339, value); err != nil {
+				return nil, fmt.Errorf("invalid SIWE message: invalid expiration time %q", value)
+			}
+		case "Not Before":
+			if m.NotBefore, err = time.Parse(time.RFC3339, value); err != nil {
+				return nil, fmt.Errorf("invalid SIWE message: invalid not before %q", value)
+			}
+		case "Request ID":
+			m.RequestID = value
+		default:
+			return nil, fmt.Errorf("invalid SIWE message: unknown field %q", key)
+		}
+	}
+
+	switch {
+	case m.URI == "":
+		return nil, fmt.Errorf("invalid SIWE message: missing URI")
+	case m.Version == "":
+		return nil, fmt.Errorf("invalid SIWE message: missing version")
+	case m.ChainID == 0:
+		return nil, fmt.Errorf("invalid SIWE message: missing chain ID")
+	case len(m.Nonce) < 8:
+		return nil, fmt.Errorf("invalid SIWE message: nonce must be at least 8 characters")
+	case m.IssuedAt.IsZero():
+		return nil, fmt.Errorf("invalid SIWE message: missing issued at")
+	}
+	return m, nil
+}
+
+// recoverSigner address yang menandatangani text dengan personal_sign
+// (EIP-191). V boleh 0/1 atau 27/28.
+func recoverSigner(text string, signature []byte) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("signature must be %d bytes, got %d", crypto.SignatureLength, len(signature))
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	copy(sig, signature)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pub, err := crypto.SigToPub(accounts.TextHash([]byte(text)), sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to recover signer: %v", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
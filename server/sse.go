@@ -0,0 +1,259 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"rpcsol/redenvelope"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	sseHeartbeat       = 15 * time.Second // Interval komentar keep-alive
+	sseEventBuffer     = 128              // Buffer event live per koneksi
+	sseMaxReplayBlocks = 50_000           // Jarak maksimum Last-Event-ID di belakang index
+)
+
+// eventsRoute GET /events, stream Server-Sent Events dari index
+func (s *Server) eventsRoute() route {
+	return route{
+		method:  http.MethodGet,
+		path:    "/events",
+		summary: "Stream envelope events as Server-Sent Events; resume with Last-Event-ID (<block>-<logIndex>)",
+		params: []param{
+			{name: "events", in: "query", description: "Comma-separated event names (EnvelopeCreated, EnvelopeClaimed, EnvelopeRefunded)"},
+			{name: "envelopeId", in: "query", description: "Filter by envelope ID"},
+			{name: "roomId", in: "query", description: "Filter by room ID (hashed like in create)"},
+			{name: "roomIdHash", in: "query", description: "Filter by room ID hash"},
+			{name: "creator", in: "query", description: "Filter by creator address"},
+			{name: "address", in: "query", description: "Filter by creator, recipient or claimer address"},
+			{name: "lastEventId", in: "query", description: "Same as the Last-Event-ID header, for clients that cannot set headers"},
+			{name: "Last-Event-ID", in: "header", description: "Resume after this event ID"},
+		},
+		response:    redenvelope.EventJSON{},
+		contentType: "text/event-stream",
+		stream:      s.handleEvents,
+	}
+}
+
+// eventPosition posisi event di chain, dipakai sebagai id SSE
+type eventPosition struct {
+	block    uint64
+	logIndex uint
+}
+
+func positionOf(event redenvelope.EnvelopeEvent) eventPosition {
+	return eventPosition{block: event.BlockNumber, logIndex: event.LogIndex}
+}
+
+func (p eventPosition) after(other eventPosition) bool {
+	return p.block > other.block || (p.block == other.block && p.logIndex > other.logIndex)
+}
+
+func (p eventPosition) String() string {
+	return fmt.Sprintf("%d-%d", p.block, p.logIndex)
+}
+
+// parseEventID kebalikan eventPosition.String
+func parseEventID(value string) (eventPosition, error) {
+	blockPart, indexPart, ok := strings.Cut(value, "-")
+	block, blockErr := strconv.ParseUint(blockPart, 10, 64)
+	logIndex, indexErr := strconv.ParseUint(indexPart, 10, 32)
+	if !ok || blockErr != nil || indexErr != nil {
+		return eventPosition{}, badRequest("invalid event ID %q, expected <block>-<logIndex>", value)
+	}
+	return eventPosition{block: block, logIndex: uint(logIndex)}, nil
+}
+
+// eventFilter EventFilter dari params, error dikembalikan sebagai 400
+func eventFilter(params *redenvelope.EventFilterJSON) (*redenvelope.EventFilter, error) {
+	filter, err := params.EventFilter()
+	if err != nil {
+		return nil, badRequest("%v", err)
+	}
+	return filter, nil
+}
+
+// eventFilterQuery EventFilterJSON dari query string
+func eventFilterQuery(query url.Values) (*redenvelope.EventFilterJSON, error) {
+	params := &redenvelope.EventFilterJSON{
+		EnvelopeID: query.Get("envelopeId"),
+		RoomID:     query.Get("roomId"),
+	}
+	if value := query.Get("events"); value != "" {
+		params.Events = strings.Split(value, ",")
+	}
+	if value := query.Get("roomIdHash"); value != "" {
+		hash, err := parseHash("roomIdHash", value)
+		if err != nil {
+			return nil, err
+		}
+		params.RoomIdHash = &hash
+	}
+	for name, target := range map[string]**common.Address{"creator": &params.Creator, "address": &params.Address} {
+		if value := query.Get(name); value != "" {
+			address, err := parseAddress(name, value)
+			if err != nil {
+				return nil, err
+			}
+			*target = &address
+		}
+	}
+	return params, nil
+}
+
+// handleEvents stream event yang cocok dengan filter. Dengan Last-Event-ID,
+// event setelah posisi itu sampai block terakhir index diputar ulang dari
+// chain sebelum stream live.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	params, err := eventFilterQuery(query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	filter, err := eventFilter(params)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var last *eventPosition
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = query.Get("lastEventId")
+	}
+	if lastID != "" {
+		position, err := parseEventID(lastID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if err := checkReplayRange(s.indexer, position); err != nil {
+			writeError(w, err)
+			return
+		}
+		last = &position
+	}
+
+	ctx := r.Context()
+	stream := &sseWriter{w: w, rc: http.NewResponseController(w)}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	// Replay dulu tanpa subscription supaya indexer tidak tertahan, lalu
+	// replay sekali lagi untuk celah sebelum subscription aktif
+	if last != nil {
+		if err := s.replayEvents(ctx, filter, last, stream); err != nil {
+			stream.error(err)
+			return
+		}
+	}
+	events, sub := s.indexer.SubscribeEventsBuffered(sseEventBuffer)
+	defer sub.Unsubscribe()
+	if last != nil {
+		if err := s.replayEvents(ctx, filter, last, stream); err != nil {
+			stream.error(err)
+			return
+		}
+	}
+	if err := stream.comment("connected"); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				// Client tertinggal sseEventBuffer event: koneksi diputus,
+				// EventSource reconnect dengan Last-Event-ID
+				return
+			}
+			position := positionOf(event)
+			if last != nil && !position.after(*last) {
+				continue
+			}
+			last = &position
+			if !s.indexer.Match(filter, event) {
+				continue
+			}
+			if err := stream.event(event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := stream.comment("ping"); err != nil {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkReplayRange menolak Last-Event-ID lebih dari sseMaxReplayBlocks
+// block di belakang index, supaya resume tidak membaca log chain tanpa batas
+func checkReplayRange(indexer *redenvelope.Indexer, position eventPosition) error {
+	head, _ := indexer.LastBlock()
+	if head > sseMaxReplayBlocks && position.block < head-sseMaxReplayBlocks {
+		return badRequest("event ID %s is more than %d blocks behind the index (block %d), reconnect without Last-Event-ID",
+			position, sseMaxReplayBlocks, head)
+	}
+	return nil
+}
+
+// replayEvents kirim event dari chain setelah *last sampai block terakhir
+// index, satu window log per panggilan node, lalu majukan *last
+func (s *Server) replayEvents(ctx context.Context, filter *redenvelope.EventFilter, last *eventPosition, stream *sseWriter) error {
+	return s.indexer.Replay(ctx, last.block, func(event redenvelope.EnvelopeEvent) error {
+		position := positionOf(event)
+		if !position.after(*last) {
+			return nil
+		}
+		*last = position
+		if !s.indexer.Match(filter, event) {
+			return nil
+		}
+		return stream.event(event)
+	})
+}
+
+// sseWriter menulis frame text/event-stream dan langsung flush
+type sseWriter struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+func (s *sseWriter) event(event redenvelope.EnvelopeEvent) error {
+	data, err := json.Marshal(redenvelope.NewEventJSON(event))
+	if err != nil {
+		return err
+	}
+	return s.write(fmt.Sprintf("id: %s\nevent: %s\ndata: %s\n\n", positionOf(event), event.Name, data))
+}
+
+func (s *sseWriter) comment(text string) error {
+	return s.write(": " + text + "\n\n")
+}
+
+// error event "error" dengan body ErrorResponse, stream berhenti setelahnya
+func (s *sseWriter) error(err error) {
+	_, body := errorResponse(err)
+	data, _ := json.Marshal(body)
+	s.write(fmt.Sprintf("event: error\ndata: %s\n\n", data))
+}
+
+func (s *sseWriter) write(frame string) error {
+	if _, err := s.w.Write([]byte(frame)); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
@@ -0,0 +1,237 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+
+	"rpcsol/redenvelope"
+	"rpcsol/webhook"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// WithWebhooks mengaktifkan endpoint /webhooks dan /deliveries. Dispatcher
+// harus dijalankan sendiri (Dispatcher.Run).
+func WithWebhooks(dispatcher *webhook.Dispatcher) Option {
+	return func(s *Server) error {
+		s.webhooks = dispatcher
+		return nil
+	}
+}
+
+// CreateWebhookRequest body POST /webhooks
+type CreateWebhookRequest struct {
+	URL    string                      `json:"url"`
+	Secret string                      `json:"secret,omitempty"` // Default: dibuatkan acak
+	Filter redenvelope.EventFilterJSON `json:"filter"`
+}
+
+// ListWebhooksResponse hasil GET /webhooks
+type ListWebhooksResponse struct {
+	Webhooks []webhook.Subscription `json:"webhooks"`
+}
+
+// ListDeliveriesResponse hasil GET /deliveries
+type ListDeliveriesResponse struct {
+	Deliveries []webhook.Delivery `json:"deliveries"`
+}
+
+// webhookRoutes endpoint subscription dan riwayat delivery. Dengan WithAuth
+// setiap session boleh mendaftarkan, membaca dan menghapus webhook miliknya
+// sendiri; operator mengelola semua webhook. Riwayat delivery hanya untuk
+// operator.
+func (s *Server) webhookRoutes() []route {
+	idParam := param{name: "id", in: "path", description: "Webhook ID", required: true}
+	deliveryParam := param{name: "id", in: "path", description: "Delivery ID", required: true}
+
+	return []route{
+		{
+			method:   http.MethodPost,
+			path:     "/webhooks",
+			summary:  "Register a webhook; the secret is only returned here",
+			request:  CreateWebhookRequest{},
+			response: webhook.Subscription{},
+			status:   http.StatusCreated,
+			access:   accessSession,
+			handle:   s.handleCreateWebhook,
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/webhooks",
+			summary:  "List webhooks (only the caller's own unless operator)",
+			response: ListWebhooksResponse{},
+			access:   accessSession,
+			handle:   s.handleListWebhooks,
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/webhooks/{id}",
+			summary:  "Read a webhook",
+			params:   []param{idParam},
+			response: webhook.Subscription{},
+			access:   accessSession,
+			handle:   s.handleGetWebhook,
+		},
+		{
+			method:   http.MethodDelete,
+			path:     "/webhooks/{id}",
+			summary:  "Delete a webhook; its delivery history is kept",
+			params:   []param{idParam},
+			response: webhook.Subscription{},
+			access:   accessSession,
+			handle:   s.handleDeleteWebhook,
+		},
+		{
+			method:  http.MethodGet,
+			path:    "/deliveries",
+			summary: "Delivery history, newest first (status=dead for the dead-letter store)",
+			params: []param{
+				{name: "webhookId", in: "query", description: "Filter by webhook ID"},
+				{name: "status", in: "query", description: "pending, delivered or dead"},
+				{name: "envelopeId", in: "query", description: "Filter by envelope ID"},
+				{name: "limit", in: "query", description: "Page size, at most 200"},
+			},
+			response: ListDeliveriesResponse{},
+			access:   accessOperator,
+			handle:   s.handleListDeliveries,
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/deliveries/{id}",
+			summary:  "Read a delivery with all attempts",
+			params:   []param{deliveryParam},
+			response: webhook.Delivery{},
+			access:   accessOperator,
+			handle:   s.handleGetDelivery,
+		},
+		{
+			method:   http.MethodPost,
+			path:     "/deliveries/{id}/redeliver",
+			summary:  "Schedule a delivery (usually a dead letter) to be sent again now",
+			params:   []param{deliveryParam},
+			response: webhook.Delivery{},
+			access:   accessOperator,
+			handle:   s.handleRedeliver,
+		},
+	}
+}
+
+// webhookScope pembatas webhook untuk caller: all true kalau semua webhook
+// boleh diakses (tanpa WithAuth, atau caller operator), selain itu hanya
+// webhook dengan Owner == owner
+func (s *Server) webhookScope(ctx context.Context) (owner common.Address, all bool, err error) {
+	if s.auth == nil {
+		return common.Address{}, true, nil
+	}
+	owner, _ = caller(ctx)
+	all, err = s.isOperator(owner)
+	return owner, all, err
+}
+
+// ownedWebhook subscription id kalau caller boleh mengaksesnya. Webhook
+// milik address lain dilaporkan tidak ada.
+func (s *Server) ownedWebhook(ctx context.Context, id string) (webhook.Subscription, error) {
+	owner, all, err := s.webhookScope(ctx)
+	if err != nil {
+		return webhook.Subscription{}, err
+	}
+	sub, ok := s.webhooks.Subscription(id)
+	if !ok || (!all && sub.Owner != owner) {
+		return webhook.Subscription{}, notFound(webhook.ErrSubscriptionNotFound)
+	}
+	return sub, nil
+}
+
+func (s *Server) handleCreateWebhook(r *http.Request) (int, interface{}, error) {
+	var req CreateWebhookRequest
+	if err := decodeBody(r, &req); err != nil {
+		return 0, nil, err
+	}
+	owner, _ := caller(r.Context())
+	sub, err := s.webhooks.SubscribeOwned(owner, req.URL, req.Secret, req.Filter)
+	if err != nil {
+		return 0, nil, badRequest("%v", err)
+	}
+	return http.StatusCreated, sub, nil
+}
+
+func (s *Server) handleListWebhooks(r *http.Request) (int, interface{}, error) {
+	owner, all, err := s.webhookScope(r.Context())
+	if err != nil {
+		return 0, nil, err
+	}
+	subs := s.webhooks.Subscriptions()
+	if !all {
+		subs = slices.DeleteFunc(subs, func(sub webhook.Subscription) bool { return sub.Owner != owner })
+	}
+	return http.StatusOK, &ListWebhooksResponse{Webhooks: subs}, nil
+}
+
+func (s *Server) handleGetWebhook(r *http.Request) (int, interface{}, error) {
+	sub, err := s.ownedWebhook(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, &sub, nil
+}
+
+func (s *Server) handleDeleteWebhook(r *http.Request) (int, interface{}, error) {
+	sub, err := s.ownedWebhook(r.Context(), r.PathValue("id"))
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := s.webhooks.Unsubscribe(sub.ID); err != nil {
+		if errors.Is(err, webhook.ErrSubscriptionNotFound) {
+			return 0, nil, notFound(err)
+		}
+		return 0, nil, err
+	}
+	return http.StatusOK, &sub, nil
+}
+
+func (s *Server) handleListDeliveries(r *http.Request) (int, interface{}, error) {
+	query := r.URL.Query()
+	filter := webhook.DeliveryFilter{
+		SubscriptionID: query.Get("webhookId"),
+		EnvelopeID:     query.Get("envelopeId"),
+	}
+	switch status := query.Get("status"); status {
+	case "", webhook.DeliveryPending, webhook.DeliveryDelivered, webhook.DeliveryDead:
+		filter.Status = status
+	default:
+		return 0, nil, badRequest("invalid status %q", status)
+	}
+	limit, err := queryInt(query.Get("limit"), redenvelope.DefaultIndexLimit)
+	if err != nil {
+		return 0, nil, badRequest("invalid limit: %v", err)
+	}
+	filter.Limit = min(max(limit, 1), maxListLimit)
+
+	deliveries := s.webhooks.Deliveries(filter)
+	if deliveries == nil {
+		deliveries = []webhook.Delivery{}
+	}
+	return http.StatusOK, &ListDeliveriesResponse{Deliveries: deliveries}, nil
+}
+
+func (s *Server) handleGetDelivery(r *http.Request) (int, interface{}, error) {
+	delivery, ok := s.webhooks.Delivery(r.PathValue("id"))
+	if !ok {
+		return 0, nil, notFound(webhook.ErrDeliveryNotFound)
+	}
+	return http.StatusOK, &delivery, nil
+}
+
+func (s *Server) handleRedeliver(r *http.Request) (int, interface{}, error) {
+	id := r.PathValue("id")
+	if err := s.webhooks.Redeliver(id); err != nil {
+		if errors.Is(err, webhook.ErrDeliveryNotFound) {
+			return 0, nil, notFound(err)
+		}
+		return 0, nil, err
+	}
+	delivery, _ := s.webhooks.Delivery(id)
+	return http.StatusOK, &delivery, nil
+}
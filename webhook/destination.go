@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrForbiddenDestination dikembalikan kalau URL webhook mengarah ke alamat
+// yang tidak publik (loopback, private, link-local, ...) dan tidak ada di
+// WithAllowedNetworks
+var ErrForbiddenDestination = errors.New("webhook destination is not a public address")
+
+// resolveTimeout batas waktu resolve host saat Subscribe
+const resolveTimeout = 5 * time.Second
+
+// sharedAddressSpace 100.64.0.0/10 (CGNAT), tidak termasuk IsPrivate
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// WithAllowedNetworks mengizinkan tujuan di jaringan non-publik tertentu,
+// misalnya 127.0.0.0/8 untuk receiver lokal saat development
+func WithAllowedNetworks(networks ...netip.Prefix) Option {
+	return func(d *Dispatcher) error {
+		for _, network := range networks {
+			if !network.IsValid() {
+				return fmt.Errorf("invalid allowed network %v", network)
+			}
+		}
+		d.allowed = append(d.allowed, networks...)
+		return nil
+	}
+}
+
+// allowedAddr true kalau addr publik atau ada di jaringan yang diizinkan
+func (d *Dispatcher) allowedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if slices.ContainsFunc(d.allowed, func(network netip.Prefix) bool { return network.Contains(addr) }) {
+		return true
+	}
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
+
+// checkDestination menolak host yang jelas mengarah ke jaringan internal.
+// Hostname di-resolve kalau bisa; kalau resolve gagal, host diterima dan
+// dicek lagi oleh dialer setiap kali delivery dikirim.
+func (d *Dispatcher) checkDestination(ctx context.Context, host string) error {
+	if addr, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil {
+		if !d.allowedAddr(addr) {
+			return fmt.Errorf("%w: %s", ErrForbiddenDestination, host)
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, resolveTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if !d.allowedAddr(addr) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrForbiddenDestination, host, addr)
+		}
+	}
+	return nil
+}
+
+// newHTTPClient client default: setiap koneksi dicek setelah DNS resolve,
+// jadi host yang berubah ke alamat internal (DNS rebinding) tetap ditolak.
+// Proxy dari environment tidak dipakai karena alamat tujuan tidak bisa
+// dicek lewat proxy.
+func (d *Dispatcher) newHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: defaultTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("failed to parse dial address %q: %v", address, err)
+			}
+			if !d.allowedAddr(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", ErrForbiddenDestination, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: defaultTimeout, Transport: transport}
+}
@@ -0,0 +1,451 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"time"
+
+	"rpcsol/redenvelope"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrSubscriptionNotFound dikembalikan kalau ID subscription tidak ada
+var ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+
+// ErrDeliveryNotFound dikembalikan kalau ID delivery tidak ada
+var ErrDeliveryNotFound = errors.New("webhook delivery not found")
+
+// RetryPolicy jadwal retry delivery. Attempt ke-n (mulai 1) yang gagal
+// dijadwalkan ulang setelah InitialBackoff * 2^(n-1), maksimum MaxBackoff.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy sekitar 4 jam sebelum delivery masuk dead-letter
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    10,
+	InitialBackoff: 10 * time.Second,
+	MaxBackoff:     time.Hour,
+}
+
+// Backoff jeda setelah attempt ke-attempt gagal
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < attempt && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, p.MaxBackoff)
+}
+
+const (
+	defaultTimeout  = 10 * time.Second // Timeout satu attempt
+	defaultWorkers  = 4                // Attempt yang berjalan bersamaan
+	eventBuffer     = 128              // Buffer event dari indexer
+	maxResponseRead = 64 << 10         // Body response receiver yang dibaca untuk riwayat
+)
+
+// Dispatcher menjadwalkan delivery untuk setiap event baru di Indexer yang
+// cocok dengan filter subscription, dan mengirimnya dengan retry.
+type Dispatcher struct {
+	store   *Store
+	indexer *redenvelope.Indexer
+	client  *http.Client
+	retry   RetryPolicy
+	workers int
+	allowed []netip.Prefix // Jaringan non-publik yang boleh jadi tujuan
+	logger  *slog.Logger
+
+	wake chan struct{}
+}
+
+// Option konfigurasi Dispatcher
+type Option func(*Dispatcher) error
+
+// WithHTTPClient client untuk mengirim delivery (default timeout 10 detik).
+// Client default menolak koneksi ke alamat non-publik; client dari option
+// ini tidak dicek saat dial, hanya URL-nya yang dicek saat Subscribe.
+func WithHTTPClient(client *http.Client) Option {
+	return func(d *Dispatcher) error {
+		d.client = client
+		return nil
+	}
+}
+
+// WithRetryPolicy mengganti DefaultRetryPolicy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(d *Dispatcher) error {
+		if policy.MaxAttempts < 1 || policy.InitialBackoff <= 0 || policy.MaxBackoff < policy.InitialBackoff {
+			return fmt.Errorf("invalid retry policy %+v", policy)
+		}
+		d.retry = policy
+		return nil
+	}
+}
+
+// WithLogger logger untuk error Run yang tidak menghentikan dispatcher
+// (event yang gagal dijadwalkan, subscription indexer yang tertinggal).
+// Default tanpa log.
+func WithLogger(logger *slog.Logger) Option {
+	return func(d *Dispatcher) error {
+		d.logger = logger
+		return nil
+	}
+}
+
+// WithWorkers jumlah delivery yang dikirim bersamaan
+func WithWorkers(workers int) Option {
+	return func(d *Dispatcher) error {
+		if workers < 1 {
+			return fmt.Errorf("workers must be positive")
+		}
+		d.workers = workers
+		return nil
+	}
+}
+
+// NewDispatcher membuat Dispatcher untuk event dari indexer
+func NewDispatcher(store *Store, indexer *redenvelope.Indexer, opts ...Option) (*Dispatcher, error) {
+	d := &Dispatcher{
+		store:   store,
+		indexer: indexer,
+		retry:   DefaultRetryPolicy,
+		workers: defaultWorkers,
+		logger:  slog.New(slog.DiscardHandler),
+		wake:    make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		if err := opt(d); err != nil {
+			return nil, err
+		}
+	}
+	if d.client == nil {
+		d.client = d.newHTTPClient()
+	}
+	return d, nil
+}
+
+// Subscribe mendaftarkan URL penerima. Secret kosong dibuatkan acak;
+// secret ada di Subscription yang dikembalikan dan hanya di sana. URL ke
+// alamat non-publik ditolak dengan ErrForbiddenDestination.
+func (d *Dispatcher) Subscribe(rawURL, secret string, filter redenvelope.EventFilterJSON) (*Subscription, error) {
+	return d.SubscribeOwned(common.Address{}, rawURL, secret, filter)
+}
+
+// SubscribeOwned seperti Subscribe, dengan owner dicatat di Subscription
+// supaya pemiliknya bisa membaca dan menghapusnya sendiri
+func (d *Dispatcher) SubscribeOwned(owner common.Address, rawURL, secret string, filter redenvelope.EventFilterJSON) (*Subscription, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return nil, fmt.Errorf("invalid webhook URL %q, expected an absolute http(s) URL", rawURL)
+	}
+	if err := d.checkDestination(context.Background(), target.Hostname()); err != nil {
+		return nil, err
+	}
+	if _, err := filter.EventFilter(); err != nil {
+		return nil, err
+	}
+	if secret == "" {
+		if secret, err = randomHex(32); err != nil {
+			return nil, err
+		}
+	}
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, err
+	}
+
+	lastBlock, _ := d.indexer.LastBlock()
+	sub := &Subscription{
+		ID:        id,
+		URL:       target.String(),
+		Secret:    secret,
+		Filter:    filter,
+		Owner:     owner,
+		FromBlock: lastBlock + 1,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := d.store.addSubscription(sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Unsubscribe menghapus subscription. Riwayat delivery tetap bisa dibaca,
+// delivery yang masih pending tidak dikirim lagi.
+func (d *Dispatcher) Unsubscribe(id string) error {
+	removed, err := d.store.removeSubscription(id)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// Subscription subscription berdasarkan ID, tanpa secret
+func (d *Dispatcher) Subscription(id string) (Subscription, bool) {
+	sub, ok := d.store.subscription(id)
+	sub.Secret = ""
+	return sub, ok
+}
+
+// Subscriptions semua subscription, tanpa secret
+func (d *Dispatcher) Subscriptions() []Subscription {
+	subs := d.store.subscriptionList()
+	for i := range subs {
+		subs[i].Secret = ""
+	}
+	return subs
+}
+
+// Deliveries riwayat delivery yang cocok dengan filter, terbaru dulu.
+// Dead-letter adalah delivery dengan Status DeliveryDead.
+func (d *Dispatcher) Deliveries(filter DeliveryFilter) []Delivery {
+	return d.store.deliveryList(filter)
+}
+
+// Delivery delivery berdasarkan ID
+func (d *Dispatcher) Delivery(id string) (Delivery, bool) {
+	return d.store.delivery(id)
+}
+
+// Redeliver menjadwalkan ulang delivery (biasanya dari dead-letter) untuk
+// dikirim segera, dengan hitungan retry dari awal
+func (d *Dispatcher) Redeliver(id string) error {
+	if _, ok := d.store.delivery(id); !ok {
+		return ErrDeliveryNotFound
+	}
+	err := d.store.updateDelivery(id, func(delivery *Delivery) {
+		delivery.Status = DeliveryPending
+		delivery.Failures = 0
+		delivery.NextAttemptAt = time.Now().UTC()
+		delivery.UpdatedAt = delivery.NextAttemptAt
+	})
+	if err != nil {
+		return err
+	}
+	d.notify()
+	return nil
+}
+
+// Run menerima event dari indexer dan mengirim delivery sampai ctx selesai.
+// Delivery pending dari store (misalnya sebelum restart) ikut dikirim.
+// Run tidak pernah menahan indexer: kalau tertinggal lebih dari
+// eventBuffer event, Run subscribe ulang dan membaca event yang terlewat
+// dari chain. Event yang gagal dijadwalkan di-log dan dilewati.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	events, sub := d.indexer.SubscribeEventsBuffered(eventBuffer)
+	defer func() { sub.Unsubscribe() }()
+	lastBlock, _ := d.indexer.LastBlock()
+
+	sem := make(chan struct{}, d.workers)
+	done := make(chan string)
+	inflight := make(map[string]bool)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				d.logger.Warn("webhook dispatcher fell behind the indexer, replaying events", "fromBlock", lastBlock)
+				events, sub = d.indexer.SubscribeEventsBuffered(eventBuffer)
+				if err := d.backfill(ctx, lastBlock); err != nil {
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					d.logger.Error("failed to replay webhook events", "fromBlock", lastBlock, "error", err)
+				}
+				continue
+			}
+			lastBlock = event.BlockNumber
+			if err := d.schedule(event); err != nil {
+				d.logger.Error("failed to schedule webhook event", "block", event.BlockNumber, "logIndex", event.LogIndex, "error", err)
+			}
+			continue
+		case id := <-done:
+			delete(inflight, id)
+		case <-d.wake:
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		due, next := d.store.due(time.Now(), inflight)
+		for _, delivery := range due {
+			inflight[delivery.ID] = true
+			go func(delivery Delivery) {
+				sem <- struct{}{}
+				d.attempt(ctx, delivery)
+				<-sem
+				select {
+				case done <- delivery.ID:
+				case <-ctx.Done():
+				}
+			}(delivery)
+		}
+
+		timer.Stop()
+		if !next.IsZero() {
+			timer.Reset(time.Until(next))
+		}
+	}
+}
+
+// backfill menjadwalkan event dari block from sampai block terakhir index,
+// untuk event yang terlewat saat Run tertinggal. Block sebelum FromBlock
+// semua subscription tidak dibaca. Delivery yang sudah ada tidak dibuat
+// ulang.
+func (d *Dispatcher) backfill(ctx context.Context, from uint64) error {
+	subs := d.store.subscriptionList()
+	if len(subs) == 0 {
+		return nil
+	}
+	start := subs[0].FromBlock
+	for _, sub := range subs[1:] {
+		start = min(start, sub.FromBlock)
+	}
+	return d.indexer.Replay(ctx, max(from, start), func(event redenvelope.EnvelopeEvent) error {
+		if err := d.schedule(event); err != nil {
+			d.logger.Error("failed to schedule webhook event", "block", event.BlockNumber, "logIndex", event.LogIndex, "error", err)
+		}
+		return nil
+	})
+}
+
+// schedule membuat delivery untuk setiap subscription yang cocok
+func (d *Dispatcher) schedule(event redenvelope.EnvelopeEvent) error {
+	var scheduled bool
+	for _, sub := range d.store.subscriptionList() {
+		if event.BlockNumber < sub.FromBlock {
+			continue
+		}
+		filter, err := sub.Filter.EventFilter()
+		if err != nil || !d.indexer.Match(filter, event) {
+			continue
+		}
+
+		now := time.Now().UTC()
+		added, err := d.store.addDelivery(&Delivery{
+			ID:             sub.ID + "-" + eventID(event),
+			SubscriptionID: sub.ID,
+			Event:          redenvelope.NewEventJSON(event),
+			Status:         DeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to schedule webhook delivery: %v", err)
+		}
+		scheduled = scheduled || added
+	}
+	if scheduled {
+		d.notify()
+	}
+	return nil
+}
+
+func (d *Dispatcher) notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// attempt mengirim delivery sekali dan mencatat hasilnya
+func (d *Dispatcher) attempt(ctx context.Context, delivery Delivery) {
+	sub, ok := d.store.subscription(delivery.SubscriptionID)
+	if !ok {
+		d.store.updateDelivery(delivery.ID, func(delivery *Delivery) {
+			delivery.Status = DeliveryDead
+			delivery.NextAttemptAt = time.Time{}
+			delivery.Attempts = append(delivery.Attempts, Attempt{At: time.Now().UTC(), Error: ErrSubscriptionNotFound.Error()})
+		})
+		return
+	}
+
+	start := time.Now()
+	status, err := d.post(ctx, sub, delivery)
+	if ctx.Err() != nil {
+		return // Shutdown, dicoba lagi setelah Run berikutnya
+	}
+
+	record := Attempt{At: start.UTC(), StatusCode: status, DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	d.store.updateDelivery(delivery.ID, func(delivery *Delivery) {
+		now := time.Now().UTC()
+		delivery.Attempts = append(delivery.Attempts, record)
+		delivery.UpdatedAt = now
+		if err == nil {
+			delivery.Status = DeliveryDelivered
+			delivery.NextAttemptAt = time.Time{}
+			return
+		}
+		delivery.Failures++
+		if delivery.Failures >= d.retry.MaxAttempts {
+			delivery.Status = DeliveryDead
+			delivery.NextAttemptAt = time.Time{}
+			return
+		}
+		delivery.NextAttemptAt = now.Add(d.retry.Backoff(delivery.Failures))
+	})
+}
+
+// post kirim payload bertanda tangan, error kalau response bukan 2xx
+func (d *Dispatcher) post(ctx context.Context, sub Subscription, delivery Delivery) (int, error) {
+	body, err := json.Marshal(&Payload{
+		DeliveryID:     delivery.ID,
+		SubscriptionID: sub.ID,
+		Event:          delivery.Event,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "rpcsol-webhook/1")
+	req.Header.Set(HeaderEvent, delivery.Event.Name)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, time.Now(), body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseRead))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver returned %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %v", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
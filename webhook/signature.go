@@ -0,0 +1,104 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header yang dikirim bersama setiap delivery
+const (
+	HeaderSignature = "X-RedEnvelope-Signature" // t=<unix>,v1=<hex HMAC-SHA256>
+	HeaderEvent     = "X-RedEnvelope-Event"     // Nama event, misalnya EnvelopeClaimed
+	HeaderDelivery  = "X-RedEnvelope-Delivery"  // ID delivery, sama untuk setiap retry
+)
+
+// DefaultTolerance selisih waktu maksimum signature yang diterima Verify
+const DefaultTolerance = 5 * time.Minute
+
+// maxPayloadBytes batas body yang dibaca VerifyRequest
+const maxPayloadBytes = 1 << 20
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrSignatureExpired = errors.New("webhook signature timestamp outside tolerance")
+)
+
+// Sign nilai header HeaderSignature untuk body pada waktu timestamp.
+// HMAC-SHA256 dihitung atas "<timestamp>.<body>" supaya timestamp ikut
+// ditandatangani.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unix := timestamp.Unix()
+	return fmt.Sprintf("t=%d,v1=%s", unix, hex.EncodeToString(signature(secret, unix, body)))
+}
+
+func signature(secret string, unix int64, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", unix)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// Verify memeriksa header HeaderSignature untuk body. Signature ditolak
+// kalau timestamp-nya berselisih lebih dari tolerance dengan now
+// (tolerance 0 memakai DefaultTolerance).
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	if tolerance == 0 {
+		tolerance = DefaultTolerance
+	}
+
+	var unix int64
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return ErrInvalidSignature
+			}
+			unix = parsed
+		case "v1":
+			decoded, err := hex.DecodeString(value)
+			if err != nil {
+				return ErrInvalidSignature
+			}
+			signatures = append(signatures, decoded)
+		}
+	}
+	if unix == 0 || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	if skew := now.Sub(time.Unix(unix, 0)); skew > tolerance || skew < -tolerance {
+		return ErrSignatureExpired
+	}
+	expected := signature(secret, unix, body)
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// VerifyRequest membaca body request receiver dan memverifikasi
+// signature-nya, lalu mengembalikan Payload. Untuk dipakai di handler
+// penerima webhook.
+func VerifyRequest(r *http.Request, secret string) (*Payload, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPayloadBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook body: %v", err)
+	}
+	if err := Verify(secret, r.Header.Get(HeaderSignature), body, time.Now(), DefaultTolerance); err != nil {
+		return nil, err
+	}
+	return ParsePayload(body)
+}
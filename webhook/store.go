@@ -0,0 +1,248 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Store menyimpan subscription dan delivery (termasuk dead-letter) ke file
+// JSON. Path kosong berarti hanya di memori.
+type Store struct {
+	path          string
+	mu            sync.Mutex
+	subscriptions map[string]*Subscription
+	deliveries    map[string]*Delivery
+}
+
+// storeFile isi file Store
+type storeFile struct {
+	Subscriptions []*Subscription `json:"subscriptions"`
+	Deliveries    []*Delivery     `json:"deliveries"`
+}
+
+// OpenStore membuka (atau membuat) store di path yang diberikan
+func OpenStore(path string) (*Store, error) {
+	st := &Store{
+		path:          path,
+		subscriptions: make(map[string]*Subscription),
+		deliveries:    make(map[string]*Delivery),
+	}
+	if path == "" {
+		return st, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return st, nil
+		}
+		return nil, fmt.Errorf("failed to read webhook store: %v", err)
+	}
+	if len(data) == 0 {
+		return st, nil
+	}
+
+	var file storeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook store: %v", err)
+	}
+	for _, sub := range file.Subscriptions {
+		st.subscriptions[sub.ID] = sub
+	}
+	for _, delivery := range file.Deliveries {
+		st.deliveries[delivery.ID] = delivery
+	}
+	return st, nil
+}
+
+// Path lokasi file store
+func (st *Store) Path() string {
+	return st.path
+}
+
+func (st *Store) addSubscription(sub *Subscription) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.subscriptions[sub.ID] = sub
+	return st.save()
+}
+
+// removeSubscription hapus subscription, delivery-nya tetap disimpan
+func (st *Store) removeSubscription(id string) (bool, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if _, ok := st.subscriptions[id]; !ok {
+		return false, nil
+	}
+	delete(st.subscriptions, id)
+	return true, st.save()
+}
+
+func (st *Store) subscription(id string) (Subscription, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	sub, ok := st.subscriptions[id]
+	if !ok {
+		return Subscription{}, false
+	}
+	return *sub, true
+}
+
+// subscriptionList salinan semua subscription, urut waktu dibuat
+func (st *Store) subscriptionList() []Subscription {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	subs := make([]Subscription, 0, len(st.subscriptions))
+	for _, sub := range st.subscriptions {
+		subs = append(subs, *sub)
+	}
+	sort.Slice(subs, func(a, b int) bool {
+		return subs[a].CreatedAt.Before(subs[b].CreatedAt)
+	})
+	return subs
+}
+
+// addDelivery simpan delivery baru, false kalau ID sudah ada (event yang
+// sama sudah pernah dijadwalkan untuk subscription ini)
+func (st *Store) addDelivery(delivery *Delivery) (bool, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if _, exists := st.deliveries[delivery.ID]; exists {
+		return false, nil
+	}
+	st.deliveries[delivery.ID] = delivery
+	return true, st.save()
+}
+
+// updateDelivery menjalankan update pada delivery lalu menyimpan store
+func (st *Store) updateDelivery(id string, update func(*Delivery)) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	delivery, ok := st.deliveries[id]
+	if !ok {
+		return fmt.Errorf("delivery %s not found", id)
+	}
+	update(delivery)
+	return st.save()
+}
+
+func (st *Store) delivery(id string) (Delivery, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	delivery, ok := st.deliveries[id]
+	if !ok {
+		return Delivery{}, false
+	}
+	return copyDelivery(delivery), true
+}
+
+// DeliveryFilter filter Deliveries. Field kosong tidak memfilter.
+type DeliveryFilter struct {
+	SubscriptionID string
+	Status         string
+	EnvelopeID     string
+	Limit          int // 0 berarti semua
+}
+
+// deliveryList delivery yang cocok dengan filter, terbaru dulu
+func (st *Store) deliveryList(filter DeliveryFilter) []Delivery {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	var deliveries []Delivery
+	for _, delivery := range st.deliveries {
+		if filter.SubscriptionID != "" && delivery.SubscriptionID != filter.SubscriptionID {
+			continue
+		}
+		if filter.Status != "" && delivery.Status != filter.Status {
+			continue
+		}
+		if filter.EnvelopeID != "" && delivery.Event.EnvelopeID != filter.EnvelopeID {
+			continue
+		}
+		deliveries = append(deliveries, copyDelivery(delivery))
+	}
+	sort.Slice(deliveries, func(a, b int) bool {
+		if !deliveries[a].CreatedAt.Equal(deliveries[b].CreatedAt) {
+			return deliveries[a].CreatedAt.After(deliveries[b].CreatedAt)
+		}
+		return deliveries[a].ID > deliveries[b].ID
+	})
+	if filter.Limit > 0 && len(deliveries) > filter.Limit {
+		deliveries = deliveries[:filter.Limit]
+	}
+	return deliveries
+}
+
+// due delivery pending yang jadwalnya sudah lewat, dan jadwal pending
+// berikutnya (zero kalau tidak ada)
+func (st *Store) due(now time.Time, skip map[string]bool) ([]Delivery, time.Time) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	var due []Delivery
+	var next time.Time
+	for _, delivery := range st.deliveries {
+		if delivery.Status != DeliveryPending || skip[delivery.ID] {
+			continue
+		}
+		if !delivery.NextAttemptAt.After(now) {
+			due = append(due, copyDelivery(delivery))
+		} else if next.IsZero() || delivery.NextAttemptAt.Before(next) {
+			next = delivery.NextAttemptAt
+		}
+	}
+	sort.Slice(due, func(a, b int) bool {
+		return due[a].NextAttemptAt.Before(due[b].NextAttemptAt)
+	})
+	return due, next
+}
+
+func copyDelivery(delivery *Delivery) Delivery {
+	out := *delivery
+	out.Attempts = append([]Attempt(nil), delivery.Attempts...)
+	return out
+}
+
+// save menulis store secara atomic (tmp file + rename). Caller harus pegang lock.
+func (st *Store) save() error {
+	if st.path == "" {
+		return nil
+	}
+
+	file := storeFile{
+		Subscriptions: make([]*Subscription, 0, len(st.subscriptions)),
+		Deliveries:    make([]*Delivery, 0, len(st.deliveries)),
+	}
+	for _, sub := range st.subscriptions {
+		file.Subscriptions = append(file.Subscriptions, sub)
+	}
+	for _, delivery := range st.deliveries {
+		file.Deliveries = append(file.Deliveries, delivery)
+	}
+	sort.Slice(file.Subscriptions, func(a, b int) bool {
+		return file.Subscriptions[a].CreatedAt.Before(file.Subscriptions[b].CreatedAt)
+	})
+	sort.Slice(file.Deliveries, func(a, b int) bool {
+		return file.Deliveries[a].CreatedAt.Before(file.Deliveries[b].CreatedAt)
+	})
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook store: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(st.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+	tmp := st.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write webhook store: %v", err)
+	}
+	if err := os.Rename(tmp, st.path); err != nil {
+		return fmt.Errorf("failed to replace %s: %v", st.path, err)
+	}
+	return nil
+}
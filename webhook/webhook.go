@@ -0,0 +1,79 @@
+// Package webhook mengirim event envelope dari Indexer ke URL subscriber,
+// ditandatangani HMAC dan di-retry dengan exponential backoff. Receiver
+// memverifikasi dengan Verify / VerifyRequest.
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"rpcsol/redenvelope"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Status delivery
+const (
+	DeliveryPending   = "pending"   // Menunggu attempt berikutnya
+	DeliveryDelivered = "delivered" // Receiver menjawab 2xx
+	DeliveryDead      = "dead"      // Gagal sampai MaxAttempts, masuk dead-letter
+)
+
+// Subscription satu URL penerima beserta filternya. Event sebelum
+// FromBlock (block berikutnya saat subscription dibuat) tidak dikirim,
+// supaya indexer yang membangun ulang index tidak mengirim riwayat lama.
+type Subscription struct {
+	ID        string                      `json:"id"`
+	URL       string                      `json:"url"`
+	Secret    string                      `json:"secret,omitempty"`
+	Filter    redenvelope.EventFilterJSON `json:"filter"`
+	Owner     common.Address              `json:"owner,omitzero"` // Address yang mendaftarkan, kosong kalau tanpa login
+	FromBlock uint64                      `json:"fromBlock"`
+	CreatedAt time.Time                   `json:"createdAt"`
+}
+
+// Attempt satu percobaan pengiriman
+type Attempt struct {
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"statusCode,omitempty"` // 0 kalau request gagal sebelum ada response
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"durationMs"`
+}
+
+// Delivery satu event untuk satu subscription beserta riwayat attempt-nya
+type Delivery struct {
+	ID             string                 `json:"id"` // <subscriptionId>-<block>-<logIndex>
+	SubscriptionID string                 `json:"subscriptionId"`
+	Event          *redenvelope.EventJSON `json:"event"`
+	Status         string                 `json:"status"`
+	Attempts       []Attempt              `json:"attempts"`
+	Failures       int                    `json:"failures"` // Attempt gagal berturut-turut, direset oleh Redeliver
+	NextAttemptAt  time.Time              `json:"nextAttemptAt,omitzero"`
+	CreatedAt      time.Time              `json:"createdAt"`
+	UpdatedAt      time.Time              `json:"updatedAt"`
+}
+
+// Payload body JSON yang dikirim ke receiver
+type Payload struct {
+	DeliveryID     string                 `json:"deliveryId"`
+	SubscriptionID string                 `json:"subscriptionId"`
+	Event          *redenvelope.EventJSON `json:"event"`
+}
+
+// ParsePayload decode body delivery
+func ParsePayload(body []byte) (*Payload, error) {
+	var payload Payload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook payload: %v", err)
+	}
+	if payload.Event == nil {
+		return nil, fmt.Errorf("webhook payload has no event")
+	}
+	return &payload, nil
+}
+
+// eventID posisi event, <block>-<logIndex>
+func eventID(event redenvelope.EnvelopeEvent) string {
+	return fmt.Sprintf("%d-%d", event.BlockNumber, event.LogIndex)
+}
@@ -0,0 +1,303 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"rpcsol/redenvelope"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"deliveryId":"x"}`)
+	now := time.Unix(1700000000, 0)
+	header := Sign("secret", now, body)
+
+	if err := Verify("secret", header, body, now.Add(time.Minute), 0); err != nil {
+		t.Fatalf("Expected valid signature, got %v", err)
+	}
+	if err := Verify("other", header, body, now, 0); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for wrong secret, got %v", err)
+	}
+	if err := Verify("secret", header, []byte(`{"deliveryId":"y"}`), now, 0); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for tampered body, got %v", err)
+	}
+	if err := Verify("secret", header, body, now.Add(time.Hour), 0); !errors.Is(err, ErrSignatureExpired) {
+		t.Errorf("Expected ErrSignatureExpired for old signature, got %v", err)
+	}
+	if err := Verify("secret", "v1=abcd", body, now, 0); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature without timestamp, got %v", err)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 10: 5 * time.Second} {
+		if got := policy.Backoff(attempt); got != want {
+			t.Errorf("Backoff(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func claimedEvent(id int64, block uint64, claimer common.Address) redenvelope.EnvelopeEvent {
+	return redenvelope.EnvelopeEvent{
+		Name:        redenvelope.EventEnvelopeClaimed,
+		EnvelopeID:  big.NewInt(id),
+		BlockNumber: block,
+		TxHash:      common.BigToHash(new(big.Int).SetUint64(block)),
+		Claimed: &redenvelope.EnvelopeClaimedEvent{
+			EnvelopeId: big.NewInt(id),
+			Claimer:    claimer,
+			Payout:     big.NewInt(100),
+		},
+	}
+}
+
+// waitDelivery menunggu sampai delivery mencapai status
+func waitDelivery(t *testing.T, d *Dispatcher, id, status string) Delivery {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if delivery, ok := d.Delivery(id); ok && delivery.Status == status {
+			return delivery
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	delivery, _ := d.Delivery(id)
+	t.Fatalf("Delivery %s did not reach %s: %+v", id, status, delivery)
+	return Delivery{}
+}
+
+func TestDispatcher_RetriesAndDeadLetter(t *testing.T) {
+	var calls atomic.Int32
+	var failing atomic.Bool
+	const secret = "receiver-secret"
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, err := VerifyRequest(r, secret)
+		if err != nil {
+			t.Errorf("Receiver rejected delivery: %v", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if payload.Event.EnvelopeID != "7" || r.Header.Get(HeaderEvent) != redenvelope.EventEnvelopeClaimed {
+			t.Errorf("Unexpected payload %+v", payload)
+		}
+		// Attempt pertama selalu gagal, lalu ikuti flag failing
+		if calls.Add(1) == 1 || failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	store, err := OpenStore(filepath.Join(t.TempDir(), "webhooks.json"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	indexer := redenvelope.NewIndexer(&redenvelope.RedEnvelopeService{})
+	dispatcher, err := NewDispatcher(store, indexer, WithRetryPolicy(RetryPolicy{
+		MaxAttempts:    2,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+	}), WithAllowedNetworks(netip.MustParsePrefix("127.0.0.0/8")))
+	if err != nil {
+		t.Fatalf("Failed to create dispatcher: %v", err)
+	}
+
+	envelopeID := "7"
+	sub, err := dispatcher.Subscribe(receiver.URL, secret, redenvelope.EventFilterJSON{
+		Events:     []string{redenvelope.EventEnvelopeClaimed},
+		EnvelopeID: envelopeID,
+	})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go dispatcher.Run(ctx)
+	time.Sleep(50 * time.Millisecond) // Tunggu Run subscribe ke indexer
+
+	claimer := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	indexer.Apply(claimedEvent(8, 1, claimer)) // Envelope lain, tidak cocok filter
+	indexer.Apply(claimedEvent(7, 2, claimer))
+
+	first := waitDelivery(t, dispatcher, sub.ID+"-2-0", DeliveryDelivered)
+	if len(first.Attempts) != 2 || first.Attempts[0].StatusCode != http.StatusServiceUnavailable || first.Attempts[1].StatusCode != http.StatusNoContent {
+		t.Errorf("Expected one retry before delivery, got %+v", first.Attempts)
+	}
+
+	failing.Store(true)
+	indexer.Apply(claimedEvent(7, 3, claimer))
+	dead := waitDelivery(t, dispatcher, sub.ID+"-3-0", DeliveryDead)
+	if len(dead.Attempts) != 2 || dead.Failures != 2 {
+		t.Errorf("Expected 2 failed attempts, got %+v", dead.Attempts)
+	}
+	if letters := dispatcher.Deliveries(DeliveryFilter{Status: DeliveryDead}); len(letters) != 1 || letters[0].ID != dead.ID {
+		t.Errorf("Expected one dead letter, got %+v", letters)
+	}
+
+	failing.Store(false)
+	if err := dispatcher.Redeliver(dead.ID); err != nil {
+		t.Fatalf("Failed to redeliver: %v", err)
+	}
+	waitDelivery(t, dispatcher, dead.ID, DeliveryDelivered)
+
+	// Riwayat tersimpan di file dan dibaca ulang setelah restart
+	reopened, err := OpenStore(store.Path())
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	history := reopened.deliveryList(DeliveryFilter{SubscriptionID: sub.ID, EnvelopeID: envelopeID})
+	if len(history) != 2 || history[0].ID != dead.ID || len(history[0].Attempts) != 3 {
+		t.Errorf("Unexpected history after reopen: %+v", history)
+	}
+	if subs := reopened.subscriptionList(); len(subs) != 1 || subs[0].Secret != secret {
+		t.Errorf("Expected subscription with secret after reopen, got %+v", subs)
+	}
+}
+
+func TestDispatcher_SkipsEventsBeforeSubscription(t *testing.T) {
+	indexer := redenvelope.NewIndexer(&redenvelope.RedEnvelopeService{})
+	claimer := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	indexer.Apply(claimedEvent(1, 5, claimer))
+
+	store, _ := OpenStore("")
+	dispatcher, err := NewDispatcher(store, indexer)
+	if err != nil {
+		t.Fatalf("Failed to create dispatcher: %v", err)
+	}
+	sub, err := dispatcher.Subscribe("https://example.com/hook", "", redenvelope.EventFilterJSON{})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	if sub.FromBlock != 6 || len(sub.Secret) != 64 {
+		t.Errorf("Unexpected subscription %+v", sub)
+	}
+	if listed, _ := dispatcher.Subscription(sub.ID); listed.Secret != "" {
+		t.Error("Secret should not be returned after creation")
+	}
+
+	// Index dibangun ulang dari awal: event lama tidak dijadwalkan lagi
+	if err := dispatcher.schedule(claimedEvent(1, 5, claimer)); err != nil {
+		t.Fatalf("Failed to schedule: %v", err)
+	}
+	if deliveries := dispatcher.Deliveries(DeliveryFilter{}); len(deliveries) != 0 {
+		t.Errorf("Expected no deliveries for old events, got %+v", deliveries)
+	}
+
+	if _, err := dispatcher.Subscribe("ftp://example.com", "", redenvelope.EventFilterJSON{}); err == nil {
+		t.Error("Expected error for non-http URL")
+	}
+	if _, err := dispatcher.Subscribe("https://example.com", "", redenvelope.EventFilterJSON{Events: []string{"Lost"}}); err == nil {
+		t.Error("Expected error for unknown event")
+	}
+}
+
+func TestDispatcher_ContinuesAfterScheduleError(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	dir := filepath.Join(t.TempDir(), "store")
+	store, err := OpenStore(filepath.Join(dir, "webhooks.json"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	indexer := redenvelope.NewIndexer(&redenvelope.RedEnvelopeService{})
+	dispatcher, err := NewDispatcher(store, indexer, WithAllowedNetworks(netip.MustParsePrefix("127.0.0.0/8")))
+	if err != nil {
+		t.Fatalf("Failed to create dispatcher: %v", err)
+	}
+	sub, err := dispatcher.Subscribe(receiver.URL, "", redenvelope.EventFilterJSON{})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runErr := make(chan error, 1)
+	go func() { runErr <- dispatcher.Run(ctx) }()
+	time.Sleep(50 * time.Millisecond) // Tunggu Run subscribe ke indexer
+
+	// Direktori store diganti file: menyimpan delivery gagal
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dir, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	claimer := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	indexer.Apply(claimedEvent(7, 2, claimer))
+	time.Sleep(50 * time.Millisecond)
+
+	if err := os.Remove(dir); err != nil {
+		t.Fatal(err)
+	}
+	indexer.Apply(claimedEvent(7, 3, claimer))
+	waitDelivery(t, dispatcher, sub.ID+"-3-0", DeliveryDelivered)
+
+	select {
+	case err := <-runErr:
+		t.Fatalf("Run stopped after a schedule error: %v", err)
+	default:
+	}
+}
+
+func TestSubscribe_RejectsNonPublicDestinations(t *testing.T) {
+	store, _ := OpenStore("")
+	dispatcher, err := NewDispatcher(store, redenvelope.NewIndexer(&redenvelope.RedEnvelopeService{}))
+	if err != nil {
+		t.Fatalf("Failed to create dispatcher: %v", err)
+	}
+	for _, rawURL := range []string{
+		"http://127.0.0.1:8545/",
+		"http://localhost/hook",
+		"http://[::1]/hook",
+		"http://10.0.0.5/hook",
+		"http://192.168.1.1/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://100.64.0.1/hook",
+		"http://0.0.0.0/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+	} {
+		if _, err := dispatcher.Subscribe(rawURL, "", redenvelope.EventFilterJSON{}); !errors.Is(err, ErrForbiddenDestination) {
+			t.Errorf("%s: expected ErrForbiddenDestination, got %v", rawURL, err)
+		}
+	}
+	if _, err := dispatcher.Subscribe("https://203.0.113.10/hook", "", redenvelope.EventFilterJSON{}); err != nil {
+		t.Errorf("Public address should be accepted: %v", err)
+	}
+
+	// Dialer ikut menolak, misalnya host yang di-resolve ulang ke loopback
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer receiver.Close()
+	if _, err := dispatcher.client.Get(receiver.URL); !errors.Is(err, ErrForbiddenDestination) {
+		t.Errorf("Expected dial to loopback to be rejected, got %v", err)
+	}
+
+	allowed, err := NewDispatcher(store, redenvelope.NewIndexer(&redenvelope.RedEnvelopeService{}),
+		WithAllowedNetworks(netip.MustParsePrefix("127.0.0.0/8")))
+	if err != nil {
+		t.Fatalf("Failed to create dispatcher: %v", err)
+	}
+	if _, err := allowed.Subscribe(receiver.URL, "", redenvelope.EventFilterJSON{}); err != nil {
+		t.Errorf("Allowed network should be accepted: %v", err)
+	}
+	resp, err := allowed.client.Get(receiver.URL)
+	if err != nil {
+		t.Fatalf("Expected dial to allowed network to succeed: %v", err)
+	}
+	resp.Body.Close()
+}